@@ -1,115 +1,250 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"database/sql"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // База часовых поясов для расписаний кнопок
 
+	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq" // PostgreSQL driver
-)
-
-type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
-}
-
-func NewDatabaseConnection() (*sql.DB, error) {
-    config := DatabaseConfig{
-        Host:     getEnvWithDefault("DB_HOST", "localhost"),
-        Port:     getEnvWithDefault("DB_PORT", "5432"),
-        User:     getEnvWithDefault("DB_USER", "mvp_user"),  // Changed default to mvp_user
-        Password: getEnvWithDefault("DB_PASSWORD", "352535"),
-        Name:     getEnvWithDefault("DB_NAME", "mvp_db"),
-    }
-
-	if config.Password == "" {
-		return nil, fmt.Errorf("database password is required")
-	}
 
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		config.Host, config.Port, config.User, config.Password, config.Name)
+	"mvp_multylink/backend/internal/blobstore"
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/config"
+	"mvp_multylink/backend/internal/domains"
+	"mvp_multylink/backend/internal/handlers"
+	"mvp_multylink/backend/internal/linkcheck"
+	"mvp_multylink/backend/internal/logging"
+	"mvp_multylink/backend/internal/middleware"
+	"mvp_multylink/backend/internal/migrations"
+	"mvp_multylink/backend/internal/monitoring"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+	"mvp_multylink/backend/internal/signing"
+	"mvp_multylink/backend/internal/unfurl"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
 
-	db, err := sql.Open("postgres", connStr)
+// NewDatabaseConnection открывает пул соединений с PostgreSQL по настройкам из конфигурации
+func NewDatabaseConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	return db, nil
 }
 
 func main() {
-	fmt.Println("Starting MultyLink API server...")
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to YAML config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fatal(logging.New("info"), "configuration error", err)
+	}
+
+	logger := logging.New(cfg.Log.Level)
+	slog.SetDefault(logger)
+
+	logger.Info("starting MultyLink API server")
+	logger.Info("effective configuration", "config", cfg.String())
 
 	// Initialize database connection
-	db, err := NewDatabaseConnection()
+	db, err := NewDatabaseConnection(cfg.Database)
 	if err != nil {
-		log.Fatalf("Database connection error: %v", err)
+		fatal(logger, "database connection error", err)
 	}
 	defer db.Close()
 
-	// Test connection with retries
-	if err := retryDBConnection(db); err != nil {
-		log.Fatalf("Failed to connect to database after retries: %v", err)
+	// Подключение к базе данных проверяется в фоне, чтобы не блокировать старт:
+	// пока база недоступна, /readyz отвечает 503
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go prepareDatabase(bgCtx, logger, db, cfg.Database.AutoMigrate)
+
+	metrics := monitoring.New()
+	metrics.RegisterDB(db, cfg.Database.Name)
+
+	// Initialize repositories and services
+	uow := repository.NewPostgresUnitOfWork(db)
+	multiLinkRepo := repository.NewPostgresMultiLinkRepository(db, cfg.Database.QueryTimeout)
+	buttonRepo := repository.NewPostgresButtonRepository(db, cfg.Database.QueryTimeout)
+	metricsRepo := repository.NewPostgresMetricsRepository(db, cfg.Database.QueryTimeout)
+	revisionRepo := repository.NewPostgresRevisionRepository(db, cfg.Database.QueryTimeout)
+	draftRepo := repository.NewPostgresDraftRepository(db, cfg.Database.QueryTimeout)
+	blocklistRepo := repository.NewPostgresBlocklistRepository(db, cfg.Database.QueryTimeout)
+	linkHealthRepo := repository.NewPostgresLinkHealthRepository(db, cfg.Database.QueryTimeout)
+	notificationRepo := repository.NewPostgresNotificationRepository(db, cfg.Database.QueryTimeout)
+	customDomainRepo := repository.NewPostgresCustomDomainRepository(db, cfg.Database.QueryTimeout)
+	profileRepo := repository.NewPostgresProfileRepository(db, cfg.Database.QueryTimeout)
+
+	urlPolicy := urlpolicy.New(cfg.URLPolicy.AllowedSchemes, cfg.URLPolicy.MaxLength)
+	blockedDomains := blocklist.New(cfg.Blocklist.Path)
+	if _, err := blockedDomains.Reload(); err != nil {
+		fatal(logger, "blocklist load error", err)
 	}
-	log.Println("Successfully connected to database")
 
-	// TODO: Initialize router
-	// TODO: Initialize services
+	imageStore, err := newBlobStore(cfg.Uploads)
+	if err != nil {
+		fatal(logger, "image storage error", err)
+	}
+	imageService := services.NewImageService(imageStore, cfg.Public.APIBaseURL)
 
-	// Simple health check endpoint for now
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	})
+	revisionService := services.NewRevisionService(uow, multiLinkRepo, buttonRepo, metricsRepo, revisionRepo, urlPolicy, blockedDomains, imageService)
+	multiLinkService := services.NewMultiLinkService(uow, multiLinkRepo, buttonRepo, metricsRepo, draftRepo, revisionService, blockedDomains, clock.Real{})
+	buttonService := services.NewButtonService(uow, buttonRepo, metricsRepo, clock.Real{})
+	metricsService := services.NewMetricsService(metricsRepo, buttonRepo)
+	draftService := services.NewDraftService(uow, multiLinkRepo, buttonRepo, metricsRepo, draftRepo, revisionService,
+		signing.NewSigner(cfg.Auth.JWTSecret, "draft-preview"), cfg.Drafts.PreviewTTL, urlPolicy, blockedDomains, imageService)
+	trashService := services.NewTrashService(uow, multiLinkRepo, buttonRepo, multiLinkService, buttonService)
+	blocklistService := services.NewBlocklistService(blocklistRepo, multiLinkRepo, buttonRepo, blockedDomains)
+	notificationService := services.NewNotificationService(notificationRepo)
+	linkHealthService := services.NewLinkHealthService(uow, linkHealthRepo, notificationService,
+		linkcheck.New(linkcheck.Options{
+			Timeout:      cfg.LinkCheck.Timeout,
+			MaxRedirects: cfg.LinkCheck.MaxRedirects,
+			UserAgent:    cfg.LinkCheck.UserAgent,
+		}),
+		clock.Real{},
+		services.LinkHealthOptions{
+			Concurrency:      cfg.LinkCheck.Concurrency,
+			HostDelay:        cfg.LinkCheck.HostDelay,
+			BrokenAfter:      cfg.LinkCheck.BrokenAfter,
+			HistoryRetention: cfg.LinkCheck.HistoryRetention,
+		})
+	unfurlService := services.NewUnfurlService(
+		unfurl.New(unfurl.Options{
+			Timeout:      cfg.Unfurl.Timeout,
+			MaxBodyBytes: cfg.Unfurl.MaxBodyBytes,
+			MaxRedirects: cfg.Unfurl.MaxRedirects,
+			UserAgent:    cfg.Unfurl.UserAgent,
+			CacheTTL:     cfg.Unfurl.CacheTTL,
+			CacheSize:    cfg.Unfurl.CacheSize,
+		}),
+		urlPolicy, blockedDomains)
+	protectionService := services.NewProtectionService(multiLinkRepo,
+		signing.NewSigner(cfg.Auth.JWTSecret, "multilink-unlock"), clock.Real{},
+		services.ProtectionOptions{
+			UnlockTTL:       cfg.Passwords.UnlockTTL,
+			MaxAttempts:     cfg.Passwords.MaxAttempts,
+			MaxSlugAttempts: cfg.Passwords.MaxSlugAttempts,
+			AttemptWindow:   cfg.Passwords.AttemptWindow,
+		})
+	customDomainService := services.NewCustomDomainService(customDomainRepo,
+		domains.NewVerifier(net.DefaultResolver, cfg.Domains.VerifyTimeout, false),
+		cfg.Domains.PrimaryHosts, cfg.Domains.CacheSize, cfg.Domains.CacheTTL, clock.Real{})
+	qrService := services.NewQRService(unfurlService, cfg.Public.PageBaseURL, cfg.Public.APIBaseURL)
+	ogCardService := services.NewOGCardService(multiLinkService, profileRepo, unfurlService,
+		cfg.Public.APIBaseURL, cfg.OGCards.CacheSize, cfg.OGCards.CacheTTL)
+	authService := services.NewAuthService(cfg.Auth.JWTSecret, cfg.Auth.TokenDuration)
+
+	// Окончательное удаление записей из корзины по истечении срока хранения
+	go trashService.RunPurger(logging.WithLogger(bgCtx, logger), cfg.Trash.PurgeInterval, cfg.Trash.Retention)
+
+	// Перезагрузка файла списка блокировки и записей администраторов
+	go blocklistService.RunReloader(logging.WithLogger(bgCtx, logger), cfg.Blocklist.ReloadInterval)
+
+	// Фоновая проверка доступности ссылок кнопок
+	if cfg.LinkCheck.Enabled {
+		go linkHealthService.RunChecker(logging.WithLogger(bgCtx, logger), cfg.LinkCheck.Interval)
+	}
+
+	corsPolicies, err := newCORSPolicies(cfg.CORS)
+	if err != nil {
+		fatal(logger, "CORS configuration error", err)
+	}
+
+	router := gin.New()
+	// Адрес посетителя нужен для учета неверных паролей и событий кликов.
+	// X-Forwarded-For принимается только от известных прокси
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		fatal(logger, "trusted proxies configuration error", err)
+	}
+	router.Use(
+		middleware.RequestIDMiddleware(logger),
+		middleware.AccessLogMiddleware(),
+		metrics.HTTPMiddleware(),
+		gin.Recovery(),
+		middleware.RouteCORSMiddleware(corsPolicies...),
+	)
+
+	healthHandler := handlers.NewHealthHandler(db, cfg.Server.HealthCheckTimeout)
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/version", healthHandler.Version)
+
+	registerAPIRoutes(router, apiHandlers{
+		multiLink:    handlers.NewMultiLinkHandler(multiLinkService, draftService, protectionService, ogCardService),
+		button:       handlers.NewButtonHandler(multiLinkService, draftService, unfurlService),
+		metrics:      handlers.NewMetricsHandler(multiLinkService, buttonService, metricsService, metrics, urlPolicy, blockedDomains, protectionService),
+		trash:        handlers.NewTrashHandler(trashService),
+		revision:     handlers.NewRevisionHandler(multiLinkService, revisionService),
+		draft:        handlers.NewDraftHandler(multiLinkService, draftService),
+		blocklist:    handlers.NewBlocklistHandler(blocklistService),
+		linkHealth:   handlers.NewLinkHealthHandler(multiLinkService, buttonService, linkHealthService),
+		notification: handlers.NewNotificationHandler(notificationService),
+		unfurl:       handlers.NewUnfurlHandler(unfurlService),
+		protection:   handlers.NewProtectionHandler(multiLinkService, protectionService, metrics),
+		sensitive:    handlers.NewSensitiveHandler(multiLinkService),
+		customDomain: handlers.NewCustomDomainHandler(multiLinkService, customDomainService),
+		qr:           handlers.NewQRHandler(multiLinkService, buttonService, qrService),
+		theme:        handlers.NewThemeHandler(multiLinkService),
+		image:        handlers.NewImageHandler(imageService, cfg.Uploads.MaxSize),
+		icon:         handlers.NewIconHandler(cfg.Public.APIBaseURL),
+	}, middleware.NewAuthMiddleware(authService, metrics), cfg.Server.RequestTimeout)
 
 	// Add root route handler
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/" {
-			http.NotFound(w, r)
-			return
-		}
-		w.Write([]byte("MultyLink API is running"))
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "MultyLink API is running")
 	})
 
 	// Add register route handler
-	http.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
-	    if r.Method != "POST" {
-	        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	        return
-	    }
-	
-	    // TODO: Add actual registration logic
-	    w.Write([]byte(`{"status":"registration endpoint works"}`))
+	router.POST("/register", func(c *gin.Context) {
+		// TODO: Add actual registration logic
+		c.JSON(http.StatusOK, gin.H{"status": "registration endpoint works"})
 	})
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	var metricsSrv *http.Server
+	if cfg.Metrics.Enabled {
+		metricsSrv = setupMetricsEndpoint(logger, cfg.Metrics, metrics, router)
+	}
+
+	// Запросы к пользовательским доменам направляются на их мультиссылки до маршрутизации по slug
+	handler := middleware.HostRouting(logger, customDomainService, router)
+
+	var tlsSrv *http.Server
+	if cfg.Domains.TLSListenAddr != "" {
+		tlsSrv = setupCustomDomainTLS(logger, cfg.Domains, cfg.Server, handler)
 	}
 
 	srv := &http.Server{
-		Addr:         ":" + port,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      handler,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Server starting on port %s", port)
+		logger.Info("server starting", "port", cfg.Server.Port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed to start: %v", err)
+			fatal(logger, "server failed to start", err)
 		}
 	}()
 
@@ -117,29 +252,141 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Server shutting down...")
+	logger.Info("server shutting down")
+	stopBackground()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("server forced to shutdown", "error", err)
+	}
+	if tlsSrv != nil {
+		if err := tlsSrv.Shutdown(ctx); err != nil {
+			logger.Error("TLS server forced to shutdown", "error", err)
+		}
+	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			logger.Error("metrics server forced to shutdown", "error", err)
+		}
+	}
+
+	logger.Info("server exited properly")
+}
 
-	// TODO: Close database connection and other resources
+// prepareDatabase ждет доступности базы данных с экспоненциальной задержкой
+// и применяет миграции. Завершается при отмене ctx
+func prepareDatabase(ctx context.Context, logger *slog.Logger, db *sql.DB, autoMigrate bool) {
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
 
-	log.Println("Server exited properly")
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil && autoMigrate {
+			err = migrations.Migrate(ctx, db)
+		}
+		if err == nil {
+			logger.Info("database is ready", "schema_version", migrations.LatestVersion())
+			return
+		}
+
+		logger.Warn("database is not ready", "attempt", attempt, "retry_in", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
 }
 
-func retryDBConnection(db *sql.DB) error {
-	var dbErr error
-	for i := 0; i < 5; i++ {
-		dbErr = db.Ping()
-		if dbErr == nil {
-			return nil
+// setupMetricsEndpoint публикует /metrics: на отдельном адресе, если он задан,
+// иначе на основном роутере под basic auth. Возвращает отдельный сервер или nil
+func setupMetricsEndpoint(logger *slog.Logger, cfg config.MetricsConfig, metrics *monitoring.Metrics, router *gin.Engine) *http.Server {
+	if cfg.ListenAddr == "" {
+		router.GET("/metrics", gin.BasicAuth(gin.Accounts{cfg.Username: cfg.Password}), gin.WrapH(metrics.Handler()))
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	srv := &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		logger.Info("metrics server starting", "addr", cfg.ListenAddr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("metrics server failed", "error", err)
+		}
+	}()
+
+	return srv
+}
+
+// setupCustomDomainTLS запускает HTTPS-сервер для пользовательских доменов.
+// Сертификат выбирается по SNI из каталога cert_dir при каждом подключении,
+// поэтому новые и обновленные сертификаты подхватываются без перезапуска
+func setupCustomDomainTLS(logger *slog.Logger, cfg config.DomainsConfig, serverCfg config.ServerConfig, handler http.Handler) *http.Server {
+	certs := domains.NewCertStore(cfg.CertDir)
+	srv := &http.Server{
+		Addr:         cfg.TLSListenAddr,
+		Handler:      handler,
+		ReadTimeout:  serverCfg.ReadTimeout,
+		WriteTimeout: serverCfg.WriteTimeout,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		},
+	}
+
+	go func() {
+		logger.Info("TLS server starting", "addr", cfg.TLSListenAddr, "cert_dir", cfg.CertDir)
+		if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			logger.Error("TLS server failed", "error", err)
 		}
-		log.Printf("Attempt %d: Database ping failed: %v", i+1, dbErr)
-		time.Sleep(2 * time.Second)
+	}()
+
+	return srv
+}
+
+// newBlobStore создает хранилище загруженных изображений по настройкам
+func newBlobStore(cfg config.UploadsConfig) (blobstore.BlobStore, error) {
+	if cfg.Storage == config.StorageS3 {
+		return blobstore.NewS3Store(blobstore.S3Options{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			UseSSL:    cfg.S3.UseSSL,
+			Timeout:   cfg.S3.Timeout,
+		}), nil
 	}
-	return fmt.Errorf("database connection failed after retries: %w", dbErr)
+	return blobstore.NewLocalStore(cfg.LocalDir)
 }
 
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// newCORSPolicies собирает CORS-политики: публичное API встраивания открыто
+// для всех, API личного кабинета доступно только с разрешенных источников
+func newCORSPolicies(cfg config.CORSConfig) ([]middleware.CORSPolicy, error) {
+	policies := []middleware.CORSPolicy{
+		{PathPrefix: "/api/public/", Config: middleware.PublicCORSConfig()},
+		{PathPrefix: "/api/", Config: middleware.DashboardCORSConfig(cfg.AllowedOrigins)},
 	}
-	return defaultValue
+	for _, policy := range policies {
+		if err := policy.Config.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+// fatal пишет ошибку в лог и завершает процесс
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/handlers"
+	"mvp_multylink/backend/internal/middleware"
+)
+
+// apiHandlers содержит обработчики маршрутов API
+type apiHandlers struct {
+	multiLink    *handlers.MultiLinkHandler
+	button       *handlers.ButtonHandler
+	metrics      *handlers.MetricsHandler
+	trash        *handlers.TrashHandler
+	revision     *handlers.RevisionHandler
+	draft        *handlers.DraftHandler
+	blocklist    *handlers.BlocklistHandler
+	linkHealth   *handlers.LinkHealthHandler
+	notification *handlers.NotificationHandler
+	unfurl       *handlers.UnfurlHandler
+	protection   *handlers.ProtectionHandler
+	sensitive    *handlers.SensitiveHandler
+	customDomain *handlers.CustomDomainHandler
+	qr           *handlers.QRHandler
+	theme        *handlers.ThemeHandler
+	image        *handlers.ImageHandler
+	icon         *handlers.IconHandler
+}
+
+// registerAPIRoutes регистрирует маршруты публичного API, API личного кабинета
+// и встроенных статических файлов
+func registerAPIRoutes(router *gin.Engine, h apiHandlers, auth *middleware.AuthMiddleware, requestTimeout time.Duration) {
+	timeout := middleware.TimeoutMiddleware(requestTimeout)
+
+	// Встроенный набор значков кнопок
+	router.GET("/static/icons/:file", h.icon.ServeIcon)
+
+	// Публичное API встраивания и переходы по кнопкам
+	public := router.Group("/api/public", timeout)
+	public.GET("/multilinks/:slug", h.multiLink.GetPublicMultiLink)
+	public.GET("/pages/:slug", h.multiLink.GetPublicPage)
+	public.GET("/multilinks/:slug/og.png", h.multiLink.GetPublicCard)
+	public.POST("/multilinks/:slug/unlock", h.protection.Unlock)
+	public.GET("/click/:button_id", h.metrics.RecordClick)
+	public.POST("/consent", h.sensitive.Acknowledge)
+	public.GET("/preview/:token", h.draft.GetPreview)
+	public.GET("/images/*key", h.image.Serve)
+
+	// API личного кабинета
+	api := router.Group("/api", timeout, auth.AuthRequired())
+
+	multiLinks := api.Group("/multilinks")
+	multiLinks.GET("", h.multiLink.GetUserMultiLinks)
+	multiLinks.POST("", h.multiLink.CreateMultiLink)
+	multiLinks.GET("/:id", h.multiLink.GetMultiLink)
+	multiLinks.PUT("/:id", h.multiLink.UpdateMultiLink)
+	multiLinks.DELETE("/:id", h.multiLink.DeleteMultiLink)
+	multiLinks.GET("/:id/qr", h.qr.GetMultiLinkQR)
+	multiLinks.PUT("/:id/theme", h.theme.UpdateTheme)
+	multiLinks.PUT("/:id/password", h.protection.SetPassword)
+	multiLinks.DELETE("/:id/password", h.protection.RemovePassword)
+
+	multiLinks.POST("/:id/buttons", h.button.CreateButton)
+	multiLinks.PUT("/:id/buttons/order", h.button.ReorderButtons)
+	multiLinks.PUT("/:id/buttons/:button_id", h.button.UpdateButton)
+	multiLinks.DELETE("/:id/buttons/:button_id", h.button.DeleteButton)
+	multiLinks.GET("/:id/buttons/:button_id/health", h.linkHealth.GetButtonHealth)
+	multiLinks.GET("/:id/buttons/:button_id/qr", h.qr.GetButtonQR)
+
+	multiLinks.GET("/:id/draft", h.draft.GetDraft)
+	multiLinks.DELETE("/:id/draft", h.draft.DiscardDraft)
+	multiLinks.POST("/:id/draft/publish", h.draft.PublishDraft)
+	multiLinks.POST("/:id/draft/preview", h.draft.CreatePreviewToken)
+
+	multiLinks.GET("/:id/metrics", h.metrics.GetMultiLinkMetrics)
+
+	multiLinks.GET("/:id/domains", h.customDomain.GetDomains)
+	multiLinks.POST("/:id/domains", h.customDomain.AddDomain)
+	multiLinks.POST("/:id/domains/:domain_id/verify", h.customDomain.VerifyDomain)
+	multiLinks.DELETE("/:id/domains/:domain_id", h.customDomain.DeleteDomain)
+
+	multiLinks.GET("/:id/revisions", h.revision.GetRevisions)
+	multiLinks.GET("/:id/revisions/:revision", h.revision.GetRevision)
+	multiLinks.POST("/:id/revisions/:revision/restore", h.revision.RestoreRevision)
+
+	api.POST("/unfurl", h.unfurl.Unfurl)
+	api.GET("/themes", h.theme.GetPresets)
+	api.POST("/images/:kind", h.image.Upload)
+	api.GET("/icons", h.icon.GetIcons)
+
+	trash := api.Group("/trash")
+	trash.GET("", h.trash.GetTrash)
+	trash.POST("/multilinks/:id/restore", h.trash.RestoreMultiLink)
+	trash.POST("/buttons/:button_id/restore", h.trash.RestoreButton)
+
+	notifications := api.Group("/notifications")
+	notifications.GET("", h.notification.GetNotifications)
+	notifications.POST("/:id/read", h.notification.MarkRead)
+
+	// API администратора
+	admin := router.Group("/api/admin", timeout, auth.AdminRequired())
+
+	blocked := admin.Group("/blocklist")
+	blocked.GET("", h.blocklist.GetBlockedDomains)
+	blocked.POST("", h.blocklist.AddBlockedDomain)
+	blocked.DELETE("/:id", h.blocklist.DeleteBlockedDomain)
+	blocked.POST("/reload", h.blocklist.ReloadBlocklist)
+	blocked.GET("/report", h.blocklist.GetReport)
+
+	admin.PUT("/multilinks/:id/sensitive", h.sensitive.ForceSensitive)
+	admin.DELETE("/multilinks/:id/sensitive", h.sensitive.UnforceSensitive)
+}
@@ -0,0 +1,51 @@
+// Package blobstore содержит хранилища загруженных файлов: локальный
+// каталог и S3-совместимое объектное хранилище
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNotFound возвращается, если объекта с таким ключом нет
+var ErrNotFound = errors.New("объект не найден")
+
+// ErrInvalidKey возвращается для ключа, который нельзя использовать как имя объекта
+var ErrInvalidKey = errors.New("некорректный ключ объекта")
+
+// Object представляет объект, открытый для чтения. Body закрывает вызывающий
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// BlobStore хранит объекты по ключу вида "каталог/имя.расширение"
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidKey проверяет, что ключ состоит из сегментов со строчными латинскими
+// буквами, цифрами, '-', '_' и '.' и не выходит за пределы хранилища
+func ValidKey(key string) bool {
+	if key == "" || len(key) > 256 {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+		for _, r := range segment {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+				return false
+			}
+		}
+	}
+	return true
+}
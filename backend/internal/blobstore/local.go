@@ -0,0 +1,102 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// LocalStore хранит объекты в каталоге файловой системы. Тип содержимого
+// определяется по расширению ключа
+type LocalStore struct {
+	dir string
+}
+
+var _ BlobStore = (*LocalStore)(nil)
+
+// NewLocalStore создает новый экземпляр LocalStore и при необходимости каталог
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put записывает объект. Файл сначала пишется во временный и затем
+// переименовывается, поэтому читатели не видят частично записанный объект
+func (s *LocalStore) Put(_ context.Context, key, _ string, data []byte) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	target := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// Get открывает объект для чтения
+func (s *LocalStore) Get(_ context.Context, key string) (Object, error) {
+	if !ValidKey(key) {
+		return Object{}, ErrNotFound
+	}
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Object{}, ErrNotFound
+	}
+	if err != nil {
+		return Object{}, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return Object{}, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return Object{}, ErrNotFound
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return Object{Body: f, ContentType: contentType, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Delete удаляет объект. Отсутствие объекта ошибкой не считается
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *LocalStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
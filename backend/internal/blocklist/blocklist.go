@@ -0,0 +1,247 @@
+// Package blocklist содержит список вредоносных доменов и ссылок. Записи
+// загружаются из локального файла в формате hosts или простого списка
+// и дополняются записями, добавленными администраторами
+package blocklist
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+// Источники записей списка
+const (
+	SourceFile  = "file"
+	SourceAdmin = "admin"
+)
+
+// ErrInvalidPattern возвращается для записи, которая не является доменом или ссылкой
+var ErrInvalidPattern = errors.New("некорректный домен или ссылка")
+
+// Match описывает запись списка, под которую попала ссылка
+type Match struct {
+	Pattern string `json:"pattern"`
+	Source  string `json:"source"`
+}
+
+// set содержит нормализованные записи одного источника: домены
+// и префиксы ссылок вида host/path
+type set struct {
+	domains  map[string]bool
+	prefixes []string
+}
+
+// newSet нормализует записи. Некорректные записи пропускаются: публичные
+// списки в формате hosts нередко содержат служебные имена
+func newSet(patterns []string) set {
+	s := set{domains: make(map[string]bool)}
+	for _, raw := range patterns {
+		pattern, err := Normalize(raw)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(pattern, "/") {
+			s.prefixes = append(s.prefixes, pattern)
+		} else {
+			s.domains[pattern] = true
+		}
+	}
+	return s
+}
+
+func (s set) match(host, hostPath string) (string, bool) {
+	// Домен блокирует и все свои поддомены
+	for domain := host; domain != ""; {
+		if s.domains[domain] {
+			return domain, true
+		}
+		i := strings.IndexByte(domain, '.')
+		if i < 0 {
+			break
+		}
+		domain = domain[i+1:]
+	}
+	for _, prefix := range s.prefixes {
+		if strings.HasPrefix(hostPath, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// List — потокобезопасный список блокировки. Файл перечитывается на лету
+// вызовом Reload, записи администраторов задаются SetAdminPatterns
+type List struct {
+	path string
+
+	mu      sync.RWMutex
+	file    set
+	admin   set
+	modTime time.Time
+}
+
+// New создает новый экземпляр List. Если path пустой, используются
+// только записи администраторов
+func New(path string) *List {
+	return &List{path: path, file: set{}, admin: set{}}
+}
+
+// Reload перечитывает файл, если он изменился с момента последней загрузки.
+// При ошибке разбора действует предыдущая версия списка
+func (l *List) Reload() (bool, error) {
+	if l.path == "" {
+		return false, nil
+	}
+
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return false, err
+	}
+
+	l.mu.RLock()
+	unchanged := info.ModTime().Equal(l.modTime)
+	l.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	patterns, err := Parse(f)
+	if err != nil {
+		return false, err
+	}
+	file := newSet(patterns)
+
+	l.mu.Lock()
+	l.file = file
+	l.modTime = info.ModTime()
+	l.mu.Unlock()
+	return true, nil
+}
+
+// SetAdminPatterns заменяет записи, добавленные администраторами
+func (l *List) SetAdminPatterns(patterns []string) {
+	admin := newSet(patterns)
+	l.mu.Lock()
+	l.admin = admin
+	l.mu.Unlock()
+}
+
+// Match проверяет ссылку по списку. Ссылки без хоста (mailto:, tel:)
+// не блокируются
+func (l *List) Match(rawURL string) (Match, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return Match{}, false
+	}
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return Match{}, false
+	}
+	hostPath := host + u.EscapedPath()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if pattern, ok := l.admin.match(host, hostPath); ok {
+		return Match{Pattern: pattern, Source: SourceAdmin}, true
+	}
+	if pattern, ok := l.file.match(host, hostPath); ok {
+		return Match{Pattern: pattern, Source: SourceFile}, true
+	}
+	return Match{}, false
+}
+
+// Parse читает записи из файла в формате hosts («0.0.0.0 example.com»)
+// или простого списка (по одному домену или ссылке в строке). Комментарии
+// начинаются с #, адреса localhost из формата hosts пропускаются
+func Parse(r io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 1 && isHostsAddress(fields[0]) {
+			fields = fields[1:]
+		}
+		for _, field := range fields {
+			if field == "localhost" || strings.HasPrefix(field, "localhost.") {
+				continue
+			}
+			patterns = append(patterns, field)
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+func isHostsAddress(field string) bool {
+	switch field {
+	case "0.0.0.0", "127.0.0.1", "::", "::1":
+		return true
+	}
+	return false
+}
+
+// Normalize приводит запись к каноническому виду: домен в нижнем регистре
+// в punycode без «*.» и завершающей точки, либо префикс ссылки host/path
+func Normalize(pattern string) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return "", ErrInvalidPattern
+	}
+
+	if strings.Contains(pattern, "://") {
+		u, err := url.Parse(pattern)
+		if err != nil || u.Hostname() == "" {
+			return "", ErrInvalidPattern
+		}
+		host, err := normalizeHost(u.Hostname())
+		if err != nil {
+			return "", err
+		}
+		if path := u.EscapedPath(); path != "" && path != "/" {
+			return host + path, nil
+		}
+		return host, nil
+	}
+
+	host, path, _ := strings.Cut(pattern, "/")
+	host, err := normalizeHost(strings.TrimPrefix(host, "*."))
+	if err != nil {
+		return "", err
+	}
+	if path != "" {
+		return host + "/" + path, nil
+	}
+	return host, nil
+}
+
+// hostProfile допускает подчеркивания в именах, которые встречаются
+// в списках блокировки, но запрещены строгим профилем idna.Lookup
+var hostProfile = idna.New(idna.MapForLookup(), idna.StrictDomainName(false), idna.Transitional(false))
+
+func normalizeHost(host string) (string, error) {
+	host, err := hostProfile.ToASCII(strings.TrimSuffix(strings.ToLower(host), "."))
+	if err != nil || host == "" {
+		return "", ErrInvalidPattern
+	}
+	return host, nil
+}
@@ -0,0 +1,174 @@
+package blocklist
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	l := New("")
+	l.SetAdminPatterns([]string{
+		"evil.example",
+		"*.tracker.test",
+		"пример.рф",
+		"XN--D1ACUFC.XN--P1AI",
+		"files.test/malware",
+		"https://share.test/s/bad",
+	})
+
+	tests := []struct {
+		url     string
+		pattern string
+	}{
+		{"https://evil.example/", "evil.example"},
+		{"https://EVIL.example./path", "evil.example"},
+		{"https://cdn.evil.example/x.js", "evil.example"},
+		{"https://a.b.tracker.test/", "tracker.test"},
+		{"https://tracker.test/", "tracker.test"},
+		// Запись в Unicode совпадает со ссылкой в punycode и наоборот
+		{"https://xn--e1afmkfd.xn--p1ai/", "xn--e1afmkfd.xn--p1ai"},
+		{"https://сайт.домен.пример.рф/", "xn--e1afmkfd.xn--p1ai"},
+		{"https://домен.рф/", "xn--d1acufc.xn--p1ai"},
+		{"https://files.test/malware/payload.exe", "files.test/malware"},
+		{"https://share.test/s/bad", "share.test/s/bad"},
+		{"", ""},
+		{"https://notevil.example/", ""},
+		{"https://evil.example.com/", ""},
+		{"https://files.test/clean", ""},
+		{"https://share.test/s/good", ""},
+		{"mailto:evil.example", ""},
+		{"tel:+79990000000", ""},
+	}
+	for _, tt := range tests {
+		match, ok := l.Match(tt.url)
+		if tt.pattern == "" {
+			if ok {
+				t.Errorf("Match(%q) = %+v, want no match", tt.url, match)
+			}
+			continue
+		}
+		if !ok || match.Pattern != tt.pattern || match.Source != SourceAdmin {
+			t.Errorf("Match(%q) = %+v, %v, want pattern %q from admin", tt.url, match, ok, tt.pattern)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{"Example.COM.", "example.com", false},
+		{"*.example.com", "example.com", false},
+		{"пример.рф", "xn--e1afmkfd.xn--p1ai", false},
+		{"ad_server.example", "ad_server.example", false},
+		{"https://example.com/", "example.com", false},
+		{"http://Example.com/path", "example.com/path", false},
+		{"example.com/path", "example.com/path", false},
+		{"", "", true},
+		{"   ", "", true},
+		{"https:///path", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.pattern)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Normalize(%q) = %q, %v, want %q, error %v", tt.pattern, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseHostsAndPlainFormats(t *testing.T) {
+	input := `# hosts
+0.0.0.0 ads.example
+127.0.0.1 localhost
+127.0.0.1 tracker.example metrics.example # два имени
+::1 localhost.localdomain
+plain.example
+https://share.test/s/bad
+`
+	got, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []string{"ads.example", "tracker.example", "metrics.example", "plain.example", "https://share.test/s/bad"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Parse = %q, want %q", got, want)
+	}
+}
+
+func TestReloadPicksUpChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	writeList(t, path, "first.example\n", time.Now().Add(-time.Hour))
+
+	l := New(path)
+	if changed, err := l.Reload(); err != nil || !changed {
+		t.Fatalf("first Reload = %v, %v, want changed", changed, err)
+	}
+	if _, ok := l.Match("https://first.example/"); !ok {
+		t.Fatal("first.example must be blocked after the first load")
+	}
+
+	// Неизмененный файл не перечитывается
+	if changed, err := l.Reload(); err != nil || changed {
+		t.Errorf("Reload of unchanged file = %v, %v, want unchanged", changed, err)
+	}
+
+	writeList(t, path, "second.example\n", time.Now())
+	if changed, err := l.Reload(); err != nil || !changed {
+		t.Fatalf("Reload after change = %v, %v, want changed", changed, err)
+	}
+	if _, ok := l.Match("https://first.example/"); ok {
+		t.Error("first.example must be unblocked after reload")
+	}
+	if match, ok := l.Match("https://second.example/"); !ok || match.Source != SourceFile {
+		t.Errorf("Match(second.example) = %+v, %v, want file match", match, ok)
+	}
+}
+
+func TestReloadKeepsPreviousListOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	writeList(t, path, "kept.example\n", time.Now())
+
+	l := New(path)
+	if _, err := l.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Reload(); err == nil {
+		t.Fatal("Reload of a missing file must fail")
+	}
+	if _, ok := l.Match("https://kept.example/"); !ok {
+		t.Error("previous list must stay active after a failed reload")
+	}
+}
+
+func TestAdminPatternsTakePrecedenceOverFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	writeList(t, path, "both.example\n", time.Now())
+
+	l := New(path)
+	if _, err := l.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	l.SetAdminPatterns([]string{"both.example"})
+
+	if match, ok := l.Match("https://both.example/"); !ok || match.Source != SourceAdmin {
+		t.Errorf("Match = %+v, %v, want admin match", match, ok)
+	}
+}
+
+func writeList(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
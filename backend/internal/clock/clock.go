@@ -0,0 +1,26 @@
+package clock
+
+import (
+	"time"
+)
+
+// Clock возвращает текущее время. Позволяет подменять время в расписаниях
+type Clock interface {
+	Now() time.Time
+}
+
+// Real возвращает системное время
+type Real struct{}
+
+// Now возвращает текущее системное время
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Func адаптирует функцию к интерфейсу Clock, например для фиксированного времени
+type Func func() time.Time
+
+// Now вызывает f
+func (f Func) Now() time.Time {
+	return f()
+}
@@ -0,0 +1,57 @@
+// Package colors разбирает цвета в шестнадцатеричной записи и вычисляет
+// их контраст по WCAG 2
+package colors
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidColor возвращается для цвета не в формате #rrggbb или #rgb
+var ErrInvalidColor = errors.New("неверный цвет")
+
+// Parse разбирает цвет в формате #rrggbb или #rgb (решетка необязательна)
+func Parse(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("%w %q", ErrInvalidColor, s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("%w %q", ErrInvalidColor, s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// Hex возвращает цвет в формате #rrggbb
+func Hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Luminance вычисляет относительную яркость цвета по WCAG 2
+func Luminance(c color.RGBA) float64 {
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+// Contrast вычисляет контраст двух цветов по WCAG 2: от 1 до 21
+func Contrast(a, b color.RGBA) float64 {
+	la, lb := Luminance(a), Luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
@@ -0,0 +1,830 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Окружения, в которых запускается приложение
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Config содержит все настройки приложения
+type Config struct {
+	// Env задает окружение: от него зависят значения по умолчанию,
+	// например sslmode подключения к базе
+	Env       string          `yaml:"env"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Auth      AuthConfig      `yaml:"auth"`
+	CORS      CORSConfig      `yaml:"cors"`
+	Log       LogConfig       `yaml:"log"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Trash     TrashConfig     `yaml:"trash"`
+	Drafts    DraftsConfig    `yaml:"drafts"`
+	URLPolicy URLPolicyConfig `yaml:"url_policy"`
+	Blocklist BlocklistConfig `yaml:"blocklist"`
+	LinkCheck LinkCheckConfig `yaml:"link_check"`
+	Unfurl    UnfurlConfig    `yaml:"unfurl"`
+	Passwords PasswordsConfig `yaml:"passwords"`
+	Domains   DomainsConfig   `yaml:"domains"`
+	Public    PublicConfig    `yaml:"public"`
+	OGCards   OGCardsConfig   `yaml:"og_cards"`
+	Uploads   UploadsConfig   `yaml:"uploads"`
+}
+
+// ServerConfig содержит настройки HTTP-сервера
+type ServerConfig struct {
+	Port            string        `yaml:"port"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// HealthCheckTimeout ограничивает время проверок /readyz
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout"`
+	// RequestTimeout ограничивает время обработки запроса, включая запросы к базе
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// TrustedProxies — адреса и подсети обратных прокси, которым доверяется
+	// заголовок X-Forwarded-For. Без них адресом посетителя считается адрес подключения
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// DatabaseConfig содержит настройки подключения к PostgreSQL.
+// Если задан URL, остальные параметры подключения игнорируются. Если sslmode
+// не задан, в production используется require, в development — disable
+type DatabaseConfig struct {
+	URL             string        `yaml:"url"`
+	Host            string        `yaml:"host"`
+	Port            string        `yaml:"port"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	Name            string        `yaml:"name"`
+	SSLMode         string        `yaml:"sslmode"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout"`
+	// QueryTimeout ограничивает время выполнения одного запроса
+	QueryTimeout time.Duration `yaml:"query_timeout"`
+	// AutoMigrate включает применение миграций при старте
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+// AuthConfig содержит настройки JWT-авторизации
+type AuthConfig struct {
+	JWTSecret     string        `yaml:"jwt_secret"`
+	TokenDuration time.Duration `yaml:"token_duration"`
+}
+
+// CORSConfig содержит список источников, которым доступно API личного кабинета
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+// LogConfig содержит настройки логирования
+type LogConfig struct {
+	Level string `yaml:"level"`
+}
+
+// MetricsConfig содержит настройки эндпоинта /metrics. Если задан ListenAddr,
+// метрики отдаются отдельным сервером, иначе на основном порту под basic auth
+type MetricsConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	ListenAddr string `yaml:"listen_addr"`
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+}
+
+// TrashConfig содержит настройки корзины. Удаленные мультиссылки и кнопки
+// окончательно удаляются по истечении Retention, проверка выполняется раз в PurgeInterval
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// DraftsConfig содержит настройки черновиков. Ссылки предпросмотра
+// подписываются секретом JWT и действуют PreviewTTL
+type DraftsConfig struct {
+	PreviewTTL time.Duration `yaml:"preview_ttl"`
+}
+
+// URLPolicyConfig содержит политику исходящих ссылок кнопок: разрешенные
+// схемы и максимальную длину ссылки
+type URLPolicyConfig struct {
+	AllowedSchemes []string `yaml:"allowed_schemes"`
+	MaxLength      int      `yaml:"max_length"`
+}
+
+// BlocklistConfig содержит настройки списка блокировки. Файл в формате hosts
+// или простого списка перечитывается раз в ReloadInterval, если изменился
+type BlocklistConfig struct {
+	Path           string        `yaml:"path"`
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+// LinkCheckConfig содержит настройки фоновой проверки ссылок кнопок.
+// Ссылки одного хоста проверяются последовательно с паузой HostDelay, владелец
+// уведомляется, если ссылка не работает дольше BrokenAfter
+type LinkCheckConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	Interval         time.Duration `yaml:"interval"`
+	Timeout          time.Duration `yaml:"timeout"`
+	Concurrency      int           `yaml:"concurrency"`
+	HostDelay        time.Duration `yaml:"host_delay"`
+	MaxRedirects     int           `yaml:"max_redirects"`
+	BrokenAfter      time.Duration `yaml:"broken_after"`
+	HistoryRetention time.Duration `yaml:"history_retention"`
+	UserAgent        string        `yaml:"user_agent"`
+}
+
+// UnfurlConfig содержит настройки предпросмотра ссылок: загрузка страницы
+// ограничена Timeout и MaxBodyBytes, результаты кэшируются на CacheTTL
+type UnfurlConfig struct {
+	Timeout      time.Duration `yaml:"timeout"`
+	MaxBodyBytes int64         `yaml:"max_body_bytes"`
+	MaxRedirects int           `yaml:"max_redirects"`
+	CacheTTL     time.Duration `yaml:"cache_ttl"`
+	CacheSize    int           `yaml:"cache_size"`
+	UserAgent    string        `yaml:"user_agent"`
+}
+
+// PasswordsConfig содержит настройки защиты страниц паролем: срок действия
+// доступа после ввода пароля и ограничения неверных попыток для одной страницы
+// с одного адреса (MaxAttempts) и со всех адресов вместе (MaxSlugAttempts)
+type PasswordsConfig struct {
+	UnlockTTL       time.Duration `yaml:"unlock_ttl"`
+	MaxAttempts     int           `yaml:"max_attempts"`
+	MaxSlugAttempts int           `yaml:"max_slug_attempts"`
+	AttemptWindow   time.Duration `yaml:"attempt_window"`
+}
+
+// DomainsConfig содержит настройки пользовательских доменов. PrimaryHosts —
+// собственные домены сервиса, которые не ищутся среди пользовательских; к ним
+// всегда добавляются хосты из public.page_base_url и public.api_base_url.
+// CacheSize ограничивает число доменов в кэше поиска мультиссылки по Host.
+// Если задан TLSListenAddr, на нем запускается HTTPS-сервер с сертификатами
+// из CertDir (файлы <домен>.crt и <домен>.key)
+type DomainsConfig struct {
+	PrimaryHosts  []string      `yaml:"primary_hosts"`
+	CertDir       string        `yaml:"cert_dir"`
+	TLSListenAddr string        `yaml:"tls_listen_addr"`
+	VerifyTimeout time.Duration `yaml:"verify_timeout"`
+	CacheTTL      time.Duration `yaml:"cache_ttl"`
+	CacheSize     int           `yaml:"cache_size"`
+}
+
+// PublicConfig содержит внешние адреса сервиса, по которым строятся ссылки
+// для QR-кодов и превью: PageBaseURL — публичные страницы (фронтенд),
+// APIBaseURL — публичное API с переходами по кнопкам
+type PublicConfig struct {
+	PageBaseURL string `yaml:"page_base_url"`
+	APIBaseURL  string `yaml:"api_base_url"`
+}
+
+// OGCardsConfig содержит настройки кэша карточек страниц для og:image
+type OGCardsConfig struct {
+	CacheSize int           `yaml:"cache_size"`
+	CacheTTL  time.Duration `yaml:"cache_ttl"`
+}
+
+// Хранилища загруженных изображений
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+// UploadsConfig содержит настройки загрузки изображений: предельный размер
+// файла и хранилище — локальный каталог LocalDir или S3-совместимый бакет
+type UploadsConfig struct {
+	MaxSize  int64    `yaml:"max_size"`
+	Storage  string   `yaml:"storage"`
+	LocalDir string   `yaml:"local_dir"`
+	S3       S3Config `yaml:"s3"`
+}
+
+// S3Config содержит параметры S3-совместимого хранилища (AWS S3, MinIO).
+// Endpoint указывается без схемы, например "localhost:9000"
+type S3Config struct {
+	Endpoint  string        `yaml:"endpoint"`
+	Region    string        `yaml:"region"`
+	Bucket    string        `yaml:"bucket"`
+	AccessKey string        `yaml:"access_key"`
+	SecretKey string        `yaml:"secret_key"`
+	UseSSL    bool          `yaml:"use_ssl"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+// MinJWTSecretLength задает минимальную длину секрета для подписи токенов
+const MinJWTSecretLength = 32
+
+// redactedValue подставляется вместо секретов при выводе конфигурации
+const redactedValue = "[REDACTED]"
+
+// sslModes содержит значения sslmode, поддерживаемые драйвером lib/pq
+var sslModes = map[string]struct{}{
+	"disable": {}, "require": {}, "verify-ca": {}, "verify-full": {},
+}
+
+// weakSecrets содержит заведомо небезопасные значения секретов
+var weakSecrets = []string{"secret", "changeme", "password", "jwt_secret", "your-secret-key"}
+
+// Default возвращает конфигурацию со значениями по умолчанию
+func Default() Config {
+	return Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Port:               "8080",
+			ReadTimeout:        10 * time.Second,
+			WriteTimeout:       10 * time.Second,
+			ShutdownTimeout:    15 * time.Second,
+			HealthCheckTimeout: 2 * time.Second,
+			RequestTimeout:     8 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "5432",
+			User:            "mvp_user",
+			Name:            "mvp_db",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnectTimeout:  5 * time.Second,
+			QueryTimeout:    3 * time.Second,
+			AutoMigrate:     true,
+		},
+		Auth: AuthConfig{
+			TokenDuration: 24 * time.Hour,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"http://localhost:5173"},
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Metrics: MetricsConfig{
+			Enabled:    true,
+			ListenAddr: ":9090",
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Drafts: DraftsConfig{
+			PreviewTTL: 72 * time.Hour,
+		},
+		URLPolicy: URLPolicyConfig{
+			AllowedSchemes: []string{"http", "https", "mailto", "tel", "sms"},
+			MaxLength:      2048,
+		},
+		Blocklist: BlocklistConfig{
+			ReloadInterval: time.Minute,
+		},
+		LinkCheck: LinkCheckConfig{
+			Enabled:          true,
+			Interval:         6 * time.Hour,
+			Timeout:          10 * time.Second,
+			Concurrency:      8,
+			HostDelay:        2 * time.Second,
+			MaxRedirects:     10,
+			BrokenAfter:      24 * time.Hour,
+			HistoryRetention: 30 * 24 * time.Hour,
+			UserAgent:        "MultyLinkBot/1.0 (link checker)",
+		},
+		Unfurl: UnfurlConfig{
+			Timeout:      5 * time.Second,
+			MaxBodyBytes: 1 << 20,
+			MaxRedirects: 5,
+			CacheTTL:     time.Hour,
+			CacheSize:    1000,
+			UserAgent:    "MultyLinkBot/1.0 (link preview)",
+		},
+		Passwords: PasswordsConfig{
+			UnlockTTL:       12 * time.Hour,
+			MaxAttempts:     10,
+			MaxSlugAttempts: 100,
+			AttemptWindow:   15 * time.Minute,
+		},
+		Domains: DomainsConfig{
+			PrimaryHosts:  []string{"localhost"},
+			VerifyTimeout: 10 * time.Second,
+			CacheTTL:      time.Minute,
+			CacheSize:     10000,
+		},
+		Public: PublicConfig{
+			PageBaseURL: "http://localhost:5173",
+			APIBaseURL:  "http://localhost:8080",
+		},
+		OGCards: OGCardsConfig{
+			CacheSize: 500,
+			CacheTTL:  24 * time.Hour,
+		},
+		Uploads: UploadsConfig{
+			MaxSize:  5 << 20,
+			Storage:  StorageLocal,
+			LocalDir: "data/uploads",
+			S3: S3Config{
+				Region:  "us-east-1",
+				Timeout: 30 * time.Second,
+			},
+		},
+	}
+}
+
+// Load загружает конфигурацию: значения по умолчанию, затем YAML-файл
+// (если путь не пустой), затем переменные окружения. Результат валидируется
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	cfg.applyEnvironmentDefaults()
+	cfg.addPublicHosts()
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// applyEnvironmentDefaults заполняет значения по умолчанию, которые зависят
+// от окружения. Локальный PostgreSQL обычно запущен без TLS, поэтому
+// шифрование по умолчанию требуется только в production
+func (c *Config) applyEnvironmentDefaults() {
+	if c.Database.SSLMode == "" {
+		if c.Env == EnvProduction {
+			c.Database.SSLMode = "require"
+		} else {
+			c.Database.SSLMode = "disable"
+		}
+	}
+}
+
+// addPublicHosts добавляет к собственным доменам сервиса хосты публичных
+// адресов: запросы к ним маршрутизируются по slug, даже если primary_hosts
+// задан без них
+func (c *Config) addPublicHosts() {
+	for _, base := range []string{c.Public.PageBaseURL, c.Public.APIBaseURL} {
+		u, err := url.Parse(base)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		if !slices.Contains(c.Domains.PrimaryHosts, host) {
+			c.Domains.PrimaryHosts = append(c.Domains.PrimaryHosts, host)
+		}
+	}
+}
+
+// applyEnv переопределяет значения из переменных окружения
+func (c *Config) applyEnv() error {
+	setString(&c.Env, "APP_ENV")
+	setString(&c.Server.Port, "PORT")
+	setString(&c.Database.URL, "DATABASE_URL")
+	setString(&c.Database.Host, "DB_HOST")
+	setString(&c.Database.Port, "DB_PORT")
+	setString(&c.Database.User, "DB_USER")
+	setString(&c.Database.Password, "DB_PASSWORD")
+	setString(&c.Database.Name, "DB_NAME")
+	setString(&c.Database.SSLMode, "DB_SSLMODE")
+	setString(&c.Auth.JWTSecret, "JWT_SECRET")
+	setString(&c.Log.Level, "LOG_LEVEL")
+	setString(&c.Metrics.ListenAddr, "METRICS_LISTEN_ADDR")
+	setString(&c.Metrics.Username, "METRICS_USERNAME")
+	setString(&c.Metrics.Password, "METRICS_PASSWORD")
+	setString(&c.Blocklist.Path, "BLOCKLIST_PATH")
+	setString(&c.Domains.CertDir, "DOMAINS_CERT_DIR")
+	setString(&c.Domains.TLSListenAddr, "DOMAINS_TLS_LISTEN_ADDR")
+	setString(&c.Public.PageBaseURL, "PUBLIC_PAGE_BASE_URL")
+	setString(&c.Public.APIBaseURL, "PUBLIC_API_BASE_URL")
+	setString(&c.Uploads.Storage, "UPLOADS_STORAGE")
+	setString(&c.Uploads.LocalDir, "UPLOADS_LOCAL_DIR")
+	setString(&c.Uploads.S3.Endpoint, "UPLOADS_S3_ENDPOINT")
+	setString(&c.Uploads.S3.Region, "UPLOADS_S3_REGION")
+	setString(&c.Uploads.S3.Bucket, "UPLOADS_S3_BUCKET")
+	setString(&c.Uploads.S3.AccessKey, "UPLOADS_S3_ACCESS_KEY")
+	setString(&c.Uploads.S3.SecretKey, "UPLOADS_S3_SECRET_KEY")
+	if err := setBool(&c.Metrics.Enabled, "METRICS_ENABLED"); err != nil {
+		return err
+	}
+	if err := setBool(&c.Database.AutoMigrate, "DB_AUTO_MIGRATE"); err != nil {
+		return err
+	}
+	if err := setBool(&c.LinkCheck.Enabled, "LINK_CHECK_ENABLED"); err != nil {
+		return err
+	}
+	if err := setBool(&c.Uploads.S3.UseSSL, "UPLOADS_S3_USE_SSL"); err != nil {
+		return err
+	}
+
+	if value := os.Getenv("HTTP_TRUSTED_PROXIES"); value != "" {
+		c.Server.TrustedProxies = splitList(value)
+	}
+	if value := os.Getenv("CORS_ALLOWED_ORIGINS"); value != "" {
+		c.CORS.AllowedOrigins = splitList(value)
+	}
+	if value := os.Getenv("URL_ALLOWED_SCHEMES"); value != "" {
+		c.URLPolicy.AllowedSchemes = splitList(value)
+	}
+	if value := os.Getenv("DOMAINS_PRIMARY_HOSTS"); value != "" {
+		c.Domains.PrimaryHosts = splitList(value)
+	}
+
+	durations := []struct {
+		target *time.Duration
+		key    string
+	}{
+		{&c.Server.ReadTimeout, "HTTP_READ_TIMEOUT"},
+		{&c.Server.WriteTimeout, "HTTP_WRITE_TIMEOUT"},
+		{&c.Server.ShutdownTimeout, "HTTP_SHUTDOWN_TIMEOUT"},
+		{&c.Server.HealthCheckTimeout, "HEALTH_CHECK_TIMEOUT"},
+		{&c.Server.RequestTimeout, "HTTP_REQUEST_TIMEOUT"},
+		{&c.Database.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME"},
+		{&c.Database.ConnectTimeout, "DB_CONNECT_TIMEOUT"},
+		{&c.Database.QueryTimeout, "DB_QUERY_TIMEOUT"},
+		{&c.Auth.TokenDuration, "JWT_TOKEN_DURATION"},
+		{&c.Trash.Retention, "TRASH_RETENTION"},
+		{&c.Trash.PurgeInterval, "TRASH_PURGE_INTERVAL"},
+		{&c.Drafts.PreviewTTL, "DRAFT_PREVIEW_TTL"},
+		{&c.Blocklist.ReloadInterval, "BLOCKLIST_RELOAD_INTERVAL"},
+		{&c.LinkCheck.Interval, "LINK_CHECK_INTERVAL"},
+		{&c.LinkCheck.BrokenAfter, "LINK_CHECK_BROKEN_AFTER"},
+		{&c.Unfurl.Timeout, "UNFURL_TIMEOUT"},
+		{&c.Unfurl.CacheTTL, "UNFURL_CACHE_TTL"},
+		{&c.Passwords.UnlockTTL, "PASSWORD_UNLOCK_TTL"},
+		{&c.Passwords.AttemptWindow, "PASSWORD_ATTEMPT_WINDOW"},
+		{&c.Domains.VerifyTimeout, "DOMAINS_VERIFY_TIMEOUT"},
+		{&c.Domains.CacheTTL, "DOMAINS_CACHE_TTL"},
+		{&c.OGCards.CacheTTL, "OG_CARD_CACHE_TTL"},
+		{&c.Uploads.S3.Timeout, "UPLOADS_S3_TIMEOUT"},
+	}
+	for _, d := range durations {
+		if err := setDuration(d.target, d.key); err != nil {
+			return err
+		}
+	}
+
+	ints := []struct {
+		target *int
+		key    string
+	}{
+		{&c.Database.MaxOpenConns, "DB_MAX_OPEN_CONNS"},
+		{&c.Database.MaxIdleConns, "DB_MAX_IDLE_CONNS"},
+		{&c.URLPolicy.MaxLength, "URL_MAX_LENGTH"},
+		{&c.LinkCheck.Concurrency, "LINK_CHECK_CONCURRENCY"},
+		{&c.Unfurl.CacheSize, "UNFURL_CACHE_SIZE"},
+		{&c.Domains.CacheSize, "DOMAINS_CACHE_SIZE"},
+		{&c.Passwords.MaxAttempts, "PASSWORD_MAX_ATTEMPTS"},
+		{&c.Passwords.MaxSlugAttempts, "PASSWORD_MAX_SLUG_ATTEMPTS"},
+		{&c.OGCards.CacheSize, "OG_CARD_CACHE_SIZE"},
+	}
+	for _, i := range ints {
+		if err := setInt(i.target, i.key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate проверяет корректность конфигурации
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		errs = append(errs, fmt.Errorf("env: unsupported value %q", c.Env))
+	}
+
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		errs = append(errs, fmt.Errorf("server.port: invalid port %q", c.Server.Port))
+	}
+	if c.Server.ReadTimeout <= 0 || c.Server.WriteTimeout <= 0 || c.Server.ShutdownTimeout <= 0 ||
+		c.Server.HealthCheckTimeout <= 0 || c.Server.RequestTimeout <= 0 {
+		errs = append(errs, errors.New("server: timeouts must be positive"))
+	}
+
+	if c.Database.URL != "" {
+		if _, err := url.Parse(c.Database.URL); err != nil {
+			errs = append(errs, fmt.Errorf("database.url: %w", err))
+		}
+	} else {
+		if c.Database.Host == "" || c.Database.User == "" || c.Database.Name == "" {
+			errs = append(errs, errors.New("database: host, user and name are required"))
+		}
+		if c.Database.Password == "" {
+			errs = append(errs, errors.New("database.password: DB_PASSWORD is required"))
+		}
+	}
+	if _, ok := sslModes[c.Database.SSLMode]; !ok {
+		errs = append(errs, fmt.Errorf("database.sslmode: unsupported value %q", c.Database.SSLMode))
+	}
+	if c.Database.MaxOpenConns <= 0 {
+		errs = append(errs, errors.New("database.max_open_conns must be positive"))
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, errors.New("database.max_idle_conns must be between 0 and max_open_conns"))
+	}
+	if c.Database.ConnMaxLifetime <= 0 || c.Database.ConnectTimeout <= 0 || c.Database.QueryTimeout <= 0 {
+		errs = append(errs, errors.New("database: conn_max_lifetime, connect_timeout and query_timeout must be positive"))
+	}
+
+	if err := validateJWTSecret(c.Auth.JWTSecret); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Auth.TokenDuration <= 0 {
+		errs = append(errs, errors.New("auth.token_duration must be positive"))
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("cors.allowed_origins must not be empty"))
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level: unsupported value %q", c.Log.Level))
+	}
+
+	if c.Metrics.Enabled && c.Metrics.ListenAddr == "" && (c.Metrics.Username == "" || c.Metrics.Password == "") {
+		errs = append(errs, errors.New("metrics: listen_addr or username and password are required"))
+	}
+
+	if c.Trash.Retention <= 0 || c.Trash.PurgeInterval <= 0 {
+		errs = append(errs, errors.New("trash: retention and purge_interval must be positive"))
+	}
+	if c.Drafts.PreviewTTL <= 0 {
+		errs = append(errs, errors.New("drafts.preview_ttl must be positive"))
+	}
+	if len(c.URLPolicy.AllowedSchemes) == 0 || c.URLPolicy.MaxLength <= 0 {
+		errs = append(errs, errors.New("url_policy: allowed_schemes must not be empty and max_length must be positive"))
+	}
+	if c.Blocklist.ReloadInterval <= 0 {
+		errs = append(errs, errors.New("blocklist.reload_interval must be positive"))
+	}
+	if c.LinkCheck.Enabled {
+		lc := c.LinkCheck
+		if lc.Interval <= 0 || lc.Timeout <= 0 || lc.BrokenAfter <= 0 || lc.HistoryRetention <= 0 || lc.HostDelay < 0 {
+			errs = append(errs, errors.New("link_check: interval, timeout, broken_after and history_retention must be positive"))
+		}
+		if lc.Concurrency <= 0 || lc.MaxRedirects < 0 {
+			errs = append(errs, errors.New("link_check: concurrency must be positive and max_redirects non-negative"))
+		}
+	}
+
+	if c.Unfurl.Timeout <= 0 || c.Unfurl.MaxBodyBytes <= 0 || c.Unfurl.MaxRedirects < 0 {
+		errs = append(errs, errors.New("unfurl: timeout and max_body_bytes must be positive and max_redirects non-negative"))
+	}
+	if c.Unfurl.CacheTTL < 0 || c.Unfurl.CacheSize < 0 {
+		errs = append(errs, errors.New("unfurl: cache_ttl and cache_size must not be negative"))
+	}
+	if c.Passwords.UnlockTTL <= 0 || c.Passwords.AttemptWindow <= 0 || c.Passwords.MaxAttempts <= 0 || c.Passwords.MaxSlugAttempts <= 0 {
+		errs = append(errs, errors.New("passwords: unlock_ttl, max_attempts, max_slug_attempts and attempt_window must be positive"))
+	}
+	if c.Domains.VerifyTimeout <= 0 || c.Domains.CacheTTL < 0 || c.Domains.CacheSize < 0 {
+		errs = append(errs, errors.New("domains: verify_timeout must be positive and cache_ttl, cache_size non-negative"))
+	}
+	if c.Domains.TLSListenAddr != "" && c.Domains.CertDir == "" {
+		errs = append(errs, errors.New("domains.cert_dir is required with tls_listen_addr"))
+	}
+	if !isAbsoluteHTTPURL(c.Public.PageBaseURL) || !isAbsoluteHTTPURL(c.Public.APIBaseURL) {
+		errs = append(errs, errors.New("public: page_base_url and api_base_url must be absolute http(s) URLs"))
+	}
+	if c.OGCards.CacheSize < 0 || c.OGCards.CacheTTL < 0 {
+		errs = append(errs, errors.New("og_cards: cache_size and cache_ttl must not be negative"))
+	}
+	if c.Uploads.MaxSize <= 0 {
+		errs = append(errs, errors.New("uploads.max_size must be positive"))
+	}
+	switch c.Uploads.Storage {
+	case StorageLocal:
+		if c.Uploads.LocalDir == "" {
+			errs = append(errs, errors.New("uploads.local_dir is required for local storage"))
+		}
+	case StorageS3:
+		s3 := c.Uploads.S3
+		if s3.Endpoint == "" || s3.Region == "" || s3.Bucket == "" || s3.AccessKey == "" || s3.SecretKey == "" {
+			errs = append(errs, errors.New("uploads.s3: endpoint, region, bucket, access_key and secret_key are required"))
+		}
+		if s3.Timeout <= 0 {
+			errs = append(errs, errors.New("uploads.s3.timeout must be positive"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("uploads.storage: unsupported value %q", c.Uploads.Storage))
+	}
+
+	return errors.Join(errs...)
+}
+
+func isAbsoluteHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func validateJWTSecret(secret string) error {
+	if secret == "" {
+		return errors.New("auth.jwt_secret: JWT_SECRET is required")
+	}
+	if len(secret) < MinJWTSecretLength {
+		return fmt.Errorf("auth.jwt_secret: must be at least %d characters", MinJWTSecretLength)
+	}
+	lower := strings.ToLower(secret)
+	for _, weak := range weakSecrets {
+		if strings.Contains(lower, weak) {
+			return errors.New("auth.jwt_secret: secret is too weak")
+		}
+	}
+	if strings.Count(secret, secret[:1]) == len(secret) {
+		return errors.New("auth.jwt_secret: secret is too weak")
+	}
+	return nil
+}
+
+// DSN возвращает строку подключения к PostgreSQL
+func (d DatabaseConfig) DSN() string {
+	if d.URL != "" {
+		if !isURLDSN(d.URL) {
+			return appendKeyValueDSN(d.URL, d.SSLMode, d.ConnectTimeout)
+		}
+		u, err := url.Parse(d.URL)
+		if err != nil {
+			return d.URL
+		}
+		// sslmode из конфигурации применяется, только если он не указан в URL
+		query := u.Query()
+		if query.Get("sslmode") == "" {
+			query.Set("sslmode", d.SSLMode)
+		}
+		if query.Get("connect_timeout") == "" {
+			query.Set("connect_timeout", strconv.Itoa(int(d.ConnectTimeout.Seconds())))
+		}
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s connect_timeout=%d",
+		quoteDSNValue(d.Host), quoteDSNValue(d.Port), quoteDSNValue(d.User), quoteDSNValue(d.Password),
+		quoteDSNValue(d.Name), d.SSLMode, int(d.ConnectTimeout.Seconds()))
+}
+
+// isURLDSN проверяет, что строка подключения задана в виде URL, а не
+// в формате key=value
+func isURLDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
+
+// dsnKeyPattern находит ключ в строке подключения формата key=value
+func dsnKeyPattern(key string) *regexp.Regexp {
+	return regexp.MustCompile(`(^|\s)` + key + `\s*=`)
+}
+
+// dsnPasswordPattern находит значение пароля в строке формата key=value,
+// в том числе в кавычках с экранированием
+var dsnPasswordPattern = regexp.MustCompile(`(^|\s)(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S*)`)
+
+// appendKeyValueDSN дополняет строку формата key=value параметрами sslmode
+// и connect_timeout, если они в ней не указаны
+func appendKeyValueDSN(dsn, sslMode string, connectTimeout time.Duration) string {
+	dsn = strings.TrimSpace(dsn)
+	if !dsnKeyPattern("sslmode").MatchString(dsn) {
+		dsn += " sslmode=" + quoteDSNValue(sslMode)
+	}
+	if !dsnKeyPattern("connect_timeout").MatchString(dsn) {
+		dsn += " connect_timeout=" + strconv.Itoa(int(connectTimeout.Seconds()))
+	}
+	return dsn
+}
+
+// redactDSN скрывает пароль в строке подключения любого формата
+func redactDSN(dsn string) string {
+	if !isURLDSN(dsn) {
+		return dsnPasswordPattern.ReplaceAllString(dsn, "${1}${2}"+redactedValue)
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return redactedValue
+	}
+	// lib/pq принимает пароль и в параметрах запроса
+	if query := u.Query(); query.Has("password") {
+		query.Set("password", redactedValue)
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
+
+// quoteDSNValue экранирует значение для формата key=value
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// Redacted возвращает копию конфигурации со скрытыми секретами
+func (c Config) Redacted() Config {
+	redacted := c
+	if redacted.Database.Password != "" {
+		redacted.Database.Password = redactedValue
+	}
+	if redacted.Database.URL != "" {
+		redacted.Database.URL = redactDSN(redacted.Database.URL)
+	}
+	if redacted.Auth.JWTSecret != "" {
+		redacted.Auth.JWTSecret = redactedValue
+	}
+	if redacted.Metrics.Password != "" {
+		redacted.Metrics.Password = redactedValue
+	}
+	if redacted.Uploads.S3.SecretKey != "" {
+		redacted.Uploads.S3.SecretKey = redactedValue
+	}
+	redacted.Server.TrustedProxies = append([]string(nil), c.Server.TrustedProxies...)
+	redacted.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+	return redacted
+}
+
+// String возвращает эффективную конфигурацию в формате YAML без секретов
+func (c Config) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("<config: %v>", err)
+	}
+	return string(data)
+}
+
+func setString(target *string, key string) {
+	if value := os.Getenv(key); value != "" {
+		*target = value
+	}
+}
+
+func setBool(target *bool, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s: invalid boolean %q", key, value)
+	}
+	*target = b
+	return nil
+}
+
+func setDuration(target *time.Duration, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s: invalid duration %q", key, value)
+	}
+	*target = d
+	return nil
+}
+
+func setInt(target *int, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s: invalid integer %q", key, value)
+	}
+	*target = i
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testJWTSecret = "k7P2x9Qm4Rz8Lw3Nc6Vb1Hd5Tf0Gy2Ja"
+
+// envKeys перечисляет переменные, которые читает applyEnv. Тесты
+// сбрасывают их, чтобы окружение запуска не влияло на результат
+var envKeys = []string{
+	"APP_ENV", "PORT", "DATABASE_URL", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME",
+	"DB_SSLMODE", "JWT_SECRET", "LOG_LEVEL", "METRICS_LISTEN_ADDR", "METRICS_USERNAME",
+	"METRICS_PASSWORD", "METRICS_ENABLED", "DB_AUTO_MIGRATE", "HTTP_TRUSTED_PROXIES",
+	"CORS_ALLOWED_ORIGINS", "URL_ALLOWED_SCHEMES", "UPLOADS_S3_SECRET_KEY",
+	"PUBLIC_PAGE_BASE_URL", "PUBLIC_API_BASE_URL", "DOMAINS_PRIMARY_HOSTS",
+}
+
+// setTestEnv сбрасывает переменные окружения и задает обязательные секреты
+func setTestEnv(t *testing.T, values map[string]string) {
+	t.Helper()
+	for _, key := range envKeys {
+		t.Setenv(key, "")
+	}
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("DB_PASSWORD", "db-pass")
+	for key, value := range values {
+		t.Setenv(key, value)
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAppliesEnvOverYAMLOverDefaults(t *testing.T) {
+	setTestEnv(t, map[string]string{"PORT": "9100", "CORS_ALLOWED_ORIGINS": "https://a.example, https://b.example"})
+	path := writeConfig(t, `
+server:
+  port: "9000"
+  request_timeout: 4s
+log:
+  level: debug
+cors:
+  allowed_origins: ["https://yaml.example"]
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Server.Port != "9100" {
+		t.Errorf("port = %q, want env value 9100", cfg.Server.Port)
+	}
+	if cfg.Server.RequestTimeout != 4*time.Second {
+		t.Errorf("request_timeout = %v, want YAML value 4s", cfg.Server.RequestTimeout)
+	}
+	if cfg.Log.Level != "debug" {
+		t.Errorf("log level = %q, want YAML value debug", cfg.Log.Level)
+	}
+	if got := strings.Join(cfg.CORS.AllowedOrigins, " "); got != "https://a.example https://b.example" {
+		t.Errorf("allowed origins = %q, want env list", got)
+	}
+	if cfg.Server.ReadTimeout != Default().Server.ReadTimeout {
+		t.Errorf("read_timeout = %v, want default", cfg.Server.ReadTimeout)
+	}
+}
+
+func TestLoadRejectsInvalidEnvValues(t *testing.T) {
+	setTestEnv(t, map[string]string{"METRICS_ENABLED": "maybe"})
+	if _, err := Load(""); err == nil {
+		t.Error("Load must reject a non-boolean METRICS_ENABLED")
+	}
+
+	setTestEnv(t, map[string]string{"APP_ENV": "staging"})
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "env") {
+		t.Errorf("Load error = %v, want unsupported env", err)
+	}
+}
+
+func TestLoadSSLModeDependsOnEnvironment(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, "disable"},
+		{map[string]string{"APP_ENV": "development"}, "disable"},
+		{map[string]string{"APP_ENV": "production"}, "require"},
+		{map[string]string{"APP_ENV": "production", "DB_SSLMODE": "verify-full"}, "verify-full"},
+		{map[string]string{"DB_SSLMODE": "require"}, "require"},
+	}
+	for _, tt := range tests {
+		setTestEnv(t, tt.env)
+		cfg, err := Load("")
+		if err != nil {
+			t.Fatalf("Load with %v: %v", tt.env, err)
+		}
+		if cfg.Database.SSLMode != tt.want {
+			t.Errorf("Load with %v: sslmode = %q, want %q", tt.env, cfg.Database.SSLMode, tt.want)
+		}
+	}
+}
+
+func TestLoadRejectsWeakJWTSecret(t *testing.T) {
+	for _, secret := range []string{
+		"short-secret",
+		strings.Repeat("a", MinJWTSecretLength),
+		"changeme-changeme-changeme-changeme",
+		"my-very-long-jwt_secret-for-production",
+	} {
+		setTestEnv(t, map[string]string{"JWT_SECRET": secret})
+		if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "auth.jwt_secret") {
+			t.Errorf("Load with JWT_SECRET %q error = %v, want auth.jwt_secret error", secret, err)
+		}
+	}
+
+	setTestEnv(t, nil)
+	t.Setenv("JWT_SECRET", "")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "JWT_SECRET is required") {
+		t.Errorf("Load without JWT_SECRET error = %v, want required error", err)
+	}
+}
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		db   DatabaseConfig
+		want string
+	}{
+		{
+			name: "url adds sslmode and connect_timeout",
+			db:   DatabaseConfig{URL: "postgres://u:p@db:5432/app", SSLMode: "require", ConnectTimeout: 5 * time.Second},
+			want: "postgres://u:p@db:5432/app?connect_timeout=5&sslmode=require",
+		},
+		{
+			name: "url keeps its own sslmode",
+			db:   DatabaseConfig{URL: "postgresql://u:p@db/app?sslmode=verify-full", SSLMode: "disable", ConnectTimeout: 5 * time.Second},
+			want: "postgresql://u:p@db/app?connect_timeout=5&sslmode=verify-full",
+		},
+		{
+			name: "key=value is not parsed as url",
+			db:   DatabaseConfig{URL: "host=db user=u password=p dbname=app", SSLMode: "require", ConnectTimeout: 3 * time.Second},
+			want: "host=db user=u password=p dbname=app sslmode=require connect_timeout=3",
+		},
+		{
+			name: "key=value keeps its own sslmode and timeout",
+			db:   DatabaseConfig{URL: "host=db sslmode=disable connect_timeout=10", SSLMode: "require", ConnectTimeout: 3 * time.Second},
+			want: "host=db sslmode=disable connect_timeout=10",
+		},
+		{
+			name: "fields are quoted",
+			db:   DatabaseConfig{Host: "localhost", Port: "5432", User: "u", Password: `it's a pass`, Name: "app", SSLMode: "disable", ConnectTimeout: 5 * time.Second},
+			want: `host=localhost port=5432 user=u password='it\'s a pass' dbname=app sslmode=disable connect_timeout=5`,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.db.DSN(); got != tt.want {
+			t.Errorf("%s: DSN() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRedactedHidesSecrets(t *testing.T) {
+	for _, dsn := range []string{
+		"postgres://u:url-pass@db:5432/app",
+		"postgres://u@db/app?password=url-pass",
+		"host=db user=u password=url-pass dbname=app",
+		`host=db password='url-pass with spaces \' quote' dbname=app`,
+	} {
+		cfg := Default()
+		cfg.Database.URL = dsn
+		cfg.Database.Password = "field-pass"
+		cfg.Auth.JWTSecret = testJWTSecret
+		cfg.Metrics.Password = "metrics-pass"
+		cfg.Uploads.S3.SecretKey = "s3-secret"
+
+		out := cfg.String()
+		for _, secret := range []string{"url-pass", "field-pass", testJWTSecret, "metrics-pass", "s3-secret"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("String() for DSN %q leaks %q:\n%s", dsn, secret, out)
+			}
+		}
+		if !strings.Contains(out, redactedValue) {
+			t.Errorf("String() for DSN %q does not mark redacted values", dsn)
+		}
+		if strings.Contains(cfg.Redacted().Database.URL, "url-pass") {
+			t.Errorf("Redacted() leaks the password of DSN %q", dsn)
+		}
+		// Исходная конфигурация не меняется
+		if cfg.Database.Password != "field-pass" || cfg.Database.URL != dsn {
+			t.Error("Redacted() must not modify the original config")
+		}
+	}
+}
+
+func TestLoadAddsPublicHostsToPrimaryHosts(t *testing.T) {
+	setTestEnv(t, map[string]string{
+		"PUBLIC_PAGE_BASE_URL":  "https://Links.Example.com",
+		"PUBLIC_API_BASE_URL":   "https://api.example.com:8443/v1",
+		"DOMAINS_PRIMARY_HOSTS": "localhost, api.example.com",
+	})
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := strings.Join(cfg.Domains.PrimaryHosts, " "); got != "localhost api.example.com links.example.com" {
+		t.Errorf("primary hosts = %q, want configured hosts plus public hosts", got)
+	}
+}
@@ -0,0 +1,83 @@
+package domains
+
+import (
+	"crypto/tls"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCertificate возвращается, если для домена нет сертификата в каталоге
+var ErrNoCertificate = errors.New("сертификат для домена не найден")
+
+// CertStore загружает TLS-сертификаты пользовательских доменов из каталога.
+// Для домена example.com используются файлы example.com.crt и example.com.key.
+// Сертификаты перечитываются, когда меняется время изменения файлов, поэтому
+// обновление сертификата не требует перезапуска
+type CertStore struct {
+	dir string
+
+	mu    sync.RWMutex
+	certs map[string]cachedCert
+}
+
+type cachedCert struct {
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// NewCertStore создает новый экземпляр CertStore
+func NewCertStore(dir string) *CertStore {
+	return &CertStore{dir: dir, certs: make(map[string]cachedCert)}
+}
+
+// GetCertificate выбирает сертификат по SNI. Подходит для tls.Config.GetCertificate
+func (s *CertStore) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	hostname, err := NormalizeHostname(hello.ServerName)
+	if err != nil {
+		return nil, ErrNoCertificate
+	}
+	return s.certificate(hostname)
+}
+
+func (s *CertStore) certificate(hostname string) (*tls.Certificate, error) {
+	// Имя уже нормализовано, но защищаемся от выхода за пределы каталога
+	if strings.ContainsAny(hostname, `/\`) {
+		return nil, ErrNoCertificate
+	}
+	certFile := filepath.Join(s.dir, hostname+".crt")
+	keyFile := filepath.Join(s.dir, hostname+".key")
+
+	certInfo, err := os.Stat(certFile)
+	if err != nil {
+		return nil, ErrNoCertificate
+	}
+	keyInfo, err := os.Stat(keyFile)
+	if err != nil {
+		return nil, ErrNoCertificate
+	}
+	modTime := certInfo.ModTime()
+	if keyInfo.ModTime().After(modTime) {
+		modTime = keyInfo.ModTime()
+	}
+
+	s.mu.RLock()
+	cached, ok := s.certs[hostname]
+	s.mu.RUnlock()
+	if ok && cached.modTime.Equal(modTime) {
+		return cached.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.certs[hostname] = cachedCert{cert: &cert, modTime: modTime}
+	s.mu.Unlock()
+	return &cert, nil
+}
@@ -0,0 +1,149 @@
+// Package domains содержит проверку владения пользовательскими доменами
+// и загрузку TLS-сертификатов для них
+package domains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/idna"
+
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// Способы подтверждения владения доменом
+const (
+	MethodDNS  = "dns"
+	MethodHTTP = "http"
+)
+
+const (
+	// TXTPrefix — поддомен, в TXT-записи которого размещается токен
+	TXTPrefix = "_multylink-verification."
+	// TXTValuePrefix предшествует токену в значении TXT-записи
+	TXTValuePrefix = "multylink-verification="
+	// WellKnownPath — путь файла с токеном для подтверждения по HTTP
+	WellKnownPath = "/.well-known/multylink-verification.txt"
+
+	// maxTokenFileSize ограничивает размер файла с токеном
+	maxTokenFileSize = 4 << 10
+)
+
+var (
+	// ErrInvalidHostname возвращается для имени, которое нельзя использовать как домен страницы
+	ErrInvalidHostname = errors.New("некорректное доменное имя")
+
+	// ErrVerificationFailed возвращается, если токен не найден ни в DNS, ни по HTTP
+	ErrVerificationFailed = errors.New("не удалось подтвердить владение доменом")
+)
+
+// Resolver получает TXT-записи домена. Ему соответствует *net.Resolver;
+// в тестах его заменяет заглушка
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Verifier проверяет, что владелец домена разместил выданный токен
+type Verifier struct {
+	resolver Resolver
+	client   *http.Client
+}
+
+// NewVerifier создает новый экземпляр Verifier. Если allowPrivate ложно,
+// HTTP-проверка не подключается к внутренним адресам
+func NewVerifier(resolver Resolver, timeout time.Duration, allowPrivate bool) *Verifier {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = urlpolicy.DialControl
+	}
+
+	return &Verifier{
+		resolver: resolver,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext:           dialer.DialContext,
+				ResponseHeaderTimeout: timeout,
+			},
+			Timeout: timeout,
+			// Файл должен отдаваться самим доменом, а не другим сайтом
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Verify проверяет токен указанным способом
+func (v *Verifier) Verify(ctx context.Context, method, hostname, token string) error {
+	switch method {
+	case MethodDNS:
+		return v.verifyDNS(ctx, hostname, token)
+	case MethodHTTP:
+		return v.verifyHTTP(ctx, hostname, token)
+	default:
+		return fmt.Errorf("%w: неизвестный способ %q", ErrVerificationFailed, method)
+	}
+}
+
+// verifyDNS ищет запись "multylink-verification=<token>" среди TXT-записей
+// _multylink-verification.<hostname>
+func (v *Verifier) verifyDNS(ctx context.Context, hostname, token string) error {
+	records, err := v.resolver.LookupTXT(ctx, TXTPrefix+hostname)
+	if err != nil {
+		return fmt.Errorf("%w: TXT-запись не найдена", ErrVerificationFailed)
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == TXTValuePrefix+token {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: TXT-запись не содержит токен", ErrVerificationFailed)
+}
+
+// verifyHTTP загружает http://<hostname>/.well-known/multylink-verification.txt
+// и сравнивает содержимое с токеном
+func (v *Verifier) verifyHTTP(ctx context.Context, hostname, token string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+hostname+WellKnownPath, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerificationFailed, err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: файл недоступен", ErrVerificationFailed)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: сервер ответил %d", ErrVerificationFailed, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenFileSize))
+	if err != nil || strings.TrimSpace(string(body)) != token {
+		return fmt.Errorf("%w: файл не содержит токен", ErrVerificationFailed)
+	}
+	return nil
+}
+
+// NormalizeHostname приводит имя к нижнему регистру в punycode без
+// завершающей точки и порта. IP-адреса и имена без точки не допускаются
+func NormalizeHostname(raw string) (string, error) {
+	host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(raw)), ".")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" || net.ParseIP(strings.Trim(host, "[]")) != nil || !strings.Contains(host, ".") {
+		return "", ErrInvalidHostname
+	}
+
+	host, err := idna.Lookup.ToASCII(host)
+	if err != nil || len(host) > 253 {
+		return "", ErrInvalidHostname
+	}
+	return host, nil
+}
@@ -0,0 +1,120 @@
+package domains
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeResolver возвращает заданные TXT-записи по имени
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return records, nil
+}
+
+func TestVerifyDNS(t *testing.T) {
+	resolver := fakeResolver{
+		TXTPrefix + "links.example.com": {"v=spf1 -all", " " + TXTValuePrefix + "secret "},
+		TXTPrefix + "other.example.com": {TXTValuePrefix + "another"},
+	}
+	v := NewVerifier(resolver, time.Second, false)
+
+	tests := []struct {
+		name    string
+		host    string
+		wantErr bool
+	}{
+		{"token found", "links.example.com", false},
+		{"different token", "other.example.com", true},
+		{"no record", "missing.example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Verify(context.Background(), MethodDNS, tt.host, "secret")
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Verify error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrVerificationFailed) {
+				t.Errorf("error %v is not ErrVerificationFailed", err)
+			}
+		})
+	}
+}
+
+func TestVerifyHTTP(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(WellKnownPath, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("secret\n"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	v := NewVerifier(fakeResolver{}, time.Second, true)
+	if err := v.Verify(context.Background(), MethodHTTP, host, "secret"); err != nil {
+		t.Errorf("Verify with matching token: %v", err)
+	}
+	if err := v.Verify(context.Background(), MethodHTTP, host, "other"); !errors.Is(err, ErrVerificationFailed) {
+		t.Errorf("Verify with wrong token error = %v, want ErrVerificationFailed", err)
+	}
+}
+
+func TestVerifyHTTPDoesNotFollowRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("secret"))
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+WellKnownPath, http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	v := NewVerifier(fakeResolver{}, time.Second, true)
+	err := v.Verify(context.Background(), MethodHTTP, strings.TrimPrefix(redirect.URL, "http://"), "secret")
+	if !errors.Is(err, ErrVerificationFailed) {
+		t.Errorf("Verify through redirect error = %v, want ErrVerificationFailed", err)
+	}
+}
+
+func TestVerifyHTTPRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("secret"))
+	}))
+	defer server.Close()
+
+	v := NewVerifier(fakeResolver{}, time.Second, false)
+	err := v.Verify(context.Background(), MethodHTTP, strings.TrimPrefix(server.URL, "http://"), "secret")
+	if !errors.Is(err, ErrVerificationFailed) {
+		t.Errorf("Verify against loopback error = %v, want ErrVerificationFailed", err)
+	}
+}
+
+func TestNormalizeHostname(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"Links.Example.COM.", "links.example.com", false},
+		{"links.example.com:8443", "links.example.com", false},
+		{"пример.рф", "xn--e1afmkfd.xn--p1ai", false},
+		{"localhost", "", true},
+		{"127.0.0.1", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeHostname(tt.raw)
+		if tt.wantErr != (err != nil) || got != tt.want {
+			t.Errorf("NormalizeHostname(%q) = %q, %v; want %q, wantErr %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
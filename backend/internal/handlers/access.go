@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/services"
+)
+
+// authorizeMultiLink проверяет, что мультиссылка из параметра :id принадлежит
+// текущему пользователю, и возвращает ее ID. При ошибке ответ уже отправлен
+func authorizeMultiLink(c *gin.Context, multiLinkService *services.MultiLinkService) (int64, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		return 0, false
+	}
+
+	multiLinkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID мультиссылки"})
+		return 0, false
+	}
+
+	// Проверка существования мультиссылки и прав доступа
+	multiLink, err := multiLinkService.GetMultiLinkByID(c.Request.Context(), multiLinkID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
+		return 0, false
+	}
+
+	if multiLink.UserID != userID.(int64) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен"})
+		return 0, false
+	}
+
+	return multiLinkID, true
+}
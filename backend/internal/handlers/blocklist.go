@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+)
+
+// BlocklistHandler обрабатывает запросы администраторов к списку блокировки
+type BlocklistHandler struct {
+	blocklistService *services.BlocklistService
+}
+
+// NewBlocklistHandler создает новый экземпляр BlocklistHandler
+func NewBlocklistHandler(blocklistService *services.BlocklistService) *BlocklistHandler {
+	return &BlocklistHandler{
+		blocklistService: blocklistService,
+	}
+}
+
+// GetBlockedDomains обрабатывает запрос на получение записей списка блокировки
+func (h *BlocklistHandler) GetBlockedDomains(c *gin.Context) {
+	domains, err := h.blocklistService.GetBlockedDomains(c.Request.Context())
+	if err != nil {
+		logError(c, "failed to get blocked domains", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении списка блокировки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, domains)
+}
+
+// AddBlockedDomain обрабатывает запрос на добавление записи в список блокировки
+func (h *BlocklistHandler) AddBlockedDomain(c *gin.Context) {
+	var req models.AddBlockedDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domain, err := h.blocklistService.AddBlockedDomain(c.Request.Context(), req.Pattern, req.Reason)
+	if errors.Is(err, blocklist.ErrInvalidPattern) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некорректный домен или ссылка"})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to add blocked domain", err, "pattern", req.Pattern)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при добавлении в список блокировки"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain)
+}
+
+// DeleteBlockedDomain обрабатывает запрос на удаление записи из списка блокировки
+func (h *BlocklistHandler) DeleteBlockedDomain(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID записи"})
+		return
+	}
+
+	err = h.blocklistService.DeleteBlockedDomain(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Запись не найдена"})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to delete blocked domain", err, "id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении из списка блокировки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Запись удалена из списка блокировки"})
+}
+
+// ReloadBlocklist обрабатывает запрос на немедленную перезагрузку списка блокировки
+func (h *BlocklistHandler) ReloadBlocklist(c *gin.Context) {
+	reloaded, err := h.blocklistService.Reload(c.Request.Context())
+	if err != nil {
+		logError(c, "failed to reload blocklist", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при перезагрузке списка блокировки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Список блокировки перезагружен", "file_changed": reloaded})
+}
+
+// GetReport обрабатывает запрос на получение отчета о мультиссылках
+// с заблокированными кнопками
+func (h *BlocklistHandler) GetReport(c *gin.Context) {
+	report, err := h.blocklistService.GetReport(c.Request.Context())
+	if err != nil {
+		logError(c, "failed to build blocklist report", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при формировании отчета"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
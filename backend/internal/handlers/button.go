@@ -1,53 +1,40 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
 	"mvp_multylink/backend/internal/services"
+	"mvp_multylink/backend/internal/theme"
+	"mvp_multylink/backend/internal/urlpolicy"
 )
 
-// ButtonHandler обрабатывает запросы, связанные с кнопками-ссылками
+// ButtonHandler обрабатывает запросы, связанные с кнопками-ссылками.
+// Изменения кнопок сохраняются в черновик мультиссылки
 type ButtonHandler struct {
 	multiLinkService *services.MultiLinkService
-	buttonService    *services.ButtonService
+	draftService     *services.DraftService
+	unfurlService    *services.UnfurlService
 }
 
 // NewButtonHandler создает новый экземпляр ButtonHandler
-func NewButtonHandler(multiLinkService *services.MultiLinkService, buttonService *services.ButtonService) *ButtonHandler {
+func NewButtonHandler(multiLinkService *services.MultiLinkService, draftService *services.DraftService, unfurlService *services.UnfurlService) *ButtonHandler {
 	return &ButtonHandler{
 		multiLinkService: multiLinkService,
-		buttonService:    buttonService,
+		draftService:     draftService,
+		unfurlService:    unfurlService,
 	}
 }
 
-// CreateButton обрабатывает запрос на создание новой кнопки-ссылки
+// CreateButton обрабатывает запрос на создание новой кнопки-ссылки в черновике
 func (h *ButtonHandler) CreateButton(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
-		return
-	}
-
-	multiLinkID, err := strconv.ParseInt(c.Param("multilink_id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID мультиссылки"})
-		return
-	}
-
-	// Проверка существования мультиссылки и прав доступа
-	multiLink, err := h.multiLinkService.GetMultiLinkByID(multiLinkID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
-		return
-	}
-
-	if multiLink.UserID != userID.(int64) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен"})
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
 		return
 	}
 
@@ -57,204 +44,142 @@ func (h *ButtonHandler) CreateButton(c *gin.Context) {
 		return
 	}
 
-	// Если позиция не указана, устанавливаем последнюю
-	if req.Position == 0 {
-		count, err := h.buttonService.GetButtonsCountByMultiLinkID(multiLinkID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении количества кнопок"})
-			return
-		}
-		req.Position = count + 1
-	}
+	// Пустой заголовок ссылки заполняем по предпросмотру страницы
+	h.unfurlService.Prefill(c.Request.Context(), &req)
 
-	button := models.LinkButton{
-		MultiLinkID: multiLinkID,
-		Title:       req.Title,
-		URL:         req.URL,
-		Icon:        req.Icon,
-		Color:       req.Color,
-		Position:    req.Position,
-		IsActive:    req.IsActive,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+	button, err := h.draftService.CreateButton(c.Request.Context(), multiLinkID, req)
+	if respondButtonValidationError(c, err) {
+		return
 	}
-
-	buttonID, err := h.buttonService.CreateButton(button)
 	if err != nil {
+		logError(c, "failed to create button", err, "multilink_id", multiLinkID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании кнопки"})
 		return
 	}
 
-	button.ID = buttonID
-
-	// Инициализация метрики для новой кнопки
-	metrics := models.LinkMetrics{
-		LinkButtonID: buttonID,
-		Clicks:       0,
-	}
-
-	_, err = h.buttonService.CreateButtonMetrics(metrics)
-	if err != nil {
-		// Логируем ошибку, но не прерываем выполнение
-		// TODO: добавить логирование
-	}
-
-	c.JSON(http.StatusCreated, gin.H{"button": button})
+	c.JSON(http.StatusCreated, gin.H{"button": button, "draft": true})
 }
 
-// UpdateButton обрабатывает запрос на обновление кнопки-ссылки
+// UpdateButton обрабатывает запрос на обновление кнопки-ссылки в черновике
 func (h *ButtonHandler) UpdateButton(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
 		return
 	}
 
-	buttonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	// Кнопки, созданные в черновике, имеют отрицательные ID
+	buttonID, err := strconv.ParseInt(c.Param("button_id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID кнопки"})
 		return
 	}
 
-	// Получение кнопки и проверка прав доступа
-	button, err := h.buttonService.GetButtonByID(buttonID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена"})
-		return
-	}
-
-	// Получение мультиссылки для проверки владельца
-	multiLink, err := h.multiLinkService.GetMultiLinkByID(button.MultiLinkID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при проверке прав доступа"})
-		return
-	}
-
-	if multiLink.UserID != userID.(int64) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен"})
-		return
-	}
-
 	var req models.UpdateLinkButtonRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Обновление полей кнопки
-	if req.Title != "" {
-		button.Title = req.Title
-	}
-
-	if req.URL != "" {
-		button.URL = req.URL
+	button, err := h.draftService.UpdateButton(c.Request.Context(), multiLinkID, buttonID, req)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена"})
+		return
 	}
-
-	button.Icon = req.Icon
-	button.Color = req.Color
-
-	if req.Position != 0 {
-		button.Position = req.Position
+	if respondButtonValidationError(c, err) {
+		return
 	}
-
-	button.IsActive = req.IsActive
-	button.UpdatedAt = time.Now()
-
-	err = h.buttonService.UpdateButton(button)
 	if err != nil {
+		logError(c, "failed to update button", err, "multilink_id", multiLinkID, "button_id", buttonID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении кнопки"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"button": button})
+	c.JSON(http.StatusOK, gin.H{"button": button, "draft": true})
 }
 
-// DeleteButton обрабатывает запрос на удаление кнопки-ссылки
+// DeleteButton обрабатывает запрос на удаление кнопки-ссылки из черновика
 func (h *ButtonHandler) DeleteButton(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
 		return
 	}
 
-	buttonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	buttonID, err := strconv.ParseInt(c.Param("button_id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID кнопки"})
 		return
 	}
 
-	// Получение кнопки и проверка прав доступа
-	button, err := h.buttonService.GetButtonByID(buttonID)
-	if err != nil {
+	err = h.draftService.DeleteButton(c.Request.Context(), multiLinkID, buttonID)
+	if errors.Is(err, repository.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена"})
 		return
 	}
-
-	// Получение мультиссылки для проверки владельца
-	multiLink, err := h.multiLinkService.GetMultiLinkByID(button.MultiLinkID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при проверке прав доступа"})
-		return
-	}
-
-	if multiLink.UserID != userID.(int64) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен"})
-		return
-	}
-
-	err = h.buttonService.DeleteButton(buttonID)
 	if err != nil {
+		logError(c, "failed to delete button", err, "multilink_id", multiLinkID, "button_id", buttonID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении кнопки"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Кнопка успешно удалена"})
+	c.JSON(http.StatusOK, gin.H{"message": "Кнопка удалена из черновика и будет перемещена в корзину после публикации"})
 }
 
-// ReorderButtons обрабатывает запрос на изменение порядка кнопок
+// ReorderButtons обрабатывает запрос на изменение порядка кнопок в черновике
 func (h *ButtonHandler) ReorderButtons(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
 		return
 	}
 
-	multiLinkID, err := strconv.ParseInt(c.Param("multilink_id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID мультиссылки"})
+	var buttonOrder []models.ButtonPosition
+	if err := c.ShouldBindJSON(&buttonOrder); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Проверка существования мультиссылки и прав доступа
-	multiLink, err := h.multiLinkService.GetMultiLinkByID(multiLinkID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
+	// Обновление позиций кнопок
+	err := h.draftService.ReorderButtons(c.Request.Context(), multiLinkID, buttonOrder)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена"})
 		return
 	}
-
-	if multiLink.UserID != userID.(int64) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен"})
+	if err != nil {
+		logError(c, "failed to reorder buttons", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении порядка кнопок"})
 		return
 	}
 
-	var buttonOrder []struct {
-		ID       int64 `json:"id"`
-		Position int   `json:"position"`
-	}
+	c.JSON(http.StatusOK, gin.H{"message": "Порядок кнопок успешно обновлен"})
+}
 
-	if err := c.ShouldBindJSON(&buttonOrder); err != nil {
+// respondButtonValidationError отвечает 400, если ошибка вызвана некорректными
+// данными кнопки, и возвращает true. Нарушения политики ссылок и списка
+// блокировки сопровождаются кодом ошибки
+func respondButtonValidationError(c *gin.Context, err error) bool {
+	if violation, ok := urlViolation(err); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": violation.Message, "code": violation.Code})
+		return true
+	}
+	switch {
+	case errors.Is(err, services.ErrBlockedURL):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "blocked_url"})
+	case errors.Is(err, services.ErrInvalidSchedule),
+		errors.Is(err, services.ErrInvalidButtonLimits),
+		errors.Is(err, services.ErrInvalidButtonPayload),
+		errors.Is(err, services.ErrInvalidIcon),
+		errors.Is(err, theme.ErrInvalidTheme):
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Обновление позиций кнопок
-	for _, item := range buttonOrder {
-		err := h.buttonService.UpdateButtonPosition(item.ID, item.Position)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении порядка кнопок"})
-			return
-		}
+	case errors.Is(err, theme.ErrLowContrast):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "low_contrast"})
+	default:
+		return false
 	}
+	return true
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Порядок кнопок успешно обновлен"})
+// urlViolation извлекает нарушение политики ссылок из ошибки
+func urlViolation(err error) (*urlpolicy.Violation, bool) {
+	var violation *urlpolicy.Violation
+	ok := errors.As(err, &violation)
+	return violation, ok
 }
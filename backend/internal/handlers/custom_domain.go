@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/domains"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+)
+
+// CustomDomainHandler обрабатывает запросы, связанные с пользовательскими доменами
+type CustomDomainHandler struct {
+	multiLinkService *services.MultiLinkService
+	domainService    *services.CustomDomainService
+}
+
+// NewCustomDomainHandler создает новый экземпляр CustomDomainHandler
+func NewCustomDomainHandler(multiLinkService *services.MultiLinkService, domainService *services.CustomDomainService) *CustomDomainHandler {
+	return &CustomDomainHandler{
+		multiLinkService: multiLinkService,
+		domainService:    domainService,
+	}
+}
+
+// GetDomains обрабатывает запрос на получение доменов мультиссылки
+func (h *CustomDomainHandler) GetDomains(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	list, err := h.domainService.GetDomains(c.Request.Context(), multiLinkID)
+	if err != nil {
+		logError(c, "failed to get custom domains", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении доменов"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"domains": list})
+}
+
+// AddDomain обрабатывает запрос на привязку домена к мультиссылке.
+// В ответе возвращается инструкция по подтверждению владения доменом
+func (h *CustomDomainHandler) AddDomain(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	var req models.AddCustomDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domain, err := h.domainService.AddDomain(c.Request.Context(), multiLinkID, req.Hostname)
+	switch {
+	case errors.Is(err, domains.ErrInvalidHostname):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrDomainExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logError(c, "failed to add custom domain", err, "multilink_id", multiLinkID, "hostname", req.Hostname)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при добавлении домена"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain)
+}
+
+// VerifyDomain обрабатывает запрос на подтверждение владения доменом
+func (h *CustomDomainHandler) VerifyDomain(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	domainID, err := strconv.ParseInt(c.Param("domain_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID домена"})
+		return
+	}
+
+	var req models.VerifyCustomDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domain, err := h.domainService.VerifyDomain(c.Request.Context(), multiLinkID, domainID, req.Method)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Домен не найден"})
+		return
+	case errors.Is(err, domains.ErrVerificationFailed):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrDomainTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logError(c, "failed to verify custom domain", err, "multilink_id", multiLinkID, "domain_id", domainID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при подтверждении домена"})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
+
+// DeleteDomain обрабатывает запрос на отвязку домена от мультиссылки
+func (h *CustomDomainHandler) DeleteDomain(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	domainID, err := strconv.ParseInt(c.Param("domain_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID домена"})
+		return
+	}
+
+	err = h.domainService.DeleteDomain(c.Request.Context(), multiLinkID, domainID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Домен не найден"})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to delete custom domain", err, "multilink_id", multiLinkID, "domain_id", domainID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении домена"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Домен отвязан"})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+	"mvp_multylink/backend/internal/signing"
+)
+
+// DraftHandler обрабатывает запросы, связанные с черновиками и их публикацией
+type DraftHandler struct {
+	multiLinkService *services.MultiLinkService
+	draftService     *services.DraftService
+}
+
+// NewDraftHandler создает новый экземпляр DraftHandler
+func NewDraftHandler(multiLinkService *services.MultiLinkService, draftService *services.DraftService) *DraftHandler {
+	return &DraftHandler{
+		multiLinkService: multiLinkService,
+		draftService:     draftService,
+	}
+}
+
+// GetDraft обрабатывает запрос на получение черновика мультиссылки. Если
+// черновика нет, возвращается опубликованное состояние
+func (h *DraftHandler) GetDraft(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	draft, hasChanges, err := h.draftService.GetDraft(c.Request.Context(), multiLinkID)
+	if err != nil {
+		logError(c, "failed to get draft", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении черновика"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"multilink":   draft.Snapshot.MultiLink,
+		"buttons":     draft.Snapshot.Buttons,
+		"has_changes": hasChanges,
+		"updated_at":  draft.UpdatedAt,
+	})
+}
+
+// PublishDraft обрабатывает запрос на публикацию черновика
+func (h *DraftHandler) PublishDraft(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	revision, err := h.draftService.Publish(c.Request.Context(), multiLinkID)
+	switch {
+	case errors.Is(err, services.ErrNoDraft):
+		c.JSON(http.StatusConflict, gin.H{"error": "Нет неопубликованных изменений"})
+		return
+	case errors.Is(err, services.ErrSlugTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Такой slug уже используется"})
+		return
+	case err != nil:
+		logError(c, "failed to publish draft", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при публикации черновика"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revision": revision})
+}
+
+// DiscardDraft обрабатывает запрос на удаление черновика
+func (h *DraftHandler) DiscardDraft(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	if err := h.draftService.DiscardDraft(c.Request.Context(), multiLinkID); err != nil {
+		logError(c, "failed to discard draft", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении черновика"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Черновик удален"})
+}
+
+// CreatePreviewToken обрабатывает запрос на создание ссылки предпросмотра черновика
+func (h *DraftHandler) CreatePreviewToken(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	token, expiresAt := h.draftService.CreatePreviewToken(multiLinkID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":      token,
+		"path":       "/api/public/preview/" + token,
+		"expires_at": expiresAt,
+	})
+}
+
+// GetPreview обрабатывает публичный запрос на предпросмотр черновика по токену
+func (h *DraftHandler) GetPreview(c *gin.Context) {
+	// Предпросмотр не должен попадать в поисковую выдачу и кэши
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Cache-Control", "private, no-store")
+
+	preview, err := h.draftService.GetPreview(c.Request.Context(), c.Param("token"))
+	switch {
+	case errors.Is(err, signing.ErrExpiredToken):
+		c.JSON(http.StatusGone, gin.H{"error": "Срок действия ссылки предпросмотра истек"})
+		return
+	case errors.Is(err, signing.ErrInvalidToken):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ссылка предпросмотра недействительна"})
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
+		return
+	case err != nil:
+		logError(c, "failed to get draft preview", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении предпросмотра"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/buildinfo"
+	"mvp_multylink/backend/internal/migrations"
+)
+
+// CheckFunc проверяет готовность одной зависимости
+type CheckFunc func(ctx context.Context) error
+
+// HealthHandler обрабатывает запросы liveness, readiness и информации о сборке
+type HealthHandler struct {
+	db           *sql.DB
+	checkTimeout time.Duration
+
+	mu     sync.RWMutex
+	names  []string
+	checks map[string]CheckFunc
+}
+
+// NewHealthHandler создает новый экземпляр HealthHandler с проверками
+// подключения к базе данных и версии схемы
+func NewHealthHandler(db *sql.DB, checkTimeout time.Duration) *HealthHandler {
+	h := &HealthHandler{
+		db:           db,
+		checkTimeout: checkTimeout,
+		checks:       make(map[string]CheckFunc),
+	}
+
+	h.AddCheck("database", func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	})
+	h.AddCheck("migrations", func(ctx context.Context) error {
+		current, err := migrations.CurrentVersion(ctx, db)
+		if err != nil {
+			return err
+		}
+		if latest := migrations.LatestVersion(); current != latest {
+			return fmt.Errorf("schema version %d, expected %d", current, latest)
+		}
+		return nil
+	})
+
+	return h
+}
+
+// AddCheck регистрирует дополнительную проверку готовности
+func (h *HealthHandler) AddCheck(name string, check CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, exists := h.checks[name]; !exists {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = check
+}
+
+// Livez сообщает, что процесс жив. Внешние зависимости не проверяются
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz выполняет все проверки готовности параллельно с общим таймаутом
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.checkTimeout)
+	defer cancel()
+
+	h.mu.RLock()
+	names := append([]string(nil), h.names...)
+	checks := make([]CheckFunc, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.RUnlock()
+
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			results[i] = check(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	report := make(gin.H, len(names))
+	for i, name := range names {
+		if results[i] != nil {
+			status = http.StatusServiceUnavailable
+			report[name] = gin.H{"status": "fail", "error": results[i].Error()}
+			logError(c, "readiness check failed", results[i], "check", name)
+			continue
+		}
+		report[name] = gin.H{"status": "ok"}
+	}
+
+	overall := "ok"
+	if status != http.StatusOK {
+		overall = "fail"
+	}
+	c.JSON(status, gin.H{"status": overall, "checks": report})
+}
+
+// Version возвращает информацию о сборке и версии схемы базы данных
+func (h *HealthHandler) Version(c *gin.Context) {
+	response := gin.H{
+		"build":                   buildinfo.Get(),
+		"expected_schema_version": migrations.LatestVersion(),
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.checkTimeout)
+	defer cancel()
+	if version, err := migrations.CurrentVersion(ctx, h.db); err == nil {
+		response["schema_version"] = version
+	}
+
+	c.JSON(http.StatusOK, response)
+}
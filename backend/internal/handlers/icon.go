@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/icons"
+)
+
+// iconsPath — путь, по которому отдаются значки встроенного набора
+const iconsPath = "/static/icons/"
+
+// IconHandler обрабатывает запросы к встроенному набору значков
+type IconHandler struct {
+	baseURL string
+}
+
+// NewIconHandler создает новый экземпляр IconHandler. Адреса значков в списке
+// строятся от baseURL
+func NewIconHandler(baseURL string) *IconHandler {
+	return &IconHandler{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// iconItem представляет значок в списке встроенного набора
+type iconItem struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// GetIcons обрабатывает запрос на получение списка значков. Адрес значка
+// содержит отпечаток содержимого, поэтому после обновления значка меняется
+func (h *IconHandler) GetIcons(c *gin.Context) {
+	names := icons.Names()
+	items := make([]iconItem, 0, len(names))
+	for _, name := range names {
+		icon, _ := icons.Get(name)
+		items = append(items, iconItem{
+			Name: name,
+			URL:  h.baseURL + iconsPath + name + ".svg?v=" + icon.Version,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"icons": items})
+}
+
+// ServeIcon отдает SVG-значок. Значки встроены в сервер и меняются только
+// с новой версией, поэтому кэшируются на год
+func (h *IconHandler) ServeIcon(c *gin.Context) {
+	name, ok := strings.CutSuffix(c.Param("file"), ".svg")
+	icon, found := icons.Get(name)
+	if !ok || !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Значок не найден"})
+		return
+	}
+
+	etag := `"` + icon.Version + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	// SVG открывается и как самостоятельный документ, поэтому запрещаем
+	// скрипты и внешние ресурсы
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, "image/svg+xml", icon.SVG)
+}
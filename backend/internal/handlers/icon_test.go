@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/icons"
+)
+
+func newIconRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/static/icons/:file", NewIconHandler("https://api.example.com").ServeIcon)
+	return router
+}
+
+func TestServeIcon(t *testing.T) {
+	router := newIconRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/static/icons/youtube.svg", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	icon, _ := icons.Get("youtube")
+	if got := w.Header().Get("Content-Type"); got != "image/svg+xml" {
+		t.Errorf("Content-Type = %q, want image/svg+xml", got)
+	}
+	if w.Header().Get("Content-Security-Policy") == "" {
+		t.Error("SVG must be served with Content-Security-Policy")
+	}
+	if w.Body.String() != string(icon.SVG) {
+		t.Error("body must be the embedded icon")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/static/icons/youtube.svg", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("conditional request status = %d, want 304", w.Code)
+	}
+}
+
+func TestServeIconRejectsUnknownNamesAndTraversal(t *testing.T) {
+	router := newIconRouter()
+
+	for _, path := range []string{
+		"/static/icons/unknown.svg",
+		"/static/icons/youtube",
+		"/static/icons/youtube.png",
+		"/static/icons/YOUTUBE.svg",
+		"/static/icons/.svg",
+		"/static/icons/..%2Ficons.go",
+		"/static/icons/%2e%2e%2f%2e%2e%2fgo.mod",
+		"/static/icons/svg%2Fyoutube.svg",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("GET %s status = %d, want 404", path, w.Code)
+		}
+	}
+
+	// Параметр с разделителями пути, как если бы роутер его не отверг
+	for _, file := range []string{"../icons.go", "svg/youtube.svg", "../../go.mod"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/static/icons/x", nil)
+		c.Params = gin.Params{{Key: "file", Value: file}}
+		NewIconHandler("").ServeIcon(c)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("ServeIcon(%q) status = %d, want 404", file, w.Code)
+		}
+	}
+}
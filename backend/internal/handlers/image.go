@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/blobstore"
+	"mvp_multylink/backend/internal/images"
+	"mvp_multylink/backend/internal/services"
+)
+
+// multipartOverhead — запас на заголовки и границы multipart-запроса сверх
+// размера самого файла
+const multipartOverhead = 64 << 10
+
+// ImageHandler обрабатывает загрузку и отдачу изображений
+type ImageHandler struct {
+	imageService  *services.ImageService
+	maxUploadSize int64
+}
+
+// NewImageHandler создает новый экземпляр ImageHandler. Файлы больше
+// maxUploadSize байт не принимаются
+func NewImageHandler(imageService *services.ImageService, maxUploadSize int64) *ImageHandler {
+	return &ImageHandler{
+		imageService:  imageService,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+// Upload обрабатывает загрузку изображения в поле file multipart-формы.
+// Назначение (avatar, background, icon) задается в пути и определяет
+// стандартные размеры. В ответе возвращаются адреса всех размеров
+func (h *ImageHandler) Upload(c *gin.Context) {
+	kind := c.Param("kind")
+	if !images.ValidKind(kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": images.ErrUnknownKind.Error()})
+		return
+	}
+
+	tooLarge := fmt.Sprintf("Размер файла не должен превышать %d КБ", h.maxUploadSize>>10)
+	if c.Request.ContentLength > h.maxUploadSize+multipartOverhead {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLarge})
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLarge})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Файл изображения не передан"})
+		return
+	}
+	if header.Size > h.maxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLarge})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не удалось прочитать файл"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, h.maxUploadSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не удалось прочитать файл"})
+		return
+	}
+	if int64(len(data)) > h.maxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLarge})
+		return
+	}
+
+	uploaded, err := h.imageService.Upload(c.Request.Context(), kind, data)
+	switch {
+	case errors.Is(err, images.ErrUnsupportedFormat):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, images.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, images.ErrInvalidImage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		logError(c, "failed to upload image", err, "kind", kind, "size", len(data))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при сохранении изображения"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, uploaded)
+}
+
+// Serve отдает сохраненное изображение. Содержимое по адресу не меняется,
+// поэтому изображение кэшируется браузерами и CDN на год
+func (h *ImageHandler) Serve(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	etag := `"` + key + `"`
+	if c.GetHeader("If-None-Match") == etag {
+		c.Header("ETag", etag)
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	object, err := h.imageService.Open(c.Request.Context(), key)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Изображение не найдено"})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to open image", err, "key", key)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении изображения"})
+		return
+	}
+	defer object.Body.Close()
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	if !object.ModTime.IsZero() {
+		c.Header("Last-Modified", object.ModTime.UTC().Format(http.TimeFormat))
+	}
+	c.DataFromReader(http.StatusOK, object.Size, object.ContentType, object.Body, nil)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/blocklist"
+)
+
+// blockedTemplate — страница-предупреждение вместо перехода по заблокированной ссылке.
+// Сама ссылка не выводится, чтобы посетитель не перешел по ней случайно
+var blockedTemplate = template.Must(template.New("blocked").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Переход заблокирован</title>
+<style>
+body { font-family: system-ui, sans-serif; background: #fef2f2; color: #1f2937; margin: 0; }
+main { max-width: 32rem; margin: 15vh auto; padding: 2rem; background: #fff; border-radius: 1rem; box-shadow: 0 1px 3px rgba(0,0,0,.1); }
+h1 { color: #b91c1c; font-size: 1.5rem; margin-top: 0; }
+code { background: #f3f4f6; padding: .1rem .3rem; border-radius: .25rem; }
+</style>
+</head>
+<body>
+<main>
+<h1>Переход заблокирован</h1>
+<p>Эта кнопка ведет на ресурс <code>{{.Pattern}}</code>, который находится в списке опасных сайтов.</p>
+<p>Такие сайты могут пытаться украсть пароли, данные банковских карт или установить вредоносные программы.</p>
+</main>
+</body>
+</html>
+`))
+
+// renderBlockedInterstitial отвечает страницей-предупреждением о заблокированной ссылке
+func renderBlockedInterstitial(c *gin.Context, match blocklist.Match) {
+	var buf bytes.Buffer
+	if err := blockedTemplate.Execute(&buf, match); err != nil {
+		logError(c, "failed to render blocked interstitial", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Переход по ссылке заблокирован", "code": "blocked_url"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusForbidden, "text/html; charset=utf-8", buf.Bytes())
+}
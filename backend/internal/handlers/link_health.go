@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/services"
+)
+
+// LinkHealthHandler обрабатывает запросы о доступности ссылок кнопок
+type LinkHealthHandler struct {
+	multiLinkService  *services.MultiLinkService
+	buttonService     *services.ButtonService
+	linkHealthService *services.LinkHealthService
+}
+
+// NewLinkHealthHandler создает новый экземпляр LinkHealthHandler
+func NewLinkHealthHandler(multiLinkService *services.MultiLinkService, buttonService *services.ButtonService, linkHealthService *services.LinkHealthService) *LinkHealthHandler {
+	return &LinkHealthHandler{
+		multiLinkService:  multiLinkService,
+		buttonService:     buttonService,
+		linkHealthService: linkHealthService,
+	}
+}
+
+// GetButtonHealth обрабатывает запрос на получение состояния ссылки кнопки
+// и истории проверок
+func (h *LinkHealthHandler) GetButtonHealth(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	buttonID, err := strconv.ParseInt(c.Param("button_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID кнопки"})
+		return
+	}
+
+	button, err := h.buttonService.GetButtonByID(c.Request.Context(), buttonID)
+	if err != nil || button.MultiLinkID != multiLinkID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена"})
+		return
+	}
+
+	health, err := h.linkHealthService.GetButtonHealth(c.Request.Context(), button)
+	if err != nil {
+		logError(c, "failed to get button health", err, "multilink_id", multiLinkID, "button_id", buttonID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении состояния ссылки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, health)
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/logging"
+)
+
+// logError пишет ошибку в логгер текущего запроса с дополнительными атрибутами
+func logError(c *gin.Context, msg string, err error, args ...any) {
+	logging.FromContext(c.Request.Context()).Error(msg, append(args, "error", err)...)
+}
@@ -1,29 +1,41 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/logging"
 	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/monitoring"
 	"mvp_multylink/backend/internal/services"
+	"mvp_multylink/backend/internal/urlpolicy"
 )
 
 // MetricsHandler обрабатывает запросы, связанные с метриками
 type MetricsHandler struct {
-	multiLinkService *services.MultiLinkService
-	buttonService    *services.ButtonService
-	metricsService   *services.MetricsService
+	multiLinkService  *services.MultiLinkService
+	buttonService     *services.ButtonService
+	metricsService    *services.MetricsService
+	monitoring        *monitoring.Metrics
+	urlPolicy         *urlpolicy.Policy
+	blocklist         *blocklist.List
+	protectionService *services.ProtectionService
 }
 
 // NewMetricsHandler создает новый экземпляр MetricsHandler
-func NewMetricsHandler(multiLinkService *services.MultiLinkService, buttonService *services.ButtonService, metricsService *services.MetricsService) *MetricsHandler {
+func NewMetricsHandler(multiLinkService *services.MultiLinkService, buttonService *services.ButtonService, metricsService *services.MetricsService, monitoring *monitoring.Metrics, urlPolicy *urlpolicy.Policy, blocklist *blocklist.List, protectionService *services.ProtectionService) *MetricsHandler {
 	return &MetricsHandler{
-		multiLinkService: multiLinkService,
-		buttonService:    buttonService,
-		metricsService:   metricsService,
+		multiLinkService:  multiLinkService,
+		buttonService:     buttonService,
+		metricsService:    metricsService,
+		monitoring:        monitoring,
+		urlPolicy:         urlPolicy,
+		blocklist:         blocklist,
+		protectionService: protectionService,
 	}
 }
 
@@ -36,55 +48,120 @@ func (h *MetricsHandler) RecordClick(c *gin.Context) {
 	}
 
 	// Получение информации о кнопке
-	button, err := h.buttonService.GetButtonByID(buttonID)
+	button, err := h.buttonService.GetButtonByID(c.Request.Context(), buttonID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена"})
 		return
 	}
 
-	// Проверка активности кнопки
-	if !button.IsActive {
+	// Кнопки неактивной или не опубликованной по расписанию мультиссылки
+	// недоступны, как и сама страница
+	multiLink, err := h.multiLinkService.GetMultiLinkByID(c.Request.Context(), button.MultiLinkID)
+	if err != nil || !h.multiLinkService.IsPublished(multiLink) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена"})
+		return
+	}
+
+	// Переходы с защищенной страницы требуют того же доступа, что и сама страница
+	if !requireUnlocked(c, h.protectionService, multiLink) {
+		return
+	}
+
+	// Проверка активности кнопки и окна видимости
+	if !h.multiLinkService.IsButtonVisible(button) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Кнопка неактивна"})
 		return
 	}
 
-	// Получение UTM-меток из запроса
-	utmSource := c.Query("utm_source")
-	utmMedium := c.Query("utm_medium")
-	utmCampaign := c.Query("utm_campaign")
-	utmContent := c.Query("utm_content")
-	utmTerm := c.Query("utm_term")
+	// Заголовки и разделители не ведут по ссылке
+	if !services.IsClickableKind(button.Kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Кнопка не является ссылкой"})
+		return
+	}
 
-	// Создание события клика
-	clickEvent := models.ClickEvent{
-		LinkButtonID: buttonID,
-		IP:           c.ClientIP(),
-		UserAgent:    c.Request.UserAgent(),
-		Referer:      c.Request.Referer(),
-		UTMSource:    utmSource,
-		UTMMedium:    utmMedium,
-		UTMCampaign:  utmCampaign,
-		UTMContent:   utmContent,
-		UTMTerm:      utmTerm,
-		CreatedAt:    time.Now(),
-	}
-
-	// Запись события клика
-	_, err = h.metricsService.RecordClickEvent(clickEvent)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при записи события клика"})
+	// Переход на чувствительное содержимое требует подтверждения посетителя,
+	// роботы по таким ссылкам не перенаправляются
+	if reason, sensitive := services.ButtonSensitiveReason(multiLink, button); sensitive {
+		if crawler := isCrawler(c.Request.UserAgent()); crawler || !visitorConsent(c)[reason] {
+			renderConsentInterstitial(c, multiLink, reason, crawler)
+			return
+		}
+	}
+
+	// Повторная проверка ссылки перед переходом: политика и список
+	// блокировки могли измениться после сохранения кнопки
+	target := services.ButtonHref(button)
+	if !h.allowRedirect(c, button, target) {
 		return
 	}
 
-	// Обновление метрики кнопки
-	err = h.metricsService.IncrementButtonClicks(buttonID)
+	// Событие клика с UTM-метками из запроса
+	clickEvent := models.ClickEvent{
+		IP:          c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+		Referer:     c.Request.Referer(),
+		UTMSource:   c.Query("utm_source"),
+		UTMMedium:   c.Query("utm_medium"),
+		UTMCampaign: c.Query("utm_campaign"),
+		UTMContent:  c.Query("utm_content"),
+		UTMTerm:     c.Query("utm_term"),
+	}
+
+	// Списание клика, запись события и счетчика выполняются в одной транзакции.
+	// При исчерпании лимита или истечении срока посетитель уходит на запасной
+	// URL либо получает 410
+	result, err := h.buttonService.RecordClick(c.Request.Context(), button, clickEvent)
 	if err != nil {
-		// Логируем ошибку, но не прерываем выполнение
-		// TODO: добавить логирование
+		stage := "record_click"
+		var clickErr *services.ClickError
+		if errors.As(err, &clickErr) {
+			stage = clickErr.Stage
+		}
+		logError(c, "failed to record click", err, "multilink_id", button.MultiLinkID, "button_id", buttonID, "stage", stage)
+		h.monitoring.ClickFailed(stage)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при записи клика"})
+		return
 	}
+	if !result.Recorded {
+		if result.FallbackURL != "" {
+			if h.allowRedirect(c, button, result.FallbackURL) {
+				c.Redirect(http.StatusFound, result.FallbackURL)
+			}
+			return
+		}
+		c.JSON(http.StatusGone, gin.H{"error": "Срок действия кнопки истек"})
+		return
+	}
+
+	h.monitoring.ClickRecorded()
+
+	// Перенаправление по исходящей ссылке, построенной по типу кнопки
+	c.Redirect(http.StatusFound, target)
+}
 
-	// Перенаправление на URL кнопки
-	c.Redirect(http.StatusFound, button.URL)
+// allowRedirect проверяет ссылку по политике и списку блокировки. При
+// нарушении политики отвечает 403 с кодом нарушения, для заблокированной
+// ссылки показывает страницу-предупреждение. Возвращает false, если переход запрещен
+func (h *MetricsHandler) allowRedirect(c *gin.Context, button models.LinkButton, target string) bool {
+	if match, blocked := h.blocklist.Match(target); blocked {
+		logging.FromContext(c.Request.Context()).Warn("redirect blocked by blocklist",
+			"multilink_id", button.MultiLinkID, "button_id", button.ID, "pattern", match.Pattern, "source", match.Source)
+		h.monitoring.ClickFailed("blocklist")
+		renderBlockedInterstitial(c, match)
+		return false
+	}
+
+	err := h.urlPolicy.Check(target)
+	if err == nil {
+		return true
+	}
+
+	violation, _ := urlViolation(err)
+	logging.FromContext(c.Request.Context()).Warn("redirect blocked by url policy",
+		"multilink_id", button.MultiLinkID, "button_id", button.ID, "code", violation.Code)
+	h.monitoring.ClickFailed("url_policy")
+	c.JSON(http.StatusForbidden, gin.H{"error": "Переход по ссылке заблокирован", "code": violation.Code})
+	return false
 }
 
 // GetMultiLinkMetrics обрабатывает запрос на получение метрик мультиссылки
@@ -95,14 +172,14 @@ func (h *MetricsHandler) GetMultiLinkMetrics(c *gin.Context) {
 		return
 	}
 
-	multiLinkID, err := strconv.ParseInt(c.Param("multilink_id"), 10, 64)
+	multiLinkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID мультиссылки"})
 		return
 	}
 
 	// Проверка существования мультиссылки и прав доступа
-	multiLink, err := h.multiLinkService.GetMultiLinkByID(multiLinkID)
+	multiLink, err := h.multiLinkService.GetMultiLinkByID(c.Request.Context(), multiLinkID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
 		return
@@ -114,8 +191,9 @@ func (h *MetricsHandler) GetMultiLinkMetrics(c *gin.Context) {
 	}
 
 	// Получение кнопок для мультиссылки
-	buttons, err := h.multiLinkService.GetLinkButtonsByMultiLinkID(multiLinkID)
+	buttons, err := h.multiLinkService.GetLinkButtonsByMultiLinkID(c.Request.Context(), multiLinkID)
 	if err != nil {
+		logError(c, "failed to get buttons", err, "multilink_id", multiLinkID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении кнопок"})
 		return
 	}
@@ -125,8 +203,9 @@ func (h *MetricsHandler) GetMultiLinkMetrics(c *gin.Context) {
 	buttonMetrics := make([]models.ButtonMetricsData, 0, len(buttons))
 
 	for _, button := range buttons {
-		metrics, err := h.metricsService.GetButtonMetrics(button.ID)
+		metrics, err := h.metricsService.GetButtonMetrics(c.Request.Context(), button.ID)
 		if err != nil {
+			logError(c, "failed to get button metrics", err, "multilink_id", multiLinkID, "button_id", button.ID)
 			continue // Пропускаем кнопку, если не удалось получить метрики
 		}
 
@@ -148,13 +227,15 @@ func (h *MetricsHandler) GetMultiLinkMetrics(c *gin.Context) {
 	}
 
 	// Получение статистики по UTM-меткам
-	utmSourceStats, err := h.metricsService.GetUTMSourceStats(multiLinkID)
+	utmSourceStats, err := h.metricsService.GetUTMSourceStats(c.Request.Context(), multiLinkID)
 	if err != nil {
+		logError(c, "failed to get utm_source stats", err, "multilink_id", multiLinkID)
 		utmSourceStats = make(map[string]int)
 	}
 
-	utmMediumStats, err := h.metricsService.GetUTMMediumStats(multiLinkID)
+	utmMediumStats, err := h.metricsService.GetUTMMediumStats(c.Request.Context(), multiLinkID)
 	if err != nil {
+		logError(c, "failed to get utm_medium stats", err, "multilink_id", multiLinkID)
 		utmMediumStats = make(map[string]int)
 	}
 
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,13 +14,19 @@ import (
 
 // MultiLinkHandler обрабатывает запросы, связанные с мультиссылками
 type MultiLinkHandler struct {
-	multiLinkService *services.MultiLinkService
+	multiLinkService  *services.MultiLinkService
+	draftService      *services.DraftService
+	protectionService *services.ProtectionService
+	ogCardService     *services.OGCardService
 }
 
 // NewMultiLinkHandler создает новый экземпляр MultiLinkHandler
-func NewMultiLinkHandler(multiLinkService *services.MultiLinkService) *MultiLinkHandler {
+func NewMultiLinkHandler(multiLinkService *services.MultiLinkService, draftService *services.DraftService, protectionService *services.ProtectionService, ogCardService *services.OGCardService) *MultiLinkHandler {
 	return &MultiLinkHandler{
-		multiLinkService: multiLinkService,
+		multiLinkService:  multiLinkService,
+		draftService:      draftService,
+		protectionService: protectionService,
+		ogCardService:     ogCardService,
 	}
 }
 
@@ -44,8 +51,9 @@ func (h *MultiLinkHandler) CreateMultiLink(c *gin.Context) {
 	}
 
 	// Проверка уникальности slug
-	exists, err := h.multiLinkService.CheckSlugExists(req.Slug)
+	exists, err := h.multiLinkService.CheckSlugExists(c.Request.Context(), req.Slug)
 	if err != nil {
+		logError(c, "failed to check slug", err, "slug", req.Slug)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при проверке slug"})
 		return
 	}
@@ -55,6 +63,11 @@ func (h *MultiLinkHandler) CreateMultiLink(c *gin.Context) {
 		return
 	}
 
+	if err := services.ValidatePublishWindow(req.PublishAt, req.UnpublishAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	multiLink := models.MultiLink{
 		UserID:      userID.(int64),
 		Title:       req.Title,
@@ -63,10 +76,14 @@ func (h *MultiLinkHandler) CreateMultiLink(c *gin.Context) {
 		IsActive:    req.IsActive,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+		PublishAt:   req.PublishAt,
+		UnpublishAt: req.UnpublishAt,
 	}
+	multiLink.Sensitive, multiLink.SensitiveReason = services.NormalizeSensitive(req.Sensitive, req.SensitiveReason)
 
-	multiLinkID, err := h.multiLinkService.CreateMultiLink(multiLink)
+	multiLinkID, err := h.multiLinkService.CreateMultiLink(c.Request.Context(), multiLink)
 	if err != nil {
+		logError(c, "failed to create multilink", err, "slug", req.Slug)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании мультиссылки"})
 		return
 	}
@@ -90,7 +107,7 @@ func (h *MultiLinkHandler) GetMultiLink(c *gin.Context) {
 		return
 	}
 
-	multiLink, err := h.multiLinkService.GetMultiLinkByID(multiLinkID)
+	multiLink, err := h.multiLinkService.GetMultiLinkByID(c.Request.Context(), multiLinkID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
 		return
@@ -103,19 +120,22 @@ func (h *MultiLinkHandler) GetMultiLink(c *gin.Context) {
 	}
 
 	// Получение кнопок для мультиссылки
-	buttons, err := h.multiLinkService.GetLinkButtonsByMultiLinkID(multiLinkID)
+	buttons, err := h.multiLinkService.GetLinkButtonsByMultiLinkID(c.Request.Context(), multiLinkID)
 	if err != nil {
+		logError(c, "failed to get buttons", err, "multilink_id", multiLinkID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении кнопок"})
 		return
 	}
 
+	pageTheme := services.ResolveTheme(multiLink)
 	c.JSON(http.StatusOK, models.MultiLinkResponse{
 		MultiLink: multiLink,
 		Buttons:   buttons,
+		Theme:     &pageTheme,
 	})
 }
 
-// UpdateMultiLink обрабатывает запрос на обновление мультиссылки
+// UpdateMultiLink обрабатывает запрос на обновление черновика мультиссылки
 func (h *MultiLinkHandler) UpdateMultiLink(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -130,7 +150,7 @@ func (h *MultiLinkHandler) UpdateMultiLink(c *gin.Context) {
 	}
 
 	// Проверка существования мультиссылки и прав доступа
-	multiLink, err := h.multiLinkService.GetMultiLinkByID(multiLinkID)
+	multiLink, err := h.multiLinkService.GetMultiLinkByID(c.Request.Context(), multiLinkID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
 		return
@@ -147,39 +167,23 @@ func (h *MultiLinkHandler) UpdateMultiLink(c *gin.Context) {
 		return
 	}
 
-	// Обновление полей мультиссылки
-	if req.Title != "" {
-		multiLink.Title = req.Title
+	// Изменения сохраняются в черновик и становятся видны после публикации
+	draftMultiLink, err := h.draftService.UpdateMultiLink(c.Request.Context(), multiLinkID, req)
+	if errors.Is(err, services.ErrSlugTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Такой slug уже используется"})
+		return
 	}
-
-	multiLink.Description = req.Description
-
-	if req.Slug != "" && req.Slug != multiLink.Slug {
-		// Проверка уникальности нового slug
-		exists, err := h.multiLinkService.CheckSlugExists(req.Slug)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при проверке slug"})
-			return
-		}
-
-		if exists {
-			c.JSON(http.StatusConflict, gin.H{"error": "Такой slug уже используется"})
-			return
-		}
-
-		multiLink.Slug = req.Slug
+	if errors.Is(err, services.ErrInvalidSchedule) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-
-	multiLink.IsActive = req.IsActive
-	multiLink.UpdatedAt = time.Now()
-
-	err = h.multiLinkService.UpdateMultiLink(multiLink)
 	if err != nil {
+		logError(c, "failed to update multilink draft", err, "multilink_id", multiLinkID, "slug", req.Slug)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении мультиссылки"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"multilink": multiLink})
+	c.JSON(http.StatusOK, gin.H{"multilink": draftMultiLink, "draft": true})
 }
 
 // DeleteMultiLink обрабатывает запрос на удаление мультиссылки
@@ -197,7 +201,7 @@ func (h *MultiLinkHandler) DeleteMultiLink(c *gin.Context) {
 	}
 
 	// Проверка существования мультиссылки и прав доступа
-	multiLink, err := h.multiLinkService.GetMultiLinkByID(multiLinkID)
+	multiLink, err := h.multiLinkService.GetMultiLinkByID(c.Request.Context(), multiLinkID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
 		return
@@ -208,13 +212,14 @@ func (h *MultiLinkHandler) DeleteMultiLink(c *gin.Context) {
 		return
 	}
 
-	err = h.multiLinkService.DeleteMultiLink(multiLinkID)
+	err = h.multiLinkService.DeleteMultiLink(c.Request.Context(), multiLinkID)
 	if err != nil {
+		logError(c, "failed to delete multilink", err, "multilink_id", multiLinkID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при удалении мультиссылки"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Мультиссылка успешно удалена"})
+	c.JSON(http.StatusOK, gin.H{"message": "Мультиссылка перемещена в корзину"})
 }
 
 // GetUserMultiLinks обрабатывает запрос на получение всех мультиссылок пользователя
@@ -225,8 +230,9 @@ func (h *MultiLinkHandler) GetUserMultiLinks(c *gin.Context) {
 		return
 	}
 
-	multiLinks, err := h.multiLinkService.GetMultiLinksByUserID(userID.(int64))
+	multiLinks, err := h.multiLinkService.GetMultiLinksByUserID(c.Request.Context(), userID.(int64))
 	if err != nil {
+		logError(c, "failed to get user multilinks", err, "user_id", userID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении мультиссылок"})
 		return
 	}
@@ -241,27 +247,82 @@ func (h *MultiLinkHandler) GetUserMultiLinks(c *gin.Context) {
 func (h *MultiLinkHandler) GetPublicMultiLink(c *gin.Context) {
 	slug := c.Param("slug")
 
-	multiLink, err := h.multiLinkService.GetMultiLinkBySlug(slug)
+	multiLink, err := h.multiLinkService.GetMultiLinkBySlug(c.Request.Context(), slug)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
 		return
 	}
 
-	// Проверка активности мультиссылки
-	if !multiLink.IsActive {
+	// Проверка активности мультиссылки и расписания публикации
+	if !h.multiLinkService.IsPublished(multiLink) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена или неактивна"})
 		return
 	}
 
+	// Роботы и сервисы предпросмотра получают нейтральную страницу вместо чувствительной
+	crawler := isCrawler(c.Request.UserAgent())
+	pageTheme := services.ResolveTheme(multiLink)
+	if _, sensitive := services.MultiLinkSensitiveReason(multiLink); sensitive && crawler {
+		c.JSON(http.StatusOK, models.MultiLinkResponse{
+			MultiLink: services.NeutralMultiLink(multiLink),
+			Buttons:   []models.LinkButton{},
+			Theme:     &pageTheme,
+			OGImage:   h.ogCardService.ImageURL(multiLink),
+		})
+		return
+	}
+
+	// Защищенная страница открывается только после ввода пароля
+	if !requireUnlocked(c, h.protectionService, multiLink) {
+		return
+	}
+
+	// Чувствительная страница открывается после подтверждения посетителя
+	consent := visitorConsent(c)
+	if !requireConsent(c, multiLink, consent) {
+		return
+	}
+
 	// Получение кнопок для мультиссылки
-	buttons, err := h.multiLinkService.GetActiveLinkButtonsByMultiLinkID(multiLink.ID)
+	buttons, err := h.multiLinkService.GetActiveLinkButtonsByMultiLinkID(c.Request.Context(), multiLink.ID)
 	if err != nil {
+		logError(c, "failed to get active buttons", err, "multilink_id", multiLink.ID, "slug", slug)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении кнопок"})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.MultiLinkResponse{
 		MultiLink: multiLink,
-		Buttons:   buttons,
+		Buttons:   gateSensitiveButtons(buttons, consent, crawler),
+		Theme:     &pageTheme,
+		OGImage:   h.ogCardService.ImageURL(multiLink),
 	})
 }
+
+// GetPublicCard обрабатывает запрос карточки страницы для og:image. Карточка
+// не раскрывает содержимое защищенных паролем и чувствительных страниц
+func (h *MultiLinkHandler) GetPublicCard(c *gin.Context) {
+	slug := c.Param("slug")
+
+	multiLink, err := h.multiLinkService.GetMultiLinkBySlug(c.Request.Context(), slug)
+	if err != nil || !h.multiLinkService.IsPublished(multiLink) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена или неактивна"})
+		return
+	}
+
+	data, fingerprint, err := h.ogCardService.Card(c.Request.Context(), multiLink)
+	if err != nil {
+		logError(c, "failed to render og card", err, "multilink_id", multiLink.ID, "slug", slug)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании карточки"})
+		return
+	}
+
+	etag := `"` + fingerprint + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=3600")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "image/png", data)
+}
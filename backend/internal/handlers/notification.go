@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+)
+
+// NotificationHandler обрабатывает запросы, связанные с уведомлениями пользователя
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler создает новый экземпляр NotificationHandler
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications обрабатывает запрос на получение уведомлений пользователя
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		return
+	}
+
+	notifications, err := h.notificationService.GetNotifications(c.Request.Context(), userID.(int64))
+	if err != nil {
+		logError(c, "failed to get notifications", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении уведомлений"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkRead обрабатывает запрос на отметку уведомления прочитанным
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID уведомления"})
+		return
+	}
+
+	err = h.notificationService.MarkRead(c.Request.Context(), id, userID.(int64))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Уведомление не найдено"})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to mark notification read", err, "user_id", userID, "notification_id", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при обновлении уведомления"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Уведомление отмечено прочитанным"})
+}
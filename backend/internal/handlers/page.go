@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/icons"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/ogcard"
+	"mvp_multylink/backend/internal/services"
+)
+
+// pageTemplate — публичная страница мультиссылки в виде HTML-документа.
+// Кнопки ведут на обработчик переходов, поэтому клики учитываются так же,
+// как при встраивании. Для защищенной страницы выводится форма ввода пароля.
+// Теги Open Graph с карточкой страницы читают сервисы предпросмотра ссылок
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{if .NotFound}}<meta name="robots" content="noindex">{{end}}
+<title>{{.Title}}</title>
+{{if .Description}}<meta name="description" content="{{.Description}}">{{end}}
+{{if .OGImage}}<meta property="og:type" content="website">
+<meta property="og:title" content="{{.Title}}">
+{{if .Description}}<meta property="og:description" content="{{.Description}}">{{end}}
+<meta property="og:image" content="{{.OGImage}}">
+<meta property="og:image:type" content="image/png">
+<meta property="og:image:width" content="{{.OGImageWidth}}">
+<meta property="og:image:height" content="{{.OGImageHeight}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="{{.OGImage}}">{{end}}
+<style>
+body { font-family: {{.Theme.FontFamily}}; background: {{.Theme.BackgroundColor}}; color: {{.Theme.TextColor}}; margin: 0; }
+main { max-width: 32rem; margin: 0 auto; padding: 3rem 1rem; text-align: center; }
+h1 { font-size: 1.5rem; margin: 0 0 .5rem; }
+h2 { font-size: 1.1rem; margin: 1.5rem 0 .5rem; }
+hr { border: 0; border-top: 1px solid currentColor; opacity: .3; margin: 1.5rem 0; }
+a.button, button { display: flex; align-items: center; justify-content: center; gap: .5rem; margin: .75rem 0; padding: .8rem 1rem; background: {{.Theme.ButtonColor}}; color: {{.Theme.ButtonTextColor}}; border: 0; border-radius: {{.Theme.ButtonRadius}}; text-decoration: none; font: inherit; width: 100%; cursor: pointer; }
+a.button svg, a.button img { width: 1.25rem; height: 1.25rem; flex: none; }
+input { box-sizing: border-box; width: 100%; padding: .8rem 1rem; font: inherit; border: 1px solid currentColor; border-radius: {{.Theme.ButtonRadius}}; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .UnlockURL}}<form id="unlock">
+<input type="password" name="password" required autofocus aria-label="Пароль">
+<button type="submit">Открыть</button>
+<p id="unlock-error" role="alert"></p>
+</form>
+<script>
+document.getElementById("unlock").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const response = await fetch({{.UnlockURL}}, {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify({password: event.target.password.value}),
+  });
+  if (response.ok) {
+    location.reload();
+    return;
+  }
+  const body = await response.json().catch(() => ({}));
+  document.getElementById("unlock-error").textContent = body.error || "Не удалось открыть страницу";
+});
+</script>{{end}}
+{{range .Buttons}}{{if eq .Kind "header"}}<h2>{{.Title}}</h2>
+{{else if eq .Kind "divider"}}<hr>
+{{else}}<a class="button" href="{{.Href}}" rel="nofollow"{{if .Color}} style="background: {{.Color}}"{{end}}>{{.Icon}}{{if .IconURL}}<img src="{{.IconURL}}" alt="">{{end}}<span>{{.Title}}</span></a>
+{{end}}{{end}}
+</main>
+</body>
+</html>
+`))
+
+// pageData содержит данные публичной страницы
+type pageData struct {
+	Title       string
+	Description string
+	// OGImage — адрес карточки страницы для og:image. Пустой адрес
+	// отключает теги Open Graph
+	OGImage       string
+	OGImageWidth  int
+	OGImageHeight int
+	NotFound      bool
+	UnlockURL     string
+	Buttons       []pageButton
+	Theme         consentTheme
+}
+
+// pageButton — кнопка на публичной странице. Встроенный значок выводится
+// как SVG, загруженное изображение — по адресу
+type pageButton struct {
+	Kind    string
+	Title   string
+	Href    string
+	Icon    template.HTML
+	IconURL string
+	Color   template.CSS
+}
+
+// newPageData подготавливает данные страницы мультиссылки без кнопок.
+// Карточка для og:image рисуется по исходной мультиссылке: для защищенных
+// и чувствительных страниц она не раскрывает содержимое
+func (h *MultiLinkHandler) newPageData(page, multiLink models.MultiLink) pageData {
+	return pageData{
+		Title:         page.Title,
+		Description:   page.Description,
+		OGImage:       h.ogCardService.ImageURL(multiLink),
+		OGImageWidth:  ogcard.Width,
+		OGImageHeight: ogcard.Height,
+		Theme:         newConsentTheme(services.ResolveTheme(page)),
+	}
+}
+
+// newPageButtons подготавливает кнопки страницы. Ссылки ведут на обработчик
+// переходов по относительному адресу, который доступен и на пользовательском домене
+func newPageButtons(buttons []models.LinkButton) []pageButton {
+	items := make([]pageButton, 0, len(buttons))
+	for _, b := range buttons {
+		item := pageButton{Kind: b.Kind, Title: b.Title, Color: template.CSS(b.Color)}
+		if services.IsClickableKind(b.Kind) {
+			item.Href = "/api/public/click/" + strconv.FormatInt(b.ID, 10)
+		}
+		// Значки встроенного набора и адреса изображений проверяются при сохранении кнопки
+		if icon, ok := icons.Get(b.Icon); ok {
+			item.Icon = template.HTML(string(icon.SVG))
+		} else {
+			item.IconURL = b.Icon
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// GetPublicPage отдает публичную страницу мультиссылки HTML-документом. Его
+// получают посетители пользовательского домена. Проверки публикации, пароля
+// и согласия совпадают с GetPublicMultiLink
+func (h *MultiLinkHandler) GetPublicPage(c *gin.Context) {
+	slug := c.Param("slug")
+
+	multiLink, err := h.multiLinkService.GetMultiLinkBySlug(c.Request.Context(), slug)
+	if err != nil || !h.multiLinkService.IsPublished(multiLink) {
+		renderPage(c, http.StatusNotFound, pageData{
+			Title:    "Страница не найдена",
+			NotFound: true,
+			Theme:    newConsentTheme(services.ResolveTheme(models.MultiLink{})),
+		})
+		return
+	}
+
+	// Роботы и сервисы предпросмотра получают нейтральную страницу вместо чувствительной
+	crawler := isCrawler(c.Request.UserAgent())
+	reason, sensitive := services.MultiLinkSensitiveReason(multiLink)
+	if sensitive && crawler {
+		renderPage(c, http.StatusOK, h.newPageData(services.NeutralMultiLink(multiLink), multiLink))
+		return
+	}
+
+	// Защищенная страница открывается только после ввода пароля. До этого
+	// заголовок и описание не раскрываются, как и на карточке страницы
+	if !h.protectionService.IsUnlocked(multiLink, accessToken(c, multiLink.ID)) {
+		locked := multiLink
+		locked.Title = "Страница защищена паролем"
+		locked.Description = ""
+		data := h.newPageData(locked, multiLink)
+		data.UnlockURL = "/api/public/multilinks/" + url.PathEscape(multiLink.Slug) + "/unlock"
+		renderPage(c, http.StatusUnauthorized, data)
+		return
+	}
+
+	// Чувствительная страница открывается после подтверждения посетителя
+	consent := visitorConsent(c)
+	if sensitive && !consent[reason] {
+		renderConsentInterstitial(c, multiLink, reason, false)
+		return
+	}
+
+	buttons, err := h.multiLinkService.GetActiveLinkButtonsByMultiLinkID(c.Request.Context(), multiLink.ID)
+	if err != nil {
+		logError(c, "failed to get active buttons", err, "multilink_id", multiLink.ID, "slug", slug)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении кнопок"})
+		return
+	}
+
+	data := h.newPageData(multiLink, multiLink)
+	data.Buttons = newPageButtons(gateSensitiveButtons(buttons, consent, crawler))
+	renderPage(c, http.StatusOK, data)
+}
+
+// renderPage отвечает HTML-документом публичной страницы. Содержимое зависит
+// от cookie доступа и согласия, поэтому не кэшируется общими кэшами
+func renderPage(c *gin.Context, status int, data pageData) {
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, data); err != nil {
+		logError(c, "failed to render public page", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при отображении страницы"})
+		return
+	}
+	c.Header("Cache-Control", "private, no-cache")
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/monitoring"
+	"mvp_multylink/backend/internal/services"
+)
+
+// accessTokenHeader передает токен доступа к защищенной странице, если
+// cookie недоступны, например при встраивании страницы на другой сайт
+const accessTokenHeader = "X-Access-Token"
+
+// ProtectionHandler обрабатывает запросы, связанные с защитой страниц паролем
+type ProtectionHandler struct {
+	multiLinkService  *services.MultiLinkService
+	protectionService *services.ProtectionService
+	monitoring        *monitoring.Metrics
+}
+
+// NewProtectionHandler создает новый экземпляр ProtectionHandler
+func NewProtectionHandler(multiLinkService *services.MultiLinkService, protectionService *services.ProtectionService, monitoring *monitoring.Metrics) *ProtectionHandler {
+	return &ProtectionHandler{
+		multiLinkService:  multiLinkService,
+		protectionService: protectionService,
+		monitoring:        monitoring,
+	}
+}
+
+// SetPassword обрабатывает запрос на установку или смену пароля мультиссылки
+func (h *ProtectionHandler) SetPassword(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	var req models.SetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.protectionService.SetPassword(c.Request.Context(), multiLinkID, req.Password); err != nil {
+		logError(c, "failed to set multilink password", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при установке пароля"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пароль установлен"})
+}
+
+// RemovePassword обрабатывает запрос на снятие пароля с мультиссылки
+func (h *ProtectionHandler) RemovePassword(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	if err := h.protectionService.RemovePassword(c.Request.Context(), multiLinkID); err != nil {
+		logError(c, "failed to remove multilink password", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при снятии пароля"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Пароль снят"})
+}
+
+// Unlock обрабатывает ввод пароля посетителем. Токен доступа возвращается
+// в ответе и сохраняется в cookie для страницы и переходов по ее кнопкам
+func (h *ProtectionHandler) Unlock(c *gin.Context) {
+	slug := c.Param("slug")
+
+	multiLink, err := h.multiLinkService.GetMultiLinkBySlug(c.Request.Context(), slug)
+	if err != nil || !h.multiLinkService.IsPublished(multiLink) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена или неактивна"})
+		return
+	}
+
+	var req models.UnlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	access, err := h.protectionService.Unlock(multiLink, c.ClientIP(), req.Password)
+	switch {
+	case errors.Is(err, services.ErrTooManyAttempts):
+		h.monitoring.RateLimitRejected(c.FullPath())
+		retryAfter := int(math.Ceil(h.protectionService.RetryAfter(slug, c.ClientIP()).Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Слишком много попыток ввода пароля, попробуйте позже"})
+		return
+	case errors.Is(err, services.ErrWrongPassword):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверный пароль"})
+		return
+	case err != nil:
+		logError(c, "failed to unlock multilink", err, "slug", slug)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при проверке пароля"})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(accessCookieName(multiLink.ID), access.Token, int(time.Until(access.ExpiresAt).Seconds()),
+		"/", "", isSecureRequest(c), true)
+	c.JSON(http.StatusOK, access)
+}
+
+// requireUnlocked проверяет доступ посетителя к защищенной странице. Если
+// пароль не введен, отвечает 401 с запросом пароля
+func requireUnlocked(c *gin.Context, protectionService *services.ProtectionService, multiLink models.MultiLink) bool {
+	if protectionService.IsUnlocked(multiLink, accessToken(c, multiLink.ID)) {
+		return true
+	}
+	c.JSON(http.StatusUnauthorized, models.PasswordChallenge{PasswordRequired: true, Slug: multiLink.Slug})
+	return false
+}
+
+// accessToken получает токен доступа к странице из cookie, заголовка
+// X-Access-Token или параметра access_token (для ссылок на переходы)
+func accessToken(c *gin.Context, multiLinkID int64) string {
+	if token, err := c.Cookie(accessCookieName(multiLinkID)); err == nil && token != "" {
+		return token
+	}
+	if token := c.GetHeader(accessTokenHeader); token != "" {
+		return token
+	}
+	return c.Query("access_token")
+}
+
+func accessCookieName(multiLinkID int64) string {
+	return "multilink_access_" + strconv.FormatInt(multiLinkID, 10)
+}
+
+func isSecureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/qr"
+	"mvp_multylink/backend/internal/services"
+	"mvp_multylink/backend/internal/unfurl"
+)
+
+// QRHandler обрабатывает запросы QR-кодов страниц и кнопок
+type QRHandler struct {
+	multiLinkService *services.MultiLinkService
+	buttonService    *services.ButtonService
+	qrService        *services.QRService
+}
+
+// NewQRHandler создает новый экземпляр QRHandler
+func NewQRHandler(multiLinkService *services.MultiLinkService, buttonService *services.ButtonService, qrService *services.QRService) *QRHandler {
+	return &QRHandler{
+		multiLinkService: multiLinkService,
+		buttonService:    buttonService,
+		qrService:        qrService,
+	}
+}
+
+// GetMultiLinkQR обрабатывает запрос QR-кода публичной страницы
+func (h *QRHandler) GetMultiLinkQR(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	multiLink, err := h.multiLinkService.GetMultiLinkByID(c.Request.Context(), multiLinkID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
+		return
+	}
+
+	h.render(c, h.qrService.MultiLinkURL(multiLink), "qr-"+multiLink.Slug)
+}
+
+// GetButtonQR обрабатывает запрос QR-кода перехода по кнопке
+func (h *QRHandler) GetButtonQR(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	buttonID, err := strconv.ParseInt(c.Param("button_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID кнопки"})
+		return
+	}
+
+	button, err := h.buttonService.GetButtonByID(c.Request.Context(), buttonID)
+	if err != nil || button.MultiLinkID != multiLinkID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена"})
+		return
+	}
+	if !services.IsClickableKind(button.Kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Кнопка не является ссылкой"})
+		return
+	}
+
+	h.render(c, h.qrService.ButtonURL(button), fmt.Sprintf("qr-button-%d", button.ID))
+}
+
+func (h *QRHandler) render(c *gin.Context, content, filename string) {
+	var req models.QRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, contentType, err := h.qrService.Render(c.Request.Context(), content, req)
+	if errors.Is(err, qr.ErrInvalidOptions) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Ссылка на логотип проверяется так же, как ссылки кнопок
+	if respondButtonValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, unfurl.ErrFetch) || errors.Is(err, unfurl.ErrImage) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Не удалось загрузить логотип: " + err.Error()})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to render qr code", err, "content", content)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при создании QR-кода"})
+		return
+	}
+
+	if req.Format == qr.FormatSVG {
+		filename += ".svg"
+	} else {
+		filename += ".png"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Data(http.StatusOK, contentType, data)
+}
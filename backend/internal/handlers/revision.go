@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+)
+
+// RevisionHandler обрабатывает запросы, связанные с историей изменений мультиссылок
+type RevisionHandler struct {
+	multiLinkService *services.MultiLinkService
+	revisionService  *services.RevisionService
+}
+
+// NewRevisionHandler создает новый экземпляр RevisionHandler
+func NewRevisionHandler(multiLinkService *services.MultiLinkService, revisionService *services.RevisionService) *RevisionHandler {
+	return &RevisionHandler{
+		multiLinkService: multiLinkService,
+		revisionService:  revisionService,
+	}
+}
+
+// revisionParam разбирает номер ревизии из параметра :revision
+func revisionParam(c *gin.Context) (int, bool) {
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный номер ревизии"})
+		return 0, false
+	}
+	return revision, true
+}
+
+// GetRevisions обрабатывает запрос на получение истории изменений мультиссылки
+func (h *RevisionHandler) GetRevisions(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.revisionService.GetRevisions(c.Request.Context(), multiLinkID)
+	if err != nil {
+		logError(c, "failed to get revisions", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении истории изменений"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RevisionListResponse{
+		Revisions: revisions,
+		Total:     len(revisions),
+	})
+}
+
+// GetRevision обрабатывает запрос на получение снимка ревизии
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+	revision, ok := revisionParam(c)
+	if !ok {
+		return
+	}
+
+	rev, err := h.revisionService.GetRevision(c.Request.Context(), multiLinkID, revision)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ревизия не найдена"})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to get revision", err, "multilink_id", multiLinkID, "revision", revision)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении ревизии"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revision": rev})
+}
+
+// RestoreRevision обрабатывает запрос на откат мультиссылки к ревизии
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+	revision, ok := revisionParam(c)
+	if !ok {
+		return
+	}
+
+	rev, err := h.revisionService.RestoreRevision(c.Request.Context(), multiLinkID, revision)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ревизия не найдена"})
+		return
+	case errors.Is(err, services.ErrSlugTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug ревизии уже занят другой страницей"})
+		return
+	case respondButtonValidationError(c, err):
+		return
+	case err != nil:
+		logError(c, "failed to restore revision", err, "multilink_id", multiLinkID, "revision", revision)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при восстановлении ревизии"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revision": rev})
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+)
+
+const (
+	// consentCookie хранит категории чувствительного содержимого, которые
+	// посетитель согласился видеть, через точку
+	consentCookie = "sensitive_consent"
+	// consentParam передает согласие в адресе, если cookie недоступны,
+	// например при встраивании страницы на другой сайт
+	consentParam  = "consent"
+	consentMaxAge = 30 * 24 * time.Hour
+)
+
+// crawlerMarkers — фрагменты User-Agent поисковых роботов и сервисов
+// предпросмотра ссылок в мессенджерах и соцсетях
+var crawlerMarkers = []string{
+	"bot", "crawler", "spider", "slurp", "facebookexternalhit", "whatsapp",
+	"vkshare", "skypeuripreview", "embedly", "preview",
+}
+
+// sensitiveReasonLabels описывают категории на странице подтверждения
+var sensitiveReasonLabels = map[string]string{
+	models.SensitiveReasonAdult:    "материалы для взрослых (18+)",
+	models.SensitiveReasonAlcohol:  "информацию об алкогольной продукции",
+	models.SensitiveReasonTobacco:  "информацию о табачной продукции",
+	models.SensitiveReasonGambling: "азартные игры",
+	models.SensitiveReasonViolence: "сцены насилия",
+	models.SensitiveReasonOther:    "материалы, которые могут быть нежелательны для части посетителей",
+}
+
+// consentTemplate — страница подтверждения перед переходом по ссылке на
+// чувствительное содержимое. Роботам она показывается без ссылки «Продолжить».
+// Цвета и шрифт берутся из оформления страницы
+var consentTemplate = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>Подтвердите переход</title>
+<style>
+body { font-family: {{.Theme.FontFamily}}; background: {{.Theme.BackgroundColor}}; color: {{.Theme.TextColor}}; margin: 0; }
+main { max-width: 32rem; margin: 15vh auto; padding: 2rem; }
+h1 { font-size: 1.5rem; margin-top: 0; }
+a.continue { display: inline-block; padding: .6rem 1.2rem; background: {{.Theme.ButtonColor}}; color: {{.Theme.ButtonTextColor}}; border-radius: {{.Theme.ButtonRadius}}; text-decoration: none; }
+</style>
+</head>
+<body>
+<main>
+<h1>Подтвердите переход</h1>
+<p>Эта ссылка ведет на {{.Label}}.</p>
+{{if .ContinueURL}}<p>Продолжая, вы подтверждаете, что вам исполнилось 18 лет и вы готовы увидеть такое содержимое.</p>
+<p><a class="continue" href="{{.ContinueURL}}" rel="nofollow">Продолжить</a></p>{{end}}
+</main>
+</body>
+</html>
+`))
+
+// SensitiveHandler обрабатывает запросы, связанные с чувствительным содержимым
+type SensitiveHandler struct {
+	multiLinkService *services.MultiLinkService
+}
+
+// NewSensitiveHandler создает новый экземпляр SensitiveHandler
+func NewSensitiveHandler(multiLinkService *services.MultiLinkService) *SensitiveHandler {
+	return &SensitiveHandler{
+		multiLinkService: multiLinkService,
+	}
+}
+
+// ForceSensitive обрабатывает запрос администратора на принудительную
+// отметку страницы как чувствительной
+func (h *SensitiveHandler) ForceSensitive(c *gin.Context) {
+	var req models.ForceSensitiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.setForcedSensitive(c, req.Reason)
+}
+
+// UnforceSensitive обрабатывает запрос администратора на снятие
+// принудительной отметки. Отметка владельца страницы сохраняется
+func (h *SensitiveHandler) UnforceSensitive(c *gin.Context) {
+	h.setForcedSensitive(c, "")
+}
+
+func (h *SensitiveHandler) setForcedSensitive(c *gin.Context, reason string) {
+	multiLinkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID мультиссылки"})
+		return
+	}
+
+	err = h.multiLinkService.SetForcedSensitive(c.Request.Context(), multiLinkID, reason)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to set forced sensitive flag", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении отметки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"forced_sensitive_reason": reason})
+}
+
+// Acknowledge обрабатывает подтверждение посетителя, что он готов видеть
+// содержимое указанных категорий. Согласие запоминается в cookie
+func (h *SensitiveHandler) Acknowledge(c *gin.Context) {
+	var req models.ConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reasons": rememberConsent(c, req.Reasons)})
+}
+
+// requireConsent проверяет согласие посетителя на просмотр чувствительной
+// страницы. Без согласия отвечает 403 с запросом подтверждения
+func requireConsent(c *gin.Context, multiLink models.MultiLink, consent map[string]bool) bool {
+	reason, sensitive := services.MultiLinkSensitiveReason(multiLink)
+	if !sensitive || consent[reason] {
+		return true
+	}
+	c.JSON(http.StatusForbidden, models.ConsentChallenge{ConsentRequired: true, Slug: multiLink.Slug, Reason: reason})
+	return false
+}
+
+// gateSensitiveButtons скрывает ссылки чувствительных кнопок, на которые
+// посетитель не дал согласия: переход по ним покажет страницу подтверждения.
+// Роботам такие кнопки не показываются вовсе
+func gateSensitiveButtons(buttons []models.LinkButton, consent map[string]bool, crawler bool) []models.LinkButton {
+	gated := buttons[:0]
+	for _, b := range buttons {
+		if b.Sensitive && crawler {
+			continue
+		}
+		if b.Sensitive && !consent[b.SensitiveReason] {
+			b.URL = ""
+			b.FallbackURL = ""
+		}
+		gated = append(gated, b)
+	}
+	return gated
+}
+
+// visitorConsent возвращает категории, на которые посетитель дал согласие,
+// из cookie и параметра consent. Согласие из параметра запоминается в cookie
+func visitorConsent(c *gin.Context) map[string]bool {
+	consent := consentReasons(cookieValue(c, consentCookie), ".")
+
+	var granted []string
+	for r := range consentReasons(c.Query(consentParam), ",") {
+		if !consent[r] {
+			granted = append(granted, r)
+			consent[r] = true
+		}
+	}
+	if len(granted) > 0 {
+		rememberConsent(c, granted)
+	}
+	return consent
+}
+
+// rememberConsent добавляет категории к согласию из cookie и возвращает
+// полный список категорий
+func rememberConsent(c *gin.Context, reasons []string) []string {
+	stored := consentReasons(cookieValue(c, consentCookie), ".")
+	for _, r := range reasons {
+		if _, known := sensitiveReasonLabels[r]; known {
+			stored[r] = true
+		}
+	}
+
+	all := make([]string, 0, len(stored))
+	for r := range stored {
+		all = append(all, r)
+	}
+	sort.Strings(all)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(consentCookie, strings.Join(all, "."), int(consentMaxAge.Seconds()),
+		"/", "", isSecureRequest(c), true)
+	return all
+}
+
+func consentReasons(value, sep string) map[string]bool {
+	reasons := make(map[string]bool)
+	for _, r := range strings.Split(value, sep) {
+		if _, known := sensitiveReasonLabels[r]; known {
+			reasons[r] = true
+		}
+	}
+	return reasons
+}
+
+func cookieValue(c *gin.Context, name string) string {
+	value, _ := c.Cookie(name)
+	return value
+}
+
+// isCrawler определяет поисковых роботов и сервисы предпросмотра ссылок,
+// которым вместо чувствительного содержимого отдается нейтральный ответ
+func isCrawler(userAgent string) bool {
+	userAgent = strings.ToLower(userAgent)
+	for _, marker := range crawlerMarkers {
+		if strings.Contains(userAgent, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// buttonRadii задает скругление кнопок для каждой формы
+var buttonRadii = map[string]string{
+	models.ButtonShapeSquare:  "0",
+	models.ButtonShapeRounded: ".5rem",
+	models.ButtonShapePill:    "999px",
+}
+
+// consentTheme содержит оформление страницы подтверждения. Значения
+// подставляются в CSS без экранирования, поэтому берутся только из
+// проверенного оформления: цвета нормализованы, шрифты — из набора пресетов
+type consentTheme struct {
+	FontFamily      template.CSS
+	BackgroundColor template.CSS
+	TextColor       template.CSS
+	ButtonColor     template.CSS
+	ButtonTextColor template.CSS
+	ButtonRadius    template.CSS
+}
+
+func newConsentTheme(t models.Theme) consentTheme {
+	return consentTheme{
+		FontFamily:      template.CSS(t.FontFamily),
+		BackgroundColor: template.CSS(t.BackgroundColor),
+		TextColor:       template.CSS(t.TextColor),
+		ButtonColor:     template.CSS(t.ButtonColor),
+		ButtonTextColor: template.CSS(t.ButtonTextColor),
+		ButtonRadius:    template.CSS(buttonRadii[t.ButtonShape]),
+	}
+}
+
+// renderConsentInterstitial отвечает страницей подтверждения перехода в
+// оформлении мультиссылки. Ссылка «Продолжить» повторяет запрос с согласием
+// на категорию
+func renderConsentInterstitial(c *gin.Context, multiLink models.MultiLink, reason string, crawler bool) {
+	data := struct {
+		Label       string
+		ContinueURL string
+		Theme       consentTheme
+	}{
+		Label: sensitiveReasonLabels[reason],
+		Theme: newConsentTheme(services.ResolveTheme(multiLink)),
+	}
+
+	if !crawler {
+		u := *c.Request.URL
+		query := u.Query()
+		query.Set(consentParam, reason)
+		u.RawQuery = query.Encode()
+		data.ContinueURL = u.RequestURI()
+	}
+
+	var buf bytes.Buffer
+	if err := consentTemplate.Execute(&buf, data); err != nil {
+		logError(c, "failed to render consent interstitial", err)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Требуется подтверждение перехода", "code": "consent_required"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+	"mvp_multylink/backend/internal/theme"
+)
+
+// ThemeHandler обрабатывает запросы, связанные с оформлением страниц
+type ThemeHandler struct {
+	multiLinkService *services.MultiLinkService
+}
+
+// NewThemeHandler создает новый экземпляр ThemeHandler
+func NewThemeHandler(multiLinkService *services.MultiLinkService) *ThemeHandler {
+	return &ThemeHandler{
+		multiLinkService: multiLinkService,
+	}
+}
+
+// GetPresets обрабатывает запрос на получение списка готовых оформлений
+func (h *ThemeHandler) GetPresets(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"presets": theme.Presets()})
+}
+
+// UpdateTheme обрабатывает запрос на изменение оформления мультиссылки.
+// В ответе возвращается итоговое оформление с примененными переопределениями
+func (h *ThemeHandler) UpdateTheme(c *gin.Context) {
+	multiLinkID, ok := authorizeMultiLink(c, h.multiLinkService)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateThemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resolved, err := h.multiLinkService.SetTheme(c.Request.Context(), multiLinkID, req)
+	switch {
+	case errors.Is(err, theme.ErrLowContrast):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "low_contrast"})
+		return
+	case errors.Is(err, theme.ErrInvalidTheme):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена"})
+		return
+	case err != nil:
+		logError(c, "failed to update theme", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при изменении оформления"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resolved)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/services"
+)
+
+// TrashHandler обрабатывает запросы, связанные с корзиной
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+// NewTrashHandler создает новый экземпляр TrashHandler
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// GetTrash обрабатывает запрос на получение содержимого корзины пользователя
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		return
+	}
+
+	trash, err := h.trashService.GetTrash(c.Request.Context(), userID.(int64))
+	if err != nil {
+		logError(c, "failed to get trash", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении корзины"})
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// RestoreMultiLink обрабатывает запрос на восстановление мультиссылки из корзины
+func (h *TrashHandler) RestoreMultiLink(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		return
+	}
+
+	multiLinkID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID мультиссылки"})
+		return
+	}
+
+	// Проверка существования мультиссылки в корзине и прав доступа
+	multiLink, err := h.trashService.GetDeletedMultiLinkByID(c.Request.Context(), multiLinkID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена в корзине"})
+		return
+	}
+
+	if multiLink.UserID != userID.(int64) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен"})
+		return
+	}
+
+	err = h.trashService.RestoreMultiLink(c.Request.Context(), multiLinkID)
+	switch {
+	case errors.Is(err, services.ErrSlugTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug мультиссылки уже занят другой страницей"})
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Мультиссылка не найдена в корзине"})
+		return
+	case err != nil:
+		logError(c, "failed to restore multilink", err, "multilink_id", multiLinkID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при восстановлении мультиссылки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Мультиссылка успешно восстановлена"})
+}
+
+// RestoreButton обрабатывает запрос на восстановление кнопки из корзины
+func (h *TrashHandler) RestoreButton(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		return
+	}
+
+	buttonID, err := strconv.ParseInt(c.Param("button_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный ID кнопки"})
+		return
+	}
+
+	// Получение кнопки из корзины и проверка прав доступа
+	button, err := h.trashService.GetDeletedButtonByID(c.Request.Context(), buttonID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена в корзине"})
+		return
+	}
+
+	multiLink, err := h.trashService.GetButtonOwnerMultiLink(c.Request.Context(), button)
+	if err != nil {
+		logError(c, "failed to load multilink for access check", err, "multilink_id", button.MultiLinkID, "button_id", buttonID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при проверке прав доступа"})
+		return
+	}
+
+	if multiLink.UserID != userID.(int64) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Доступ запрещен"})
+		return
+	}
+
+	err = h.trashService.RestoreButton(c.Request.Context(), buttonID)
+	switch {
+	case errors.Is(err, services.ErrParentDeleted):
+		c.JSON(http.StatusConflict, gin.H{"error": "Мультиссылка кнопки находится в корзине, сначала восстановите ее"})
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Кнопка не найдена в корзине"})
+		return
+	case err != nil:
+		logError(c, "failed to restore button", err, "multilink_id", button.MultiLinkID, "button_id", buttonID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при восстановлении кнопки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Кнопка успешно восстановлена"})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/services"
+	"mvp_multylink/backend/internal/unfurl"
+)
+
+// UnfurlHandler обрабатывает запросы предпросмотра ссылок
+type UnfurlHandler struct {
+	unfurlService *services.UnfurlService
+}
+
+// NewUnfurlHandler создает новый экземпляр UnfurlHandler
+func NewUnfurlHandler(unfurlService *services.UnfurlService) *UnfurlHandler {
+	return &UnfurlHandler{
+		unfurlService: unfurlService,
+	}
+}
+
+// Unfurl обрабатывает запрос на получение заголовка, изображения
+// и иконки страницы по ссылке
+func (h *UnfurlHandler) Unfurl(c *gin.Context) {
+	var req models.UnfurlRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := h.unfurlService.Unfurl(c.Request.Context(), req.URL)
+	if respondButtonValidationError(c, err) {
+		return
+	}
+	if errors.Is(err, unfurl.ErrFetch) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logError(c, "failed to unfurl url", err, "url", req.URL)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ошибка при получении предпросмотра ссылки"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
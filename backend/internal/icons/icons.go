@@ -0,0 +1,164 @@
+// Package icons содержит встроенный набор SVG-значков для кнопок: значки
+// популярных платформ и общие значки для почты, телефона, SMS и ссылок.
+// Значки одноцветные и используют currentColor, поэтому принимают цвет
+// текста кнопки
+package icons
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed svg/*.svg
+var files embed.FS
+
+// Общие значки для кнопок без платформы
+const (
+	Email   = "email"
+	Phone   = "phone"
+	SMS     = "sms"
+	Link    = "link"
+	Website = "website"
+)
+
+// Icon представляет значок набора: имя, содержимое и отпечаток содержимого,
+// который используется в адресе значка и ETag
+type Icon struct {
+	Name    string
+	SVG     []byte
+	Version string
+}
+
+var (
+	set   = make(map[string]Icon)
+	names []string
+)
+
+// domains сопоставляет домены платформ значкам. Поддомены (www, m, music
+// и т.п.) определяются по суффиксу
+var domains = map[string]string{
+	"t.me":             "telegram",
+	"telegram.me":      "telegram",
+	"telegram.org":     "telegram",
+	"telegram.dog":     "telegram",
+	"vk.com":           "vk",
+	"vk.ru":            "vk",
+	"vk.me":            "vk",
+	"vkontakte.ru":     "vk",
+	"youtube.com":      "youtube",
+	"youtu.be":         "youtube",
+	"instagram.com":    "instagram",
+	"instagr.am":       "instagram",
+	"tiktok.com":       "tiktok",
+	"rutube.ru":        "rutube",
+	"dzen.ru":          "dzen",
+	"zen.yandex.ru":    "dzen",
+	"wa.me":            "whatsapp",
+	"whatsapp.com":     "whatsapp",
+	"github.com":       "github",
+	"x.com":            "x",
+	"twitter.com":      "x",
+	"facebook.com":     "facebook",
+	"fb.com":           "facebook",
+	"fb.me":            "facebook",
+	"linkedin.com":     "linkedin",
+	"ok.ru":            "ok",
+	"odnoklassniki.ru": "ok",
+	"twitch.tv":        "twitch",
+	"discord.com":      "discord",
+	"discord.gg":       "discord",
+	"spotify.com":      "spotify",
+	"pinterest.com":    "pinterest",
+	"pinterest.ru":     "pinterest",
+	"pin.it":           "pinterest",
+	"patreon.com":      "patreon",
+}
+
+// schemes сопоставляет схемы ссылок, отличных от http(s), общим значкам
+var schemes = map[string]string{
+	"mailto": Email,
+	"tel":    Phone,
+	"sms":    SMS,
+}
+
+func init() {
+	entries, err := files.ReadDir("svg")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		data, err := files.ReadFile("svg/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		name := strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+		sum := sha256.Sum256(data)
+		set[name] = Icon{Name: name, SVG: data, Version: hex.EncodeToString(sum[:6])}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Каждый домен и каждая схема должны вести на существующий значок
+	for domain, name := range domains {
+		if _, ok := set[name]; !ok {
+			panic("icons: нет значка " + name + " для домена " + domain)
+		}
+	}
+	for scheme, name := range schemes {
+		if _, ok := set[name]; !ok {
+			panic("icons: нет значка " + name + " для схемы " + scheme)
+		}
+	}
+}
+
+// Names возвращает имена всех значков в алфавитном порядке
+func Names() []string {
+	return append([]string(nil), names...)
+}
+
+// Get возвращает значок по имени
+func Get(name string) (Icon, bool) {
+	icon, ok := set[name]
+	return icon, ok
+}
+
+// Exists проверяет, что значок с таким именем есть в наборе
+func Exists(name string) bool {
+	_, ok := set[name]
+	return ok
+}
+
+// ForURL подбирает значок по исходящей ссылке кнопки: для http(s) — по домену
+// платформы, для mailto:, tel: и sms: — общий значок. Если подходящего
+// значка нет, возвращается пустая строка
+func ForURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if name, ok := schemes[scheme]; ok {
+		return name
+	}
+	if scheme != "http" && scheme != "https" {
+		return ""
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	for host != "" {
+		if name, ok := domains[host]; ok {
+			return name
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package icons
+
+import "testing"
+
+func TestForURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://youtube.com/@demo", "youtube"},
+		{"https://www.youtube.com/watch?v=1", "youtube"},
+		{"https://music.youtube.com/", "youtube"},
+		{"https://youtu.be/abc", "youtube"},
+		{"HTTPS://M.VK.COM/demo", "vk"},
+		{"https://t.me./demo", "telegram"},
+		{"http://zen.yandex.ru/demo", "dzen"},
+		{"mailto:hello@example.com", Email},
+		{"tel:+79990000000", Phone},
+		{"sms:+79990000000", SMS},
+		// Неизвестный домен остается без значка
+		{"https://example.com/", ""},
+		{"https://yandex.ru/", ""},
+		{"https://notyoutube.com/", ""},
+		{"https://youtube.com.evil.test/", ""},
+		{"ftp://youtube.com/", ""},
+		{"javascript:alert(1)", ""},
+		{"", ""},
+		{"://broken", ""},
+	}
+	for _, tt := range tests {
+		if got := ForURL(tt.url); got != tt.want {
+			t.Errorf("ForURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSetIsComplete(t *testing.T) {
+	for _, name := range []string{Email, Phone, SMS, Link, Website} {
+		if !Exists(name) {
+			t.Errorf("generic icon %q is missing", name)
+		}
+	}
+	for _, name := range Names() {
+		icon, ok := Get(name)
+		if !ok || len(icon.SVG) == 0 || icon.Version == "" {
+			t.Errorf("icon %q = %+v, want content and version", name, icon)
+		}
+	}
+	if Exists("") || Exists("../icons") || Exists("youtube.svg") {
+		t.Error("only bare icon names must exist")
+	}
+}
@@ -0,0 +1,193 @@
+// Package images обрабатывает загруженные изображения: проверяет формат по
+// содержимому, поворачивает по EXIF, уменьшает до стандартных размеров и
+// кодирует заново. Метаданные исходного файла, включая EXIF, не сохраняются
+package images
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Декодеры форматов изображений
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp"
+)
+
+// Назначения загружаемых изображений
+const (
+	KindAvatar     = "avatar"
+	KindBackground = "background"
+	KindIcon       = "icon"
+)
+
+// MaxPixels ограничивает размер исходного изображения, чтобы маленький
+// сжатый файл не занял при декодировании много памяти
+const MaxPixels = 6000 * 4000
+
+// jpegQuality — качество JPEG для изображений без прозрачности
+const jpegQuality = 85
+
+var (
+	// ErrUnsupportedFormat возвращается, если содержимое файла не JPEG, PNG, GIF или WebP
+	ErrUnsupportedFormat = errors.New("неподдерживаемый формат изображения")
+
+	// ErrTooLarge возвращается, если изображение больше MaxPixels
+	ErrTooLarge = errors.New("слишком большое изображение")
+
+	// ErrInvalidImage возвращается, если изображение не удалось декодировать
+	ErrInvalidImage = errors.New("поврежденное изображение")
+
+	// ErrUnknownKind возвращается для неизвестного назначения изображения
+	ErrUnknownKind = errors.New("неизвестное назначение изображения")
+)
+
+// allowedTypes — типы содержимого, определенные по сигнатуре файла, которые
+// принимаются к загрузке
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Variant описывает стандартный размер изображения. Если Square истинно,
+// изображение обрезается до квадрата по центру, иначе вписывается в
+// Width×Height с сохранением пропорций. Изображения не увеличиваются
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+	Square bool
+}
+
+// variants задает стандартные размеры для каждого назначения
+var variants = map[string][]Variant{
+	KindAvatar: {
+		{Name: "sm", Width: 64, Height: 64, Square: true},
+		{Name: "md", Width: 256, Height: 256, Square: true},
+		{Name: "lg", Width: 512, Height: 512, Square: true},
+	},
+	KindBackground: {
+		{Name: "sm", Width: 640, Height: 1280},
+		{Name: "md", Width: 1280, Height: 1920},
+		{Name: "lg", Width: 1920, Height: 2560},
+	},
+	KindIcon: {
+		{Name: "sm", Width: 32, Height: 32},
+		{Name: "md", Width: 64, Height: 64},
+		{Name: "lg", Width: 128, Height: 128},
+	},
+}
+
+// Encoded представляет изображение одного размера, готовое к сохранению
+type Encoded struct {
+	Variant     string
+	Width       int
+	Height      int
+	ContentType string
+	Ext         string
+	Data        []byte
+}
+
+// ValidKind проверяет, что назначение изображения известно
+func ValidKind(kind string) bool {
+	_, ok := variants[kind]
+	return ok
+}
+
+// Process проверяет и декодирует изображение и возвращает его во всех
+// стандартных размерах назначения kind. Изображения с прозрачностью
+// кодируются в PNG, остальные — в JPEG
+func Process(data []byte, kind string) ([]Encoded, error) {
+	sizes, ok := variants[kind]
+	if !ok {
+		return nil, ErrUnknownKind
+	}
+
+	img, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	opaque := isOpaque(img)
+	result := make([]Encoded, 0, len(sizes))
+	for _, v := range sizes {
+		resized := resize(img, v)
+		encoded, err := encode(resized, opaque)
+		if err != nil {
+			return nil, err
+		}
+		encoded.Variant = v.Name
+		result = append(result, encoded)
+	}
+	return result, nil
+}
+
+// decode определяет формат по содержимому, а не по имени файла или
+// заголовкам запроса, проверяет размеры до декодирования и поворачивает
+// изображение согласно EXIF-ориентации
+func decode(data []byte) (image.Image, error) {
+	if !allowedTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedFormat
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return img, nil
+}
+
+func resize(img image.Image, v Variant) image.Image {
+	bounds := img.Bounds()
+	if v.Square {
+		side := min(bounds.Dx(), bounds.Dy(), v.Width)
+		return imaging.Fill(img, side, side, imaging.Center, imaging.Lanczos)
+	}
+	if bounds.Dx() <= v.Width && bounds.Dy() <= v.Height {
+		return imaging.Clone(img)
+	}
+	return imaging.Fit(img, v.Width, v.Height, imaging.Lanczos)
+}
+
+func encode(img image.Image, opaque bool) (Encoded, error) {
+	var buf bytes.Buffer
+	encoded := Encoded{Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+	if opaque {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return Encoded{}, err
+		}
+		encoded.ContentType, encoded.Ext = "image/jpeg", ".jpg"
+	} else {
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return Encoded{}, err
+		}
+		encoded.ContentType, encoded.Ext = "image/png", ".png"
+	}
+	encoded.Data = buf.Bytes()
+	return encoded, nil
+}
+
+// isOpaque проверяет, что в изображении нет прозрачных пикселей
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// solidImage возвращает изображение width×height: левая половина красная,
+// правая синяя
+func solidImage(width, height int, alpha uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{R: 220, A: alpha}
+			if x >= width/2 {
+				c = color.NRGBA{B: 220, A: alpha}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessEncodesEveryVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		kind     string
+		wantType string
+		wantSize [][2]int
+	}{
+		{
+			name: "opaque avatar becomes square jpeg", data: encodePNG(t, solidImage(300, 200, 0xff)), kind: KindAvatar,
+			wantType: "image/jpeg", wantSize: [][2]int{{64, 64}, {200, 200}, {200, 200}},
+		},
+		{
+			name: "transparent icon stays png", data: encodePNG(t, solidImage(100, 50, 0x80)), kind: KindIcon,
+			wantType: "image/png", wantSize: [][2]int{{32, 16}, {64, 32}, {100, 50}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := Process(tt.data, tt.kind)
+			if err != nil {
+				t.Fatalf("Process: %v", err)
+			}
+			if len(encoded) != len(tt.wantSize) {
+				t.Fatalf("variants = %d, want %d", len(encoded), len(tt.wantSize))
+			}
+			for i, e := range encoded {
+				if e.ContentType != tt.wantType || e.Width != tt.wantSize[i][0] || e.Height != tt.wantSize[i][1] {
+					t.Errorf("variant %s = %s %dx%d, want %s %dx%d", e.Variant, e.ContentType, e.Width, e.Height,
+						tt.wantType, tt.wantSize[i][0], tt.wantSize[i][1])
+				}
+			}
+		})
+	}
+
+	if _, err := Process(encodePNG(t, solidImage(10, 10, 0xff)), "banner"); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("Process with unknown kind error = %v, want %v", err, ErrUnknownKind)
+	}
+}
+
+func TestProcessDetectsFormatByContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		// Тип определяется по содержимому, имя файла и Content-Type запроса не учитываются
+		{"html named as png", []byte("<!DOCTYPE html><html><script>alert(1)</script></html>"), ErrUnsupportedFormat},
+		{"svg with script", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`), ErrUnsupportedFormat},
+		{"pdf", []byte("%PDF-1.7\n1 0 obj\n<<>>\nendobj\n"), ErrUnsupportedFormat},
+		{"plain text", []byte("just some text"), ErrUnsupportedFormat},
+		{"empty", nil, ErrUnsupportedFormat},
+		// Сигнатура изображения с посторонним содержимым не декодируется
+		{"gif header with script", []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00<script>alert(1)</script>"), ErrInvalidImage},
+		{"truncated png", encodePNG(t, solidImage(20, 20, 0xff))[:40], ErrInvalidImage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Process(tt.data, KindAvatar); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Process error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// pngHeader возвращает начало PNG-файла с заголовком IHDR указанных размеров
+// и без данных изображения: декодировать такой файл нельзя, можно только
+// прочитать размеры
+func pngHeader(width, height uint32) []byte {
+	var ihdr [13]byte
+	binary.BigEndian.PutUint32(ihdr[0:], width)
+	binary.BigEndian.PutUint32(ihdr[4:], height)
+	ihdr[8], ihdr[9] = 8, 2 // 8 бит на канал, RGB
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)))
+	chunk := append([]byte("IHDR"), ihdr[:]...)
+	buf.Write(chunk)
+	_ = binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+	return buf.Bytes()
+}
+
+func TestProcessRejectsTooLargeBeforeDecode(t *testing.T) {
+	// Заголовок обещает 10000×10000 пикселей, но данных нет: если бы
+	// изображение декодировалось, ошибка была бы ErrInvalidImage
+	if _, err := Process(pngHeader(10000, 10000), KindBackground); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Process error = %v, want %v", err, ErrTooLarge)
+	}
+	if _, err := Process(pngHeader(MaxPixels+1, 1), KindBackground); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Process of a long strip error = %v, want %v", err, ErrTooLarge)
+	}
+
+	// Размер в пределах MaxPixels проходит проверку и доходит до декодирования
+	if _, err := Process(pngHeader(6000, 4000), KindBackground); !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("Process of a header within MaxPixels error = %v, want %v", err, ErrInvalidImage)
+	}
+}
+
+const exifSecret = "Camera serial 1234-SECRET"
+
+// withEXIF вставляет в JPEG сегмент APP1 с EXIF-ориентацией и описанием изображения
+func withEXIF(t *testing.T, data []byte, orientation uint16) []byte {
+	t.Helper()
+	description := append([]byte(exifSecret), 0)
+
+	// TIFF в порядке байтов big-endian: заголовок, IFD из двух записей, данные описания
+	var tiff bytes.Buffer
+	for _, v := range []any{
+		[]byte("MM\x00\x2a"), uint32(8), uint16(2),
+		// ImageDescription (ASCII), значение по смещению после IFD
+		uint16(0x010e), uint16(2), uint32(len(description)), uint32(8 + 2 + 2*12 + 4),
+		// Orientation (SHORT), значение в старших байтах поля
+		uint16(0x0112), uint16(3), uint32(1), orientation, uint16(0),
+		uint32(0), description,
+	} {
+		if err := binary.Write(&tiff, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	result := append([]byte(nil), data[:2]...)
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+func TestProcessAppliesOrientationAndStripsEXIF(t *testing.T) {
+	// Снимок 80×40 с ориентацией 6: для показа его нужно повернуть на 90°
+	// по часовой стрелке, после чего левая (красная) половина окажется сверху
+	data := withEXIF(t, encodeJPEG(t, solidImage(80, 40, 0xff)), 6)
+	if !bytes.Contains(data, []byte(exifSecret)) {
+		t.Fatal("test image must contain EXIF")
+	}
+
+	encoded, err := Process(data, KindBackground)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	for _, e := range encoded {
+		if e.Width != 40 || e.Height != 80 {
+			t.Errorf("variant %s = %dx%d, want 40x80 after rotation", e.Variant, e.Width, e.Height)
+		}
+		if bytes.Contains(e.Data, []byte("Exif")) || bytes.Contains(e.Data, []byte(exifSecret)) {
+			t.Errorf("variant %s keeps EXIF metadata", e.Variant)
+		}
+
+		img, err := jpeg.Decode(bytes.NewReader(e.Data))
+		if err != nil {
+			t.Fatalf("variant %s: %v", e.Variant, err)
+		}
+		if top := color.NRGBAModel.Convert(img.At(20, 10)).(color.NRGBA); top.R < 150 || top.B > 100 {
+			t.Errorf("variant %s: top pixel = %v, want red", e.Variant, top)
+		}
+		if bottom := color.NRGBAModel.Convert(img.At(20, 70)).(color.NRGBA); bottom.B < 150 || bottom.R > 100 {
+			t.Errorf("variant %s: bottom pixel = %v, want blue", e.Variant, bottom)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package linkcheck проверяет доступность внешних ссылок: выполняет
+// HEAD-запрос (или GET, если HEAD не поддерживается), отслеживает цепочку
+// перенаправлений и ограничивает нагрузку на каждый хост
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// Состояния ссылки по результату проверки
+const (
+	HealthOK     = "ok"
+	HealthBroken = "broken"
+	// HealthUnknown означает, что результат не позволяет судить о ссылке
+	// (например, сайт ограничил частоту запросов)
+	HealthUnknown = "unknown"
+)
+
+// Options задает параметры проверки
+type Options struct {
+	Timeout      time.Duration
+	MaxRedirects int
+	UserAgent    string
+	// AllowPrivate разрешает подключение к внутренним адресам. Нужен для
+	// проверки на локальных серверах, например httptest
+	AllowPrivate bool
+}
+
+// Result содержит результат проверки одной ссылки
+type Result struct {
+	URL        string
+	FinalURL   string
+	StatusCode int
+	Redirects  []string
+	Error      string
+	Duration   time.Duration
+	Health     string
+}
+
+// Checker выполняет проверки ссылок
+type Checker struct {
+	client       *http.Client
+	maxRedirects int
+	userAgent    string
+}
+
+// New создает новый экземпляр Checker
+func New(opts Options) *Checker {
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivate {
+		dialer.Control = urlpolicy.DialControl
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   opts.Timeout,
+		ResponseHeaderTimeout: opts.Timeout,
+		MaxIdleConnsPerHost:   1,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &Checker{
+		client:       &http.Client{Transport: transport, Timeout: opts.Timeout},
+		maxRedirects: opts.MaxRedirects,
+		userAgent:    opts.UserAgent,
+	}
+}
+
+// Check проверяет ссылку. Сначала выполняется HEAD, а если сервер его
+// не поддерживает или отвечает ошибкой — GET
+func (c *Checker) Check(ctx context.Context, rawURL string) Result {
+	start := time.Now()
+	result := c.do(ctx, http.MethodHead, rawURL)
+	if result.Error != "" || result.StatusCode >= 400 {
+		result = c.do(ctx, http.MethodGet, rawURL)
+	}
+	result.Duration = time.Since(start)
+	result.Health = classify(result)
+	if ctx.Err() != nil {
+		// Проверка прервана остановкой сервиса, а не недоступностью сайта
+		result.Health = HealthUnknown
+	}
+	return result
+}
+
+func (c *Checker) do(ctx context.Context, method, rawURL string) Result {
+	result := Result{URL: rawURL}
+
+	client := *c.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > c.maxRedirects {
+			return fmt.Errorf("более %d перенаправлений", c.maxRedirects)
+		}
+		result.Redirects = append(result.Redirects, req.URL.String())
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Error = errorMessage(err)
+		return result
+	}
+	defer resp.Body.Close()
+	// Тело не нужно: дочитываем немного, чтобы соединение можно было переиспользовать
+	_, _ = io.CopyN(io.Discard, resp.Body, 4<<10)
+
+	result.StatusCode = resp.StatusCode
+	result.FinalURL = resp.Request.URL.String()
+	return result
+}
+
+func classify(result Result) string {
+	switch {
+	case result.Error != "":
+		return HealthBroken
+	case result.StatusCode == http.StatusTooManyRequests:
+		return HealthUnknown
+	case result.StatusCode >= 400:
+		return HealthBroken
+	default:
+		return HealthOK
+	}
+}
+
+// errorMessage возвращает описание ошибки без повторения URL,
+// которое добавляет http.Client
+func errorMessage(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return err.Error()
+}
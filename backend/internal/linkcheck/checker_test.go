@@ -0,0 +1,147 @@
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestChecker(timeout time.Duration) *Checker {
+	return New(Options{Timeout: timeout, MaxRedirects: 3, UserAgent: "linkcheck-test", AllowPrivate: true})
+}
+
+func TestCheckFollowsRedirectChain(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/b", http.StatusMovedPermanently) })
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/c", http.StatusFound) })
+	mux.HandleFunc("/c", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	result := newTestChecker(time.Second).Check(context.Background(), server.URL+"/a")
+
+	if result.Health != HealthOK || result.StatusCode != http.StatusOK {
+		t.Fatalf("health = %q, status = %d, error = %q; want ok 200", result.Health, result.StatusCode, result.Error)
+	}
+	if result.FinalURL != server.URL+"/c" {
+		t.Errorf("final URL = %q, want %q", result.FinalURL, server.URL+"/c")
+	}
+	want := []string{server.URL + "/b", server.URL + "/c"}
+	if len(result.Redirects) != len(want) || result.Redirects[0] != want[0] || result.Redirects[1] != want[1] {
+		t.Errorf("redirects = %v, want %v", result.Redirects, want)
+	}
+}
+
+func TestCheckStopsEndlessRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"x", http.StatusFound)
+	}))
+	defer server.Close()
+
+	result := newTestChecker(time.Second).Check(context.Background(), server.URL+"/")
+	if result.Health != HealthBroken || result.Error == "" {
+		t.Errorf("health = %q, error = %q; want broken with error", result.Health, result.Error)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	result := newTestChecker(100*time.Millisecond).Check(context.Background(), server.URL)
+	if result.Health != HealthBroken || result.Error == "" {
+		t.Errorf("health = %q, error = %q; want broken with error", result.Health, result.Error)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("check took %v, timeout was not applied", elapsed)
+	}
+}
+
+func TestCheckFallsBackToGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := newTestChecker(time.Second).Check(context.Background(), server.URL)
+	if result.Health != HealthOK {
+		t.Errorf("health = %q, status = %d; want ok after GET", result.Health, result.StatusCode)
+	}
+}
+
+func TestCheckClassifiesStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusOK, HealthOK},
+		{http.StatusNotFound, HealthBroken},
+		{http.StatusInternalServerError, HealthBroken},
+		{http.StatusTooManyRequests, HealthUnknown},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		result := newTestChecker(time.Second).Check(context.Background(), server.URL)
+		server.Close()
+		if result.Health != tt.want {
+			t.Errorf("status %d: health = %q, want %q", tt.status, result.Health, tt.want)
+		}
+	}
+}
+
+func TestCheckRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	checker := New(Options{Timeout: time.Second, MaxRedirects: 3})
+	result := checker.Check(context.Background(), server.URL)
+	if result.Health != HealthBroken || result.StatusCode != 0 {
+		t.Errorf("health = %q, status = %d; want broken without response", result.Health, result.StatusCode)
+	}
+}
+
+func TestCheckAllChecksEveryTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	targets := []Target{{ID: 1, URL: server.URL + "/"}, {ID: 2, URL: server.URL + "/missing"}, {ID: 3, URL: server.URL + "/ok"}}
+	var mu sync.Mutex
+	got := make(map[int64]string)
+	newTestChecker(time.Second).CheckAll(context.Background(), targets, 2, time.Millisecond, func(target Target, result Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		got[target.ID] = result.Health
+	})
+
+	want := map[int64]string{1: HealthOK, 2: HealthBroken, 3: HealthOK}
+	for id, health := range want {
+		if got[id] != health {
+			t.Errorf("target %d: health = %q, want %q", id, got[id], health)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package linkcheck
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Target описывает ссылку для проверки
+type Target struct {
+	ID  int64
+	URL string
+}
+
+// CheckAll проверяет ссылки и вызывает handle для каждого результата.
+// Ссылки одного хоста проверяются последовательно с паузой hostDelay между
+// запросами, разные хосты — параллельно, не более concurrency одновременно.
+// handle может вызываться из нескольких горутин
+func (c *Checker) CheckAll(ctx context.Context, targets []Target, concurrency int, hostDelay time.Duration, handle func(Target, Result)) {
+	byHost := make(map[string][]Target)
+	var hosts []string
+	for _, target := range targets {
+		host := hostOf(target.URL)
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		byHost[host] = append(byHost[host], target)
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for _, host := range hosts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func(queue []Target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			for i, target := range queue {
+				if i > 0 && !sleep(ctx, hostDelay) {
+					return
+				}
+				handle(target, c.Check(ctx, target.URL))
+			}
+		}(byHost[host])
+	}
+	wg.Wait()
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// sleep ждет d или отмены контекста. Возвращает false, если контекст отменен
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
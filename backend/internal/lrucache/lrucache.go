@@ -0,0 +1,89 @@
+// Package lrucache содержит LRU-кэш с ограниченным временем жизни записей
+package lrucache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache — потокобезопасный LRU-кэш. При size <= 0 или ttl <= 0 ничего не хранит
+type Cache[K comparable, V any] struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	order   *list.List
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// New создает новый экземпляр Cache
+func New[K comparable, V any](size int, ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get возвращает значение, если оно есть в кэше и не устарело
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	e := element.Value.(*entry[K, V])
+	if time.Now().After(e.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return e.value, true
+}
+
+// Put сохраняет значение, вытесняя давно не использованные записи
+func (c *Cache[K, V]) Put(key K, value V) {
+	if c.size <= 0 || c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &entry[K, V]{key: key, value: value, expiresAt: time.Now().Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = e
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry[K, V]).key)
+	}
+}
+
+// Delete удаляет значение из кэша
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
@@ -6,74 +6,45 @@ import (
 
 	"github.com/gin-gonic/gin" // Make sure to run: go get -u github.com/gin-gonic/gin
 
+	"mvp_multylink/backend/internal/monitoring"
 	"mvp_multylink/backend/internal/services"
 )
 
 // AuthMiddleware предоставляет middleware для аутентификации
 type AuthMiddleware struct {
 	authService *services.AuthService
+	metrics     *monitoring.Metrics
 }
 
 // NewAuthMiddleware создает новый экземпляр AuthMiddleware
-func NewAuthMiddleware(authService *services.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService *services.AuthService, metrics *monitoring.Metrics) *AuthMiddleware {
 	return &AuthMiddleware{
 		authService: authService,
+		metrics:     metrics,
 	}
 }
 
 // AuthRequired возвращает middleware, требующий аутентификации
 func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Получение токена из заголовка Authorization
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Требуется авторизация"})
-			c.Abort()
-			return
-		}
-
-		// Проверка формата токена
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверный формат токена"})
-			c.Abort()
+		if !m.authenticate(c) {
 			return
 		}
-
-		tokenString := parts[1]
-
-		// Валидация токена
-		claims, err := m.authService.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный токен: " + err.Error()})
-			c.Abort()
-			return
-		}
-
-		// Сохранение данных пользователя в контексте
-		c.Set("userID", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
-		c.Set("isAdmin", claims.IsAdmin)
-
 		c.Next()
 	}
 }
 
-// AdminRequired возвращает middleware, требующий прав администратора
+// AdminRequired возвращает middleware, требующий прав администратора.
+// Права проверяются до передачи запроса дальше по цепочке
 func (m *AuthMiddleware) AdminRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Сначала проверяем аутентификацию
-		m.AuthRequired()(c)
-
-		// Если запрос был прерван в AuthRequired, выходим
-		if c.IsAborted() {
+		if !m.authenticate(c) {
 			return
 		}
 
-		// Проверяем права администратора
 		isAdmin, exists := c.Get("isAdmin")
 		if !exists || !isAdmin.(bool) {
+			m.metrics.AuthFailed("forbidden")
 			c.JSON(http.StatusForbidden, gin.H{"error": "Требуются права администратора"})
 			c.Abort()
 			return
@@ -82,3 +53,46 @@ func (m *AuthMiddleware) AdminRequired() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// authenticate проверяет токен из заголовка Authorization и сохраняет данные
+// пользователя в контексте. Не передает запрос дальше по цепочке: при ошибке
+// ответ уже отправлен, запрос прерван и возвращается false
+func (m *AuthMiddleware) authenticate(c *gin.Context) bool {
+	// Получение токена из заголовка Authorization
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		m.metrics.AuthFailed("missing_token")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Требуется авторизация"})
+		c.Abort()
+		return false
+	}
+
+	// Проверка формата токена
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		m.metrics.AuthFailed("malformed_token")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Неверный формат токена"})
+		c.Abort()
+		return false
+	}
+
+	// Валидация токена
+	claims, err := m.authService.ValidateToken(parts[1])
+	if err != nil {
+		m.metrics.AuthFailed("invalid_token")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Недействительный токен: " + err.Error()})
+		c.Abort()
+		return false
+	}
+
+	// Сохранение данных пользователя в контексте
+	c.Set("userID", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("email", claims.Email)
+	c.Set("isAdmin", claims.IsAdmin)
+	c.Request = c.Request.WithContext(services.WithActor(c.Request.Context(), services.Actor{
+		UserID:   claims.UserID,
+		Username: claims.Username,
+	}))
+	return true
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/monitoring"
+	"mvp_multylink/backend/internal/services"
+)
+
+const testJWTSecret = "test-secret-test-secret-test-secret"
+
+func newAdminRouter(t *testing.T) (*gin.Engine, *services.AuthService, *bool) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	authService := services.NewAuthService(testJWTSecret, time.Hour)
+	auth := NewAuthMiddleware(authService, monitoring.New())
+
+	called := false
+	router := gin.New()
+	router.POST("/api/admin/blocklist", auth.AdminRequired(), func(c *gin.Context) {
+		called = true
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+	return router, authService, &called
+}
+
+func adminRequest(t *testing.T, router *gin.Engine, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/blocklist", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminRequiredRejectsNonAdmin(t *testing.T) {
+	router, authService, called := newAdminRouter(t)
+	token, _, err := authService.GenerateToken(models.User{ID: 1, Username: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := adminRequest(t, router, token)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if *called {
+		t.Fatal("handler ran for a non-admin user")
+	}
+}
+
+func TestAdminRequiredRejectsMissingToken(t *testing.T) {
+	router, _, called := newAdminRouter(t)
+
+	w := adminRequest(t, router, "")
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if *called {
+		t.Fatal("handler ran without a token")
+	}
+}
+
+func TestAdminRequiredAllowsAdmin(t *testing.T) {
+	router, authService, called := newAdminRouter(t)
+	token, _, err := authService.GenerateToken(models.User{ID: 1, Username: "admin", IsAdmin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := adminRequest(t, router, token)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if !*called {
+		t.Fatal("handler did not run for an admin")
+	}
+}
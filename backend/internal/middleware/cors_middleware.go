@@ -1,22 +1,235 @@
 package middleware
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
-// CORSMiddleware возвращает middleware для настройки CORS
-func CORSMiddleware() gin.HandlerFunc {
+// CORSConfig описывает CORS-политику для группы маршрутов
+type CORSConfig struct {
+	// AllowedOrigins содержит разрешенные источники. Поддерживаются точные
+	// значения ("https://example.com"), поддомены ("https://*.example.com")
+	// и "*" для любого источника (только без AllowCredentials)
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge задает время кеширования preflight-ответа браузером
+	MaxAge time.Duration
+}
+
+// DefaultCORSMethods содержит методы, разрешенные по умолчанию
+var DefaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions}
+
+// DefaultCORSHeaders содержит заголовки запроса, разрешенные по умолчанию
+var DefaultCORSHeaders = []string{"Content-Type", "Authorization", "Accept", "Cache-Control", "X-Requested-With", "X-CSRF-Token"}
+
+// PublicCORSConfig возвращает открытую политику для публичного JSON API встраивания.
+// Учетные данные не передаются, поэтому разрешен любой источник. Доступ
+// к защищенным паролем страницам передается заголовком X-Access-Token
+func PublicCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+		AllowedHeaders: []string{"Content-Type", "Accept", "X-Access-Token"},
+		MaxAge:         24 * time.Hour,
+	}
+}
+
+// DashboardCORSConfig возвращает закрытую политику для API личного кабинета
+func DashboardCORSConfig(allowedOrigins []string) CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   allowedOrigins,
+		AllowedMethods:   DefaultCORSMethods,
+		AllowedHeaders:   DefaultCORSHeaders,
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+}
+
+// Validate проверяет согласованность политики
+func (c CORSConfig) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" && c.AllowCredentials {
+			return errors.New("cors: источник \"*\" нельзя использовать вместе с учетными данными")
+		}
+		if strings.Contains(origin, "*") && origin != "*" && !strings.Contains(origin, "://*.") {
+			return errors.New("cors: неверный шаблон источника " + origin)
+		}
+	}
+	return nil
+}
+
+// originMatcher проверяет источник по списку разрешенных
+type originMatcher struct {
+	any      bool
+	exact    map[string]struct{}
+	wildcard []wildcardOrigin
+}
+
+// wildcardOrigin описывает шаблон вида "https://*.example.com"
+type wildcardOrigin struct {
+	scheme string
+	suffix string // ".example.com" или ".example.com:8443"
+}
+
+func newOriginMatcher(origins []string) originMatcher {
+	m := originMatcher{exact: make(map[string]struct{})}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "":
+			continue
+		case origin == "*":
+			m.any = true
+		case strings.Contains(origin, "://*."):
+			scheme, host, _ := strings.Cut(origin, "://")
+			m.wildcard = append(m.wildcard, wildcardOrigin{scheme: scheme, suffix: strings.TrimPrefix(host, "*")})
+		default:
+			m.exact[strings.TrimSuffix(origin, "/")] = struct{}{}
+		}
+	}
+	return m
+}
+
+func (m originMatcher) allowed(origin string) bool {
+	if m.any {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := m.exact[origin]; ok {
+		return true
+	}
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok {
+		return false
+	}
+	for _, w := range m.wildcard {
+		// Шаблон "*.example.com" совпадает только с поддоменами, но не с самим example.com
+		if scheme == w.scheme && strings.HasSuffix(host, w.suffix) && len(host) > len(w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CORSMiddleware возвращает middleware для настройки CORS по указанной политике
+func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
+	matcher := newOriginMatcher(config.AllowedOrigins)
+	// Браузеры отклоняют "*" вместе с учетными данными, а отражение любого
+	// источника открыло бы API всем сайтам, поэтому такая политика не
+	// разрешает ни одного источника (см. Validate)
+	if config.AllowCredentials {
+		matcher.any = false
+	}
+	wildcardResponse := matcher.any
+
+	allowMethods := strings.Join(config.AllowedMethods, ", ")
+	allowHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	allowedMethods := make(map[string]struct{}, len(config.AllowedMethods))
+	for _, method := range config.AllowedMethods {
+		allowedMethods[strings.ToUpper(method)] = struct{}{}
+	}
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		header := c.Writer.Header()
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		// Ответ зависит от Origin, поэтому кеши должны это учитывать
+		if !wildcardResponse {
+			header.Add("Vary", "Origin")
+		}
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" {
+			// Не CORS-запрос
+			c.Next()
+			return
+		}
+
+		if !matcher.allowed(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			// Заголовки не выставляются, браузер сам заблокирует ответ
+			c.Next()
+			return
+		}
+
+		if wildcardResponse {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if preflight {
+			if _, ok := allowedMethods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))]; !ok {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			header.Set("Access-Control-Allow-Methods", allowMethods)
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+			if config.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
+		if exposeHeaders != "" {
+			header.Set("Access-Control-Expose-Headers", exposeHeaders)
+		}
+
 		c.Next()
 	}
 }
+
+// CORSPolicy связывает CORS-политику с префиксом пути
+type CORSPolicy struct {
+	PathPrefix string
+	Config     CORSConfig
+}
+
+// RouteCORSMiddleware возвращает middleware, выбирающий политику по самому
+// длинному совпадающему префиксу пути. Он подключается на уровне движка,
+// чтобы preflight-запросы обрабатывались и для маршрутов без OPTIONS-обработчика
+func RouteCORSMiddleware(policies ...CORSPolicy) gin.HandlerFunc {
+	handlers := make([]gin.HandlerFunc, len(policies))
+	for i, policy := range policies {
+		handlers[i] = CORSMiddleware(policy.Config)
+	}
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		selected := -1
+		for i, policy := range policies {
+			if strings.HasPrefix(path, policy.PathPrefix) &&
+				(selected < 0 || len(policy.PathPrefix) > len(policies[selected].PathPrefix)) {
+				selected = i
+			}
+		}
+		if selected < 0 {
+			c.Next()
+			return
+		}
+		handlers[selected](c)
+	}
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newCORSRouter подключает политики так же, как main: публичное API открыто,
+// API личного кабинета доступно только перечисленным источникам
+func newCORSRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RouteCORSMiddleware(
+		CORSPolicy{PathPrefix: "/api/public/", Config: PublicCORSConfig()},
+		CORSPolicy{PathPrefix: "/api/", Config: DashboardCORSConfig([]string{"https://app.example.com", "https://*.example.org"})},
+	))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/multilinks", ok)
+	router.POST("/api/public/multilinks/:slug/unlock", ok)
+	router.GET("/livez", ok)
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, path, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDashboardCORSAllowsListedOrigins(t *testing.T) {
+	router := newCORSRouter()
+
+	for _, origin := range []string{"https://app.example.com", "https://APP.example.com", "https://a.example.org", "https://b.c.example.org"} {
+		w := corsRequest(router, http.MethodGet, "/api/multilinks", origin, nil)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != origin {
+			t.Errorf("origin %s: Access-Control-Allow-Origin = %q, want it reflected", origin, got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+			t.Errorf("origin %s: Access-Control-Allow-Credentials = %q, want true", origin, got)
+		}
+		if !slices.Contains(w.Header().Values("Vary"), "Origin") {
+			t.Errorf("origin %s: Vary = %q, want Origin", origin, w.Header().Values("Vary"))
+		}
+	}
+}
+
+func TestDashboardCORSRejectsOtherOrigins(t *testing.T) {
+	router := newCORSRouter()
+
+	for _, origin := range []string{
+		"https://evil.example",
+		// Шаблон *.example.org не покрывает сам example.org
+		"https://example.org",
+		"http://a.example.org",
+		"https://a.example.org.evil.test",
+		"https://evilexample.org",
+		"https://app.example.com.evil.test",
+		"null",
+	} {
+		w := corsRequest(router, http.MethodGet, "/api/multilinks", origin, nil)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("origin %s: Access-Control-Allow-Origin = %q, want none", origin, got)
+		}
+		if w.Code != http.StatusOK {
+			t.Errorf("origin %s: status = %d, want the request to reach the handler", origin, w.Code)
+		}
+		if !slices.Contains(w.Header().Values("Vary"), "Origin") {
+			t.Errorf("origin %s: Vary = %q, want Origin", origin, w.Header().Values("Vary"))
+		}
+
+		w = corsRequest(router, http.MethodOptions, "/api/multilinks", origin, map[string]string{"Access-Control-Request-Method": http.MethodGet})
+		if w.Code != http.StatusForbidden {
+			t.Errorf("origin %s: preflight status = %d, want 403", origin, w.Code)
+		}
+	}
+}
+
+func TestPublicCORSPreflightAllowsUnlock(t *testing.T) {
+	router := newCORSRouter()
+
+	w := corsRequest(router, http.MethodOptions, "/api/public/multilinks/demo/unlock", "https://blog.example.net", map[string]string{
+		"Access-Control-Request-Method":  http.MethodPost,
+		"Access-Control-Request-Headers": "content-type, x-access-token",
+	})
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("preflight status = %d, want 204", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want none", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(got, http.MethodPost) {
+		t.Errorf("Access-Control-Allow-Methods = %q, want POST", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "X-Access-Token") {
+		t.Errorf("Access-Control-Allow-Headers = %q, want X-Access-Token", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "86400" {
+		t.Errorf("Access-Control-Max-Age = %q, want 86400", got)
+	}
+}
+
+func TestPublicCORSPreflightRejectsUnlistedMethod(t *testing.T) {
+	router := newCORSRouter()
+
+	w := corsRequest(router, http.MethodOptions, "/api/public/multilinks/demo/unlock", "https://blog.example.net", map[string]string{
+		"Access-Control-Request-Method": http.MethodDelete,
+	})
+	if w.Code != http.StatusForbidden {
+		t.Errorf("preflight status = %d, want 403", w.Code)
+	}
+}
+
+func TestRouteCORSPicksLongestPrefix(t *testing.T) {
+	router := newCORSRouter()
+
+	// Открытая политика /api/public/ действует вместо закрытой /api/
+	w := corsRequest(router, http.MethodPost, "/api/public/multilinks/demo/unlock", "https://blog.example.net", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("public route Access-Control-Allow-Origin = %q, want *", got)
+	}
+
+	// Маршруты вне политик не получают CORS-заголовков
+	w = corsRequest(router, http.MethodGet, "/livez", "https://app.example.com", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("/livez Access-Control-Allow-Origin = %q, want none", got)
+	}
+}
+
+func TestCORSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CORSConfig
+		wantErr bool
+	}{
+		{"public", PublicCORSConfig(), false},
+		{"dashboard", DashboardCORSConfig([]string{"https://app.example.com", "https://*.example.com"}), false},
+		{"any origin with credentials", DashboardCORSConfig([]string{"*"}), true},
+		{"wildcard in the middle", DashboardCORSConfig([]string{"https://app.*.example.com"}), true},
+	}
+	for _, tt := range tests {
+		if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
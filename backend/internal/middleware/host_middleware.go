@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// publicAPIPrefix — маршруты, доступные на пользовательском домене без изменений
+const publicAPIPrefix = "/api/public/"
+
+// hostRoutingExempt — служебные маршруты, которые обслуживаются на любом
+// домене без поиска мультиссылки: проверки работоспособности и метрики не
+// должны зависеть от базы доменов
+var hostRoutingExempt = map[string]bool{
+	"/livez":   true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// HostResolver находит slug мультиссылки по заголовку Host
+type HostResolver interface {
+	ResolveHost(ctx context.Context, host string) (slug string, ok bool, err error)
+}
+
+// HostRouting направляет запросы к пользовательским доменам на их мультиссылки
+// до маршрутизации по slug. Корень домена отдает HTML-страницу мультиссылки,
+// публичное API (переходы, ввод пароля, согласие) доступно как есть, остальные
+// пути отвечают 404. Запросы к доменам сервиса и служебные маршруты передаются
+// дальше без изменений. Если домен не удалось найти из-за ошибки, запрос
+// обрабатывается обычной маршрутизацией, как для домена сервиса
+func HostRouting(logger *slog.Logger, resolver HostResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hostRoutingExempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		slug, ok, err := resolver.ResolveHost(r.Context(), r.Host)
+		if err != nil {
+			logger.WarnContext(r.Context(), "failed to resolve custom domain", "host", r.Host, "error", err)
+		}
+		if err != nil || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/":
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = "/api/public/pages/" + slug
+			r2.URL.RawPath = ""
+			next.ServeHTTP(w, r2)
+		case strings.HasPrefix(r.URL.Path, publicAPIPrefix):
+			next.ServeHTTP(w, r)
+		default:
+			writeJSONError(w, http.StatusNotFound, "Страница не найдена")
+		}
+	})
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeHostResolver сопоставляет пользовательские домены со slug мультиссылок
+type fakeHostResolver struct {
+	slugs map[string]string
+	err   error
+}
+
+func (r fakeHostResolver) ResolveHost(_ context.Context, host string) (string, bool, error) {
+	if r.err != nil {
+		return "", false, r.err
+	}
+	slug, ok := r.slugs[host]
+	return slug, ok, nil
+}
+
+// routedPath возвращает путь, с которым запрос дошел до следующего обработчика,
+// и код ответа
+func routedPath(t *testing.T, resolver HostResolver, host, path string) (string, int) {
+	t.Helper()
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = host
+	w := httptest.NewRecorder()
+	HostRouting(logger, resolver, next).ServeHTTP(w, req)
+	return got, w.Code
+}
+
+func TestHostRouting(t *testing.T) {
+	resolver := fakeHostResolver{slugs: map[string]string{"links.example.com": "demo page"}}
+
+	tests := []struct {
+		name     string
+		host     string
+		path     string
+		wantPath string
+		wantCode int
+	}{
+		{"custom domain root serves page document", "links.example.com", "/", "/api/public/pages/demo page", http.StatusOK},
+		{"custom domain public api passes through", "links.example.com", "/api/public/click/7", "/api/public/click/7", http.StatusOK},
+		{"custom domain other paths are not found", "links.example.com", "/api/multilinks", "", http.StatusNotFound},
+		{"service domain is not rewritten", "api.multylink.test", "/", "/", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, code := routedPath(t, resolver, tt.host, tt.path)
+			if code != tt.wantCode {
+				t.Errorf("status = %d, want %d", code, tt.wantCode)
+			}
+			if path != tt.wantPath {
+				t.Errorf("routed path = %q, want %q", path, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestHostRoutingExemptsServiceRoutes(t *testing.T) {
+	// Резолвер с ошибкой показывает, что служебные маршруты его не вызывают
+	resolver := fakeHostResolver{err: errors.New("db down")}
+	for _, path := range []string{"/livez", "/readyz", "/metrics"} {
+		got, code := routedPath(t, resolver, "links.example.com", path)
+		if code != http.StatusOK || got != path {
+			t.Errorf("%s: routed path = %q, status = %d, want it passed through", path, got, code)
+		}
+	}
+
+	// На пользовательском домене служебные маршруты тоже доступны
+	resolver = fakeHostResolver{slugs: map[string]string{"links.example.com": "demo"}}
+	if got, code := routedPath(t, resolver, "links.example.com", "/readyz"); code != http.StatusOK || got != "/readyz" {
+		t.Errorf("custom domain /readyz: routed path = %q, status = %d, want it passed through", got, code)
+	}
+}
+
+func TestHostRoutingResolverErrorFallsThrough(t *testing.T) {
+	resolver := fakeHostResolver{err: errors.New("db down")}
+	for _, path := range []string{"/", "/api/public/pages/demo"} {
+		got, code := routedPath(t, resolver, "links.example.com", path)
+		if code != http.StatusOK || got != path {
+			t.Errorf("%s: routed path = %q, status = %d, want the normal router", path, got, code)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+-- Начальная схема: пользователи, мультиссылки, кнопки и метрики
+
+CREATE TABLE IF NOT EXISTS users (
+    id            BIGSERIAL PRIMARY KEY,
+    username      VARCHAR(30)  NOT NULL UNIQUE,
+    email         VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    avatar_url    TEXT         NOT NULL DEFAULT '',
+    is_admin      BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id        BIGINT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    display_name   VARCHAR(100) NOT NULL DEFAULT '',
+    bio            TEXT         NOT NULL DEFAULT '',
+    theme_color    VARCHAR(32)  NOT NULL DEFAULT '',
+    background_url TEXT         NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS multilinks (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT       NOT NULL REFERENCES users (id),
+    title       VARCHAR(255) NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    slug        VARCHAR(30)  NOT NULL UNIQUE,
+    is_active   BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_multilinks_user_id ON multilinks (user_id);
+
+CREATE TABLE IF NOT EXISTS link_buttons (
+    id           BIGSERIAL PRIMARY KEY,
+    multilink_id BIGINT       NOT NULL REFERENCES multilinks (id),
+    title        VARCHAR(255) NOT NULL,
+    url          TEXT         NOT NULL,
+    icon         VARCHAR(255) NOT NULL DEFAULT '',
+    color        VARCHAR(32)  NOT NULL DEFAULT '',
+    position     INTEGER      NOT NULL DEFAULT 0,
+    is_active    BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_buttons_multilink_id ON link_buttons (multilink_id, position);
+
+CREATE TABLE IF NOT EXISTS link_metrics (
+    id             BIGSERIAL PRIMARY KEY,
+    link_button_id BIGINT      NOT NULL UNIQUE REFERENCES link_buttons (id),
+    clicks         INTEGER     NOT NULL DEFAULT 0,
+    last_click_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS click_events (
+    id             BIGSERIAL PRIMARY KEY,
+    link_button_id BIGINT      NOT NULL REFERENCES link_buttons (id),
+    ip             VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent     TEXT        NOT NULL DEFAULT '',
+    referer        TEXT        NOT NULL DEFAULT '',
+    utm_source     VARCHAR(255) NOT NULL DEFAULT '',
+    utm_medium     VARCHAR(255) NOT NULL DEFAULT '',
+    utm_campaign   VARCHAR(255) NOT NULL DEFAULT '',
+    utm_content    VARCHAR(255) NOT NULL DEFAULT '',
+    utm_term       VARCHAR(255) NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_click_events_button_created ON click_events (link_button_id, created_at);
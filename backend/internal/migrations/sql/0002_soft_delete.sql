@@ -0,0 +1,11 @@
+-- Мягкое удаление мультиссылок и кнопок
+
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- slug должен быть уникален только среди неудаленных мультиссылок
+ALTER TABLE multilinks DROP CONSTRAINT IF EXISTS multilinks_slug_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_multilinks_slug_active ON multilinks (slug) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_multilinks_deleted_at ON multilinks (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_link_buttons_deleted_at ON link_buttons (deleted_at) WHERE deleted_at IS NOT NULL;
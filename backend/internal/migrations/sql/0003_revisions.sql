@@ -0,0 +1,13 @@
+-- История изменений мультиссылок: снимок страницы и упорядоченных кнопок после каждого изменения
+
+CREATE TABLE IF NOT EXISTS multilink_revisions (
+    id           BIGSERIAL PRIMARY KEY,
+    multilink_id BIGINT      NOT NULL REFERENCES multilinks (id),
+    revision     INTEGER     NOT NULL,
+    author_id    BIGINT      REFERENCES users (id) ON DELETE SET NULL,
+    author_name  VARCHAR(30) NOT NULL DEFAULT '',
+    action       VARCHAR(32) NOT NULL,
+    snapshot     JSONB       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (multilink_id, revision)
+);
@@ -0,0 +1,8 @@
+-- Черновики: изменения мультиссылки и кнопок, еще не опубликованные на публичной странице
+
+CREATE TABLE IF NOT EXISTS multilink_drafts (
+    multilink_id BIGINT      PRIMARY KEY REFERENCES multilinks (id),
+    snapshot     JSONB       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
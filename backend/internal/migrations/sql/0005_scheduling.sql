@@ -0,0 +1,8 @@
+-- Расписание публикации мультиссылок и окна видимости кнопок
+
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
+
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS visible_from TIMESTAMPTZ;
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS visible_until TIMESTAMPTZ;
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
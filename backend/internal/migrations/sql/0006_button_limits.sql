@@ -0,0 +1,7 @@
+-- Ограничение кнопок по количеству кликов и сроку действия
+
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS max_clicks INTEGER CHECK (max_clicks > 0);
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS remaining_clicks INTEGER CHECK (remaining_clicks >= 0);
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS fallback_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS exhausted_action VARCHAR(16) NOT NULL DEFAULT 'hide';
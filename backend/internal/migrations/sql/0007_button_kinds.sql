@@ -0,0 +1,3 @@
+-- Типы кнопок: ссылка, почта, телефон, SMS, мессенджеры, заголовок и разделитель
+
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'url';
@@ -0,0 +1,9 @@
+-- Записи списка блокировки, добавленные администраторами
+
+CREATE TABLE IF NOT EXISTS blocked_domains (
+    id         BIGSERIAL PRIMARY KEY,
+    pattern    VARCHAR(2048) NOT NULL UNIQUE,
+    reason     TEXT          NOT NULL DEFAULT '',
+    created_by BIGINT        REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
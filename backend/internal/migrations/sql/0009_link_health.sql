@@ -0,0 +1,35 @@
+-- Проверка доступности ссылок кнопок и уведомления владельцев
+
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS health VARCHAR(16) NOT NULL DEFAULT 'unknown';
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS health_checked_at TIMESTAMPTZ;
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS broken_since TIMESTAMPTZ;
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS broken_notified_at TIMESTAMPTZ;
+
+-- История проверок удаляется вместе с кнопкой
+CREATE TABLE IF NOT EXISTS link_checks (
+    id             BIGSERIAL PRIMARY KEY,
+    link_button_id BIGINT      NOT NULL REFERENCES link_buttons (id) ON DELETE CASCADE,
+    url            TEXT        NOT NULL,
+    status_code    INTEGER     NOT NULL DEFAULT 0,
+    final_url      TEXT        NOT NULL DEFAULT '',
+    redirects      JSONB       NOT NULL DEFAULT '[]',
+    error          TEXT        NOT NULL DEFAULT '',
+    health         VARCHAR(16) NOT NULL,
+    duration_ms    INTEGER     NOT NULL DEFAULT 0,
+    checked_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_checks_button_checked ON link_checks (link_button_id, checked_at DESC);
+CREATE INDEX IF NOT EXISTS idx_link_checks_checked_at ON link_checks (checked_at);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind       VARCHAR(32) NOT NULL,
+    title      TEXT        NOT NULL,
+    payload    JSONB       NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications (user_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- Защита страниц мультиссылок паролем. Пустой хэш означает, что страница открыта
+
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS password_hash VARCHAR(255) NOT NULL DEFAULT '';
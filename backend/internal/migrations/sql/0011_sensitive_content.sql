@@ -0,0 +1,9 @@
+-- Отметка чувствительного содержимого (18+, алкоголь и т.п.) для страниц и кнопок.
+-- forced_sensitive_reason задает администратор, владелец страницы снять ее не может
+
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS sensitive BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS sensitive_reason VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS forced_sensitive_reason VARCHAR(16) NOT NULL DEFAULT '';
+
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS sensitive BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE link_buttons ADD COLUMN IF NOT EXISTS sensitive_reason VARCHAR(16) NOT NULL DEFAULT '';
@@ -0,0 +1,16 @@
+-- Пользовательские домены мультиссылок. Домен может ожидать подтверждения
+-- у нескольких пользователей, но подтвержденным бывает только у одной мультиссылки
+
+CREATE TABLE IF NOT EXISTS custom_domains (
+    id           BIGSERIAL PRIMARY KEY,
+    multilink_id BIGINT       NOT NULL REFERENCES multilinks (id) ON DELETE CASCADE,
+    hostname     VARCHAR(253) NOT NULL,
+    token        VARCHAR(64)  NOT NULL,
+    verified_at  TIMESTAMPTZ,
+    method       VARCHAR(8)   NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    UNIQUE (multilink_id, hostname)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS custom_domains_verified_hostname_idx
+    ON custom_domains (hostname) WHERE verified_at IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Оформление страниц: пресет и переопределенные поля в JSON.
+-- Пустой пресет означает пресет по умолчанию
+
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS theme_preset VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE multilinks ADD COLUMN IF NOT EXISTS theme_overrides JSONB NOT NULL DEFAULT '{}';
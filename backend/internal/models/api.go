@@ -1,45 +1,91 @@
 package models
 
+import (
+	"time"
+)
+
 // CreateMultiLinkRequest представляет запрос на создание мультиссылки
 type CreateMultiLinkRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	Slug        string `json:"slug" binding:"omitempty,min=3,max=30"`
-	IsActive    bool   `json:"is_active"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	Slug        string     `json:"slug" binding:"omitempty,min=3,max=30"`
+	IsActive    bool       `json:"is_active"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+	// Причина указывается только вместе с Sensitive; без нее используется "other"
+	Sensitive       bool   `json:"sensitive"`
+	SensitiveReason string `json:"sensitive_reason" binding:"omitempty,oneof=adult alcohol tobacco gambling violence other"`
 }
 
 // UpdateMultiLinkRequest представляет запрос на обновление мультиссылки
 type UpdateMultiLinkRequest struct {
-	Title       string `json:"title" binding:"omitempty"`
-	Description string `json:"description"`
-	Slug        string `json:"slug" binding:"omitempty,min=3,max=30"`
-	IsActive    bool   `json:"is_active"`
+	Title       string     `json:"title" binding:"omitempty"`
+	Description string     `json:"description"`
+	Slug        string     `json:"slug" binding:"omitempty,min=3,max=30"`
+	IsActive    bool       `json:"is_active"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+	// Причина указывается только вместе с Sensitive; без нее используется "other"
+	Sensitive       bool   `json:"sensitive"`
+	SensitiveReason string `json:"sensitive_reason" binding:"omitempty,oneof=adult alcohol tobacco gambling violence other"`
 }
 
-// CreateLinkButtonRequest представляет запрос на создание кнопки-ссылки
+// CreateLinkButtonRequest представляет запрос на создание кнопки-ссылки.
+// Границы окна видимости принимаются в RFC 3339 или как местное время
+// ("2006-01-02T15:04") в часовом поясе Timezone
 type CreateLinkButtonRequest struct {
-	Title    string `json:"title" binding:"required"`
-	URL      string `json:"url" binding:"required,url"`
-	Icon     string `json:"icon"`
-	Color    string `json:"color"`
-	Position int    `json:"position"`
-	IsActive bool   `json:"is_active"`
+	Kind            string     `json:"kind" binding:"omitempty,oneof=url email phone sms telegram whatsapp header divider"`
+	Title           string     `json:"title"`
+	URL             string     `json:"url"`
+	Icon            string     `json:"icon"`
+	Color           string     `json:"color"`
+	Position        int        `json:"position"`
+	IsActive        bool       `json:"is_active"`
+	VisibleFrom     *string    `json:"visible_from"`
+	VisibleUntil    *string    `json:"visible_until"`
+	Timezone        string     `json:"timezone"`
+	MaxClicks       *int       `json:"max_clicks" binding:"omitempty,min=1"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	FallbackURL     string     `json:"fallback_url" binding:"omitempty,url"`
+	ExhaustedAction string     `json:"exhausted_action" binding:"omitempty,oneof=hide fallback"`
+	Sensitive       bool       `json:"sensitive"`
+	SensitiveReason string     `json:"sensitive_reason" binding:"omitempty,oneof=adult alcohol tobacco gambling violence other"`
 }
 
 // UpdateLinkButtonRequest представляет запрос на обновление кнопки-ссылки
 type UpdateLinkButtonRequest struct {
-	Title    string `json:"title" binding:"omitempty"`
-	URL      string `json:"url" binding:"omitempty,url"`
-	Icon     string `json:"icon"`
-	Color    string `json:"color"`
-	Position int    `json:"position"`
-	IsActive bool   `json:"is_active"`
+	Kind            string     `json:"kind" binding:"omitempty,oneof=url email phone sms telegram whatsapp header divider"`
+	Title           string     `json:"title" binding:"omitempty"`
+	URL             string     `json:"url"`
+	Icon            string     `json:"icon"`
+	Color           string     `json:"color"`
+	Position        int        `json:"position"`
+	IsActive        bool       `json:"is_active"`
+	VisibleFrom     *string    `json:"visible_from"`
+	VisibleUntil    *string    `json:"visible_until"`
+	Timezone        string     `json:"timezone"`
+	MaxClicks       *int       `json:"max_clicks" binding:"omitempty,min=1"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	FallbackURL     string     `json:"fallback_url" binding:"omitempty,url"`
+	ExhaustedAction string     `json:"exhausted_action" binding:"omitempty,oneof=hide fallback"`
+	Sensitive       bool       `json:"sensitive"`
+	SensitiveReason string     `json:"sensitive_reason" binding:"omitempty,oneof=adult alcohol tobacco gambling violence other"`
+}
+
+// ButtonPosition представляет новую позицию кнопки в запросе на изменение порядка
+type ButtonPosition struct {
+	ID       int64 `json:"id"`
+	Position int   `json:"position"`
 }
 
 // MultiLinkResponse представляет ответ с данными мультиссылки и её кнопками
 type MultiLinkResponse struct {
 	MultiLink MultiLink    `json:"multilink"`
 	Buttons   []LinkButton `json:"buttons,omitempty"`
+	// Theme содержит оформление страницы с примененными переопределениями
+	Theme *Theme `json:"theme,omitempty"`
+	// OGImage содержит адрес карточки страницы для og:image (только в публичном API)
+	OGImage string `json:"og_image,omitempty"`
 }
 
 // MultiLinkListResponse представляет ответ со списком мультиссылок пользователя
@@ -63,3 +109,119 @@ type ButtonMetricsData struct {
 	Clicks     int     `json:"clicks"`
 	Percentage float64 `json:"percentage"`
 }
+
+// TrashResponse представляет содержимое корзины пользователя
+type TrashResponse struct {
+	MultiLinks []MultiLink  `json:"multilinks"`
+	Buttons    []LinkButton `json:"buttons"`
+}
+
+// AddBlockedDomainRequest представляет запрос на добавление записи в список блокировки
+type AddBlockedDomainRequest struct {
+	Pattern string `json:"pattern" binding:"required,max=2048"`
+	Reason  string `json:"reason"`
+}
+
+// SetPasswordRequest представляет запрос на установку пароля мультиссылки.
+// Длина ограничена сверху, так как bcrypt учитывает только первые 72 байта
+type SetPasswordRequest struct {
+	Password string `json:"password" binding:"required,min=4,max=72"`
+}
+
+// UnlockRequest представляет запрос посетителя на открытие защищенной страницы
+type UnlockRequest struct {
+	Password string `json:"password" binding:"required,max=72"`
+}
+
+// UnlockResponse содержит токен доступа к защищенной странице
+type UnlockResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PasswordChallenge возвращается вместо защищенной страницы, пока посетитель
+// не ввел пароль. Содержимое страницы, включая заголовок, не раскрывается
+type PasswordChallenge struct {
+	PasswordRequired bool   `json:"password_required"`
+	Slug             string `json:"slug"`
+}
+
+// ForceSensitiveRequest представляет запрос администратора на принудительную
+// отметку страницы как чувствительной
+type ForceSensitiveRequest struct {
+	Reason string `json:"reason" binding:"required,oneof=adult alcohol tobacco gambling violence other"`
+}
+
+// ConsentRequest представляет подтверждение посетителя, что он готов
+// видеть содержимое указанных категорий
+type ConsentRequest struct {
+	Reasons []string `json:"reasons" binding:"required,min=1,dive,oneof=adult alcohol tobacco gambling violence other"`
+}
+
+// ConsentChallenge возвращается вместо чувствительной страницы, пока
+// посетитель не подтвердил, что готов ее увидеть
+type ConsentChallenge struct {
+	ConsentRequired bool   `json:"consent_required"`
+	Slug            string `json:"slug"`
+	Reason          string `json:"reason"`
+}
+
+// AddCustomDomainRequest представляет запрос на привязку домена к мультиссылке
+type AddCustomDomainRequest struct {
+	Hostname string `json:"hostname" binding:"required,max=253"`
+}
+
+// VerifyCustomDomainRequest представляет запрос на проверку владения доменом
+type VerifyCustomDomainRequest struct {
+	Method string `json:"method" binding:"required,oneof=dns http"`
+}
+
+// UnfurlRequest представляет запрос на получение предпросмотра ссылки
+type UnfurlRequest struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// QRCodeRequest представляет параметры QR-кода, передаваемые в строке запроса.
+// Цвета задаются в формате rrggbb, логотип — ссылкой на изображение
+type QRCodeRequest struct {
+	Format     string `form:"format" binding:"omitempty,oneof=png svg"`
+	Size       int    `form:"size" binding:"omitempty,min=128,max=2048"`
+	Level      string `form:"level" binding:"omitempty,oneof=L M Q H"`
+	Foreground string `form:"fg" binding:"omitempty,max=7"`
+	Background string `form:"bg" binding:"omitempty,max=7"`
+	LogoURL    string `form:"logo" binding:"omitempty,max=2048"`
+}
+
+// ButtonHealthResponse представляет состояние ссылки кнопки и историю проверок
+type ButtonHealthResponse struct {
+	ButtonID        int64       `json:"button_id"`
+	Health          string      `json:"health"`
+	HealthCheckedAt *time.Time  `json:"health_checked_at,omitempty"`
+	BrokenSince     *time.Time  `json:"broken_since,omitempty"`
+	Checks          []LinkCheck `json:"checks"`
+}
+
+// RevisionListItem представляет ревизию в списке истории вместе с отличиями от предыдущей
+type RevisionListItem struct {
+	ID         int64        `json:"id"`
+	Revision   int          `json:"revision"`
+	AuthorID   *int64       `json:"author_id,omitempty"`
+	AuthorName string       `json:"author_name,omitempty"`
+	Action     string       `json:"action"`
+	CreatedAt  time.Time    `json:"created_at"`
+	Diff       RevisionDiff `json:"diff"`
+}
+
+// RevisionListResponse представляет ответ со списком ревизий мультиссылки
+type RevisionListResponse struct {
+	Revisions []RevisionListItem `json:"revisions"`
+	Total     int                `json:"total"`
+}
+
+// UploadedImage представляет загруженное изображение: адрес размера по
+// умолчанию и адреса всех стандартных размеров
+type UploadedImage struct {
+	Kind     string            `json:"kind"`
+	URL      string            `json:"url"`
+	Variants map[string]string `json:"variants"`
+}
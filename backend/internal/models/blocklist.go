@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// BlockedDomain представляет запись списка блокировки, добавленную администратором.
+// Pattern содержит домен (блокирует и поддомены) или префикс ссылки host/path
+type BlockedDomain struct {
+	ID        int64     `json:"id" db:"id"`
+	Pattern   string    `json:"pattern" db:"pattern"`
+	Reason    string    `json:"reason,omitempty" db:"reason"`
+	CreatedBy *int64    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// BlockedButton представляет кнопку, ссылка которой попала в список блокировки
+type BlockedButton struct {
+	ButtonID int64  `json:"button_id"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Pattern  string `json:"pattern"`
+	Source   string `json:"source"`
+}
+
+// BlocklistReportItem представляет мультиссылку с заблокированными кнопками
+type BlocklistReportItem struct {
+	MultiLinkID int64           `json:"multilink_id"`
+	UserID      int64           `json:"user_id"`
+	Title       string          `json:"title"`
+	Slug        string          `json:"slug"`
+	Buttons     []BlockedButton `json:"buttons"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// CustomDomain представляет пользовательский домен мультиссылки. Запросы
+// к подтвержденному домену открывают страницу мультиссылки
+type CustomDomain struct {
+	ID          int64      `json:"id" db:"id"`
+	MultiLinkID int64      `json:"multilink_id" db:"multilink_id"`
+	Hostname    string     `json:"hostname" db:"hostname"`
+	Token       string     `json:"token" db:"token"` // Токен подтверждения владения
+	VerifiedAt  *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	Method      string     `json:"method,omitempty" db:"method"` // Способ подтверждения: dns или http
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// DomainVerification описывает, где владелец домена должен разместить токен
+type DomainVerification struct {
+	TXTName  string `json:"txt_name"`
+	TXTValue string `json:"txt_value"`
+	HTTPURL  string `json:"http_url"`
+	HTTPBody string `json:"http_body"`
+}
+
+// CustomDomainResponse представляет домен с инструкцией по подтверждению
+type CustomDomainResponse struct {
+	CustomDomain
+	Verification *DomainVerification `json:"verification,omitempty"`
+}
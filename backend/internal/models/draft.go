@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// MultiLinkDraft представляет неопубликованные изменения мультиссылки и ее кнопок.
+// Кнопки, созданные в черновике, имеют отрицательные временные ID до публикации
+type MultiLinkDraft struct {
+	MultiLinkID int64            `json:"multilink_id" db:"multilink_id"`
+	Snapshot    RevisionSnapshot `json:"snapshot" db:"snapshot"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+}
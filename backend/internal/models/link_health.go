@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Состояния ссылки кнопки по результатам проверки доступности
+const (
+	LinkHealthUnknown = "unknown"
+	LinkHealthOK      = "ok"
+	LinkHealthBroken  = "broken"
+)
+
+// LinkCheck представляет результат одной проверки ссылки кнопки
+type LinkCheck struct {
+	ID           int64     `json:"id" db:"id"`
+	LinkButtonID int64     `json:"link_button_id" db:"link_button_id"`
+	URL          string    `json:"url" db:"url"`
+	StatusCode   int       `json:"status_code,omitempty" db:"status_code"`
+	FinalURL     string    `json:"final_url,omitempty" db:"final_url"`
+	Redirects    []string  `json:"redirects,omitempty" db:"redirects"`
+	Error        string    `json:"error,omitempty" db:"error"`
+	Health       string    `json:"health" db:"health"`
+	DurationMs   int       `json:"duration_ms" db:"duration_ms"`
+	CheckedAt    time.Time `json:"checked_at" db:"checked_at"`
+}
+
+// BrokenLink представляет неработающую ссылку, о которой нужно уведомить владельца
+type BrokenLink struct {
+	ButtonID       int64     `json:"button_id"`
+	MultiLinkID    int64     `json:"multilink_id"`
+	UserID         int64     `json:"-"`
+	MultiLinkTitle string    `json:"multilink_title"`
+	Title          string    `json:"title"`
+	URL            string    `json:"url"`
+	BrokenSince    time.Time `json:"broken_since"`
+}
+
+// Типы уведомлений
+const (
+	NotificationKindBrokenLinks = "broken_links"
+)
+
+// Notification представляет уведомление пользователя
+type Notification struct {
+	ID        int64           `json:"id" db:"id"`
+	UserID    int64           `json:"user_id" db:"user_id"`
+	Kind      string          `json:"kind" db:"kind"`
+	Title     string          `json:"title" db:"title"`
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	ReadAt    *time.Time      `json:"read_at,omitempty" db:"read_at"`
+}
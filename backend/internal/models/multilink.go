@@ -6,30 +6,95 @@ import (
 
 // MultiLink представляет основную страницу пользователя с мультиссылками
 type MultiLink struct {
-	ID          int64     `json:"id" db:"id"`
-	UserID      int64     `json:"user_id" db:"user_id"`
-	Title       string    `json:"title" db:"title"`
-	Description string    `json:"description,omitempty" db:"description"`
-	Slug        string    `json:"slug" db:"slug"` // Уникальный идентификатор для URL
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          int64      `json:"id" db:"id"`
+	UserID      int64      `json:"user_id" db:"user_id"`
+	Title       string     `json:"title" db:"title"`
+	Description string     `json:"description,omitempty" db:"description"`
+	Slug        string     `json:"slug" db:"slug"` // Уникальный идентификатор для URL
+	IsActive    bool       `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`     // Время перемещения в корзину
+	PublishAt   *time.Time `json:"publish_at,omitempty" db:"publish_at"`     // Страница доступна не раньше этого времени
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty" db:"unpublish_at"` // Страница скрывается в это время
+	// Чувствительное содержимое: посетитель должен подтвердить, что готов его
+	// увидеть. ForcedSensitiveReason задает администратор, см. SensitiveReason*
+	Sensitive             bool   `json:"sensitive" db:"sensitive"`
+	SensitiveReason       string `json:"sensitive_reason,omitempty" db:"sensitive_reason"`
+	ForcedSensitiveReason string `json:"forced_sensitive_reason,omitempty" db:"forced_sensitive_reason"`
+	// Хэш пароля страницы. Пароль задается отдельно от черновика и не входит в ревизии
+	PasswordHash      string `json:"-" db:"password_hash"`
+	PasswordProtected bool   `json:"password_protected" db:"-"`
+	// Оформление страницы. Как и пароль, задается отдельно от черновика и не входит в ревизии
+	ThemePreset    string         `json:"theme_preset,omitempty" db:"theme_preset"`
+	ThemeOverrides ThemeOverrides `json:"theme_overrides" db:"theme_overrides"`
 }
 
 // LinkButton представляет кнопку-ссылку на странице пользователя
 type LinkButton struct {
-	ID          int64     `json:"id" db:"id"`
-	MultiLinkID int64     `json:"multilink_id" db:"multilink_id"`
-	Title       string    `json:"title" db:"title"`
-	URL         string    `json:"url" db:"url"`
-	Icon        string    `json:"icon,omitempty" db:"icon"`
-	Color       string    `json:"color,omitempty" db:"color"`
-	Position    int       `json:"position" db:"position"` // Порядок отображения
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          int64      `json:"id" db:"id"`
+	MultiLinkID int64      `json:"multilink_id" db:"multilink_id"`
+	Kind        string     `json:"kind" db:"kind"` // Тип кнопки, см. ButtonKind*
+	Title       string     `json:"title" db:"title"`
+	URL         string     `json:"url" db:"url"`
+	Icon        string     `json:"icon,omitempty" db:"icon"` // Имя встроенного значка или адрес загруженного изображения
+	Color       string     `json:"color,omitempty" db:"color"`
+	Position    int        `json:"position" db:"position"` // Порядок отображения
+	IsActive    bool       `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // Время перемещения в корзину
+	// Окно видимости кнопки. Timezone (IANA) задает, в каком поясе были указаны границы
+	VisibleFrom  *time.Time `json:"visible_from,omitempty" db:"visible_from"`
+	VisibleUntil *time.Time `json:"visible_until,omitempty" db:"visible_until"`
+	Timezone     string     `json:"timezone,omitempty" db:"timezone"`
+	// Ограничения кнопки: после MaxClicks кликов или после ExpiresAt кнопка
+	// скрывается или ведет на FallbackURL, в зависимости от ExhaustedAction
+	MaxClicks       *int       `json:"max_clicks,omitempty" db:"max_clicks"`
+	RemainingClicks *int       `json:"remaining_clicks,omitempty" db:"remaining_clicks"` // Только для чтения
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	FallbackURL     string     `json:"fallback_url,omitempty" db:"fallback_url"`
+	ExhaustedAction string     `json:"exhausted_action,omitempty" db:"exhausted_action"`
+	// Состояние ссылки по результатам фоновой проверки доступности, см. LinkHealth*
+	Health          string     `json:"health,omitempty" db:"health"`
+	HealthCheckedAt *time.Time `json:"health_checked_at,omitempty" db:"health_checked_at"`
+	BrokenSince     *time.Time `json:"broken_since,omitempty" db:"broken_since"`
+	// Чувствительное содержимое: переход требует подтверждения посетителя
+	Sensitive       bool   `json:"sensitive" db:"sensitive"`
+	SensitiveReason string `json:"sensitive_reason,omitempty" db:"sensitive_reason"`
+	// Blocked вычисляется при чтении: ссылка кнопки находится в списке блокировки
+	Blocked bool `json:"blocked,omitempty" db:"-"`
 }
 
+// Действия для исчерпанной кнопки
+const (
+	ExhaustedActionHide     = "hide"
+	ExhaustedActionFallback = "fallback"
+)
+
+// Типы кнопок. Для кликабельных типов поле URL содержит адрес, e-mail,
+// номер телефона или имя пользователя, из которых строится исходящая ссылка
+const (
+	ButtonKindURL      = "url"
+	ButtonKindEmail    = "email"
+	ButtonKindPhone    = "phone"
+	ButtonKindSMS      = "sms"
+	ButtonKindTelegram = "telegram"
+	ButtonKindWhatsApp = "whatsapp"
+	ButtonKindHeader   = "header"
+	ButtonKindDivider  = "divider"
+)
+
+// Категории чувствительного содержимого
+const (
+	SensitiveReasonAdult    = "adult"
+	SensitiveReasonAlcohol  = "alcohol"
+	SensitiveReasonTobacco  = "tobacco"
+	SensitiveReasonGambling = "gambling"
+	SensitiveReasonViolence = "violence"
+	SensitiveReasonOther    = "other"
+)
+
 // LinkMetrics представляет метрики для кнопок-ссылок
 type LinkMetrics struct {
 	ID           int64     `json:"id" db:"id"`
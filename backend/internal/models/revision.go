@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// Действия, после которых сохраняется ревизия мультиссылки
+const (
+	RevisionActionUpdateMultiLink = "update_multilink"
+	RevisionActionUpdateButton    = "update_button"
+	RevisionActionDeleteButton    = "delete_button"
+	RevisionActionReorderButtons  = "reorder_buttons"
+	RevisionActionRestore         = "restore_revision"
+	RevisionActionPublish         = "publish_draft"
+)
+
+// RevisionSnapshot представляет состояние мультиссылки и ее кнопок в порядке отображения
+type RevisionSnapshot struct {
+	MultiLink MultiLink    `json:"multilink"`
+	Buttons   []LinkButton `json:"buttons"`
+}
+
+// MultiLinkRevision представляет сохраненную ревизию мультиссылки
+type MultiLinkRevision struct {
+	ID          int64            `json:"id" db:"id"`
+	MultiLinkID int64            `json:"multilink_id" db:"multilink_id"`
+	Revision    int              `json:"revision" db:"revision"` // Номер ревизии в пределах мультиссылки
+	AuthorID    *int64           `json:"author_id,omitempty" db:"author_id"`
+	AuthorName  string           `json:"author_name,omitempty" db:"author_name"`
+	Action      string           `json:"action" db:"action"`
+	Snapshot    RevisionSnapshot `json:"snapshot" db:"snapshot"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+}
+
+// FieldChange описывает изменение одного поля
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// ButtonChange описывает изменения полей одной кнопки
+type ButtonChange struct {
+	ButtonID int64         `json:"button_id"`
+	Title    string        `json:"title"`
+	Changes  []FieldChange `json:"changes"`
+}
+
+// RevisionDiff описывает отличия ревизии от предыдущей
+type RevisionDiff struct {
+	MultiLink      []FieldChange  `json:"multilink,omitempty"`
+	ButtonsAdded   []LinkButton   `json:"buttons_added,omitempty"`
+	ButtonsRemoved []LinkButton   `json:"buttons_removed,omitempty"`
+	ButtonsChanged []ButtonChange `json:"buttons_changed,omitempty"`
+	OrderChanged   bool           `json:"order_changed"`
+}
@@ -0,0 +1,48 @@
+package models
+
+// Режимы оформления
+const (
+	ThemeModeLight = "light"
+	ThemeModeDark  = "dark"
+)
+
+// Формы кнопок
+const (
+	ButtonShapeSquare  = "square"
+	ButtonShapeRounded = "rounded"
+	ButtonShapePill    = "pill"
+)
+
+// Theme описывает оформление страницы после применения переопределений
+// к пресету. Цвета хранятся в формате #rrggbb
+type Theme struct {
+	Preset          string `json:"preset"`
+	Mode            string `json:"mode"`
+	BackgroundColor string `json:"background_color"`
+	TextColor       string `json:"text_color"`
+	ButtonColor     string `json:"button_color"`
+	ButtonTextColor string `json:"button_text_color"`
+	ButtonShape     string `json:"button_shape"`
+	Font            string `json:"font"`
+	// FontFamily — значение CSS font-family для выбранного шрифта
+	FontFamily string `json:"font_family"`
+}
+
+// ThemeOverrides содержит поля оформления, переопределенные для страницы.
+// Пустое поле означает значение из пресета
+type ThemeOverrides struct {
+	Mode            string `json:"mode,omitempty"`
+	BackgroundColor string `json:"background_color,omitempty"`
+	TextColor       string `json:"text_color,omitempty"`
+	ButtonColor     string `json:"button_color,omitempty"`
+	ButtonTextColor string `json:"button_text_color,omitempty"`
+	ButtonShape     string `json:"button_shape,omitempty"`
+	Font            string `json:"font,omitempty"`
+}
+
+// UpdateThemeRequest представляет запрос на выбор пресета и переопределений
+// оформления страницы
+type UpdateThemeRequest struct {
+	Preset    string         `json:"preset" binding:"required,max=32"`
+	Overrides ThemeOverrides `json:"overrides"`
+}
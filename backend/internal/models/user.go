@@ -8,7 +8,7 @@ import (
 type User struct {
 	ID        int64     `json:"id" db:"id"`
 	Username  string    `json:"username" db:"username"`
-	Email     string    `json:"email" db:\"email\"`
+	Email     string    `json:"email" db:"email"`
 	Password  string    `json:"-" db:"password_hash"`
 	AvatarURL string    `json:"avatar_url,omitempty" db:"avatar_url"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
@@ -28,7 +28,7 @@ type UpdateProfileRequest struct {
 	DisplayName   string `json:"display_name,omitempty"`
 	AvatarURL     string `json:"avatar_url,omitempty"`
 	Bio           string `json:"bio,omitempty"`
-	ThemeColor    string `json:"theme_color,omitempty"`
+	ThemeColor    string `json:"theme_color,omitempty" binding:"omitempty,hexcolor"`
 	BackgroundURL string `json:"background_url,omitempty"`
 }
 
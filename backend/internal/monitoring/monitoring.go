@@ -0,0 +1,133 @@
+package monitoring
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace используется как префикс всех метрик приложения
+const namespace = "multylink"
+
+// Metrics содержит Prometheus-метрики API
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.HistogramVec
+	clicksRecorded      prometheus.Counter
+	clicksFailed        *prometheus.CounterVec
+	authFailures        *prometheus.CounterVec
+	rateLimitRejections *prometheus.CounterVec
+}
+
+// New создает набор метрик в собственном реестре вместе со стандартными
+// метриками процесса и Go runtime
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Длительность обработки HTTP-запросов по маршрутам.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		clicksRecorded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "clicks_recorded_total",
+			Help:      "Количество успешно записанных кликов по кнопкам.",
+		}),
+		clicksFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "clicks_failed_total",
+			Help:      "Количество кликов, которые не удалось записать, по этапу.",
+		}, []string{"stage"}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_failures_total",
+			Help:      "Количество отказов в аутентификации по причине.",
+		}, []string{"reason"}),
+		rateLimitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_rejections_total",
+			Help:      "Количество запросов, отклоненных ограничением частоты.",
+		}, []string{"route"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.clicksRecorded,
+		m.clicksFailed,
+		m.authFailures,
+		m.rateLimitRejections,
+	)
+
+	return m
+}
+
+// RegisterDB добавляет метрики пула соединений из sql.DBStats
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler возвращает обработчик /metrics в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// HTTPMiddleware измеряет длительность запросов. Маршрут берется из шаблона
+// gin, чтобы ID в пути не раздували число временных рядов
+func (m *Metrics) HTTPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.httpRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// ClickRecorded учитывает успешно записанный клик
+func (m *Metrics) ClickRecorded() {
+	if m == nil {
+		return
+	}
+	m.clicksRecorded.Inc()
+}
+
+// ClickFailed учитывает ошибку записи клика на указанном этапе
+func (m *Metrics) ClickFailed(stage string) {
+	if m == nil {
+		return
+	}
+	m.clicksFailed.WithLabelValues(stage).Inc()
+}
+
+// AuthFailed учитывает отказ в аутентификации
+func (m *Metrics) AuthFailed(reason string) {
+	if m == nil {
+		return
+	}
+	m.authFailures.WithLabelValues(reason).Inc()
+}
+
+// RateLimitRejected учитывает запрос, отклоненный ограничением частоты
+func (m *Metrics) RateLimitRejected(route string) {
+	if m == nil {
+		return
+	}
+	m.rateLimitRejections.WithLabelValues(route).Inc()
+}
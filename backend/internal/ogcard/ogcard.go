@@ -0,0 +1,230 @@
+// Package ogcard рисует карточки страниц для предпросмотра в соцсетях и
+// мессенджерах (og:image): заголовок, аватар, цвет оформления и число ссылок
+package ogcard
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"mvp_multylink/backend/internal/colors"
+)
+
+// Размер карточки, рекомендуемый для og:image
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	margin      = 80
+	avatarSize  = 240
+	titleSize   = 64
+	textSize    = 36
+	footerSize  = 32
+	maxTitleRow = 2
+	brand       = "MultyLink"
+)
+
+// Card описывает содержимое карточки
+type Card struct {
+	Title    string
+	Subtitle string
+	Footer   string
+	Accent   color.RGBA
+	// Text задает цвет текста. Если не задан, выбирается белый или темный
+	// в зависимости от контраста с Accent
+	Text color.RGBA
+	// Avatar выводится в круге слева от текста, если задан
+	Avatar image.Image
+}
+
+var (
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	ink   = color.RGBA{R: 0x11, G: 0x18, B: 0x27, A: 0xff}
+)
+
+type faces struct {
+	title, text, footer font.Face
+}
+
+var (
+	loadFaces = sync.OnceValues(func() (faces, error) {
+		bold, err := opentype.Parse(gobold.TTF)
+		if err != nil {
+			return faces{}, err
+		}
+		regular, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			return faces{}, err
+		}
+
+		var f faces
+		if f.title, err = newFace(bold, titleSize); err != nil {
+			return faces{}, err
+		}
+		if f.text, err = newFace(regular, textSize); err != nil {
+			return faces{}, err
+		}
+		if f.footer, err = newFace(bold, footerSize); err != nil {
+			return faces{}, err
+		}
+		return f, nil
+	})
+	// drawMu защищает шрифты: font.Face из opentype не потокобезопасен
+	drawMu sync.Mutex
+)
+
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// Render рисует карточку в PNG размером Width×Height. Цвет текста выбирается
+// по контрасту с цветом оформления
+func Render(card Card) ([]byte, error) {
+	f, err := loadFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(card.Accent), image.Point{}, draw.Src)
+
+	fg := card.Text
+	if fg.A == 0 {
+		fg = white
+		if colors.Contrast(card.Accent, ink) > colors.Contrast(card.Accent, white) {
+			fg = ink
+		}
+	}
+
+	textX := margin
+	if card.Avatar != nil {
+		top := (Height - avatarSize) / 2
+		drawAvatar(img, card.Avatar, image.Rect(margin, top, margin+avatarSize, top+avatarSize))
+		textX = margin + avatarSize + 56
+	}
+	textWidth := Width - margin - textX
+
+	drawMu.Lock()
+	defer drawMu.Unlock()
+
+	lines := wrap(f.title, card.Title, textWidth, maxTitleRow)
+	lineHeight := titleSize * 5 / 4
+	blockHeight := len(lines) * lineHeight
+	if card.Subtitle != "" {
+		blockHeight += textSize * 2
+	}
+	y := (Height-blockHeight)/2 + titleSize
+
+	for _, line := range lines {
+		drawText(img, f.title, fg, textX, y, line)
+		y += lineHeight
+	}
+	if card.Subtitle != "" {
+		sub := wrap(f.text, card.Subtitle, textWidth, 1)
+		drawText(img, f.text, withAlpha(fg, 0xcc), textX, y+textSize/2, sub[0])
+	}
+
+	bottom := Height - margin/2 - 8
+	if card.Footer != "" {
+		drawText(img, f.footer, fg, margin, bottom, card.Footer)
+	}
+	brandWidth := font.MeasureString(f.footer, brand).Ceil()
+	drawText(img, f.footer, withAlpha(fg, 0x99), Width-margin-brandWidth, bottom, brand)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawText(dst draw.Image, face font.Face, c color.Color, x, y int, text string) {
+	d := font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(text)
+}
+
+// wrap разбивает текст на строки не шире width. Если строк больше max,
+// последняя обрезается с многоточием
+func wrap(face font.Face, text string, width, max int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	fits := func(s string) bool { return font.MeasureString(face, s).Ceil() <= width }
+
+	var lines []string
+	line := ""
+	for _, word := range words {
+		candidate := strings.TrimSpace(line + " " + word)
+		if fits(candidate) {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = word
+	}
+	lines = append(lines, line)
+
+	truncated := len(lines) > max
+	if truncated {
+		lines = lines[:max]
+	}
+	last := lines[len(lines)-1]
+	if truncated || !fits(last) {
+		runes := []rune(last)
+		for len(runes) > 0 && !fits(string(runes)+"…") {
+			runes = runes[:len(runes)-1]
+		}
+		lines[len(lines)-1] = strings.TrimSpace(string(runes)) + "…"
+	}
+	return lines
+}
+
+// drawAvatar вписывает центральный квадрат изображения в круг box
+func drawAvatar(dst draw.Image, avatar image.Image, box image.Rectangle) {
+	b := avatar.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(b.Min).Add(image.Pt((b.Dx()-side)/2, (b.Dy()-side)/2))
+
+	scaled := image.NewRGBA(image.Rect(0, 0, box.Dx(), box.Dy()))
+	draw.Draw(scaled, scaled.Bounds(), image.NewUniform(white), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), avatar, crop, draw.Over, nil)
+
+	draw.DrawMask(dst, box, scaled, image.Point{}, circle{r: box.Dx() / 2}, image.Point{}, draw.Over)
+}
+
+// circle — маска круга радиуса r с центром в (r, r)
+type circle struct {
+	r int
+}
+
+func (c circle) ColorModel() color.Model { return color.AlphaModel }
+
+func (c circle) Bounds() image.Rectangle { return image.Rect(0, 0, 2*c.r, 2*c.r) }
+
+func (c circle) At(x, y int) color.Color {
+	dx, dy := float64(x-c.r)+0.5, float64(y-c.r)+0.5
+	if dx*dx+dy*dy <= float64(c.r*c.r) {
+		return color.Alpha{A: 0xff}
+	}
+	return color.Alpha{}
+}
+
+func withAlpha(c color.RGBA, a uint8) color.NRGBA {
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: a}
+}
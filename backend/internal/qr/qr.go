@@ -0,0 +1,205 @@
+// Package qr строит QR-коды в форматах PNG и SVG с настраиваемыми
+// размером, уровнем коррекции ошибок, цветами и логотипом в центре
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/draw"
+
+	"mvp_multylink/backend/internal/colors"
+)
+
+// Форматы изображения
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// Ограничения размера изображения в пикселях
+const (
+	DefaultSize = 512
+	MinSize     = 128
+	MaxSize     = 2048
+)
+
+const (
+	// minContrast — минимальный контраст цветов, при котором код уверенно
+	// читается камерами телефонов
+	minContrast = 3.0
+	// logoScale — доля стороны кода, которую занимает логотип. При уровне
+	// коррекции Q и выше код читается и с закрытым центром
+	logoScale = 0.22
+)
+
+// ErrInvalidOptions возвращается для неподдерживаемых параметров кода
+var ErrInvalidOptions = errors.New("некорректные параметры QR-кода")
+
+// levels сопоставляет уровни коррекции L, M, Q, H уровням кодировщика
+var levels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// Options задает вид QR-кода
+type Options struct {
+	Size       int
+	Level      string
+	Foreground color.RGBA
+	Background color.RGBA
+	// Logo размещается в центре кода. С логотипом уровень коррекции
+	// повышается минимум до Q
+	Logo image.Image
+}
+
+// DefaultOptions возвращает черный код на белом фоне с уровнем коррекции M
+func DefaultOptions() Options {
+	return Options{
+		Size:       DefaultSize,
+		Level:      "M",
+		Foreground: color.RGBA{A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+}
+
+// Render строит QR-код с содержимым content в указанном формате
+func Render(content, format string, opts Options) ([]byte, error) {
+	code, err := encode(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatPNG:
+		return renderPNG(code, opts)
+	case FormatSVG:
+		return renderSVG(code, opts)
+	default:
+		return nil, fmt.Errorf("%w: неизвестный формат %q", ErrInvalidOptions, format)
+	}
+}
+
+func encode(content string, opts Options) (*qrcode.QRCode, error) {
+	if opts.Size < MinSize || opts.Size > MaxSize {
+		return nil, fmt.Errorf("%w: размер должен быть от %d до %d", ErrInvalidOptions, MinSize, MaxSize)
+	}
+	level, ok := levels[opts.Level]
+	if !ok {
+		return nil, fmt.Errorf("%w: неизвестный уровень коррекции %q", ErrInvalidOptions, opts.Level)
+	}
+	if opts.Logo != nil && level < qrcode.High {
+		level = qrcode.High
+	}
+	if opts.Foreground.A != 0xff || opts.Background.A != 0xff ||
+		colors.Contrast(opts.Foreground, opts.Background) < minContrast ||
+		colors.Luminance(opts.Foreground) > colors.Luminance(opts.Background) {
+		return nil, fmt.Errorf("%w: код должен быть темнее фона и контрастным", ErrInvalidOptions)
+	}
+
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOptions, err)
+	}
+	code.ForegroundColor = opts.Foreground
+	code.BackgroundColor = opts.Background
+	return code, nil
+}
+
+func renderPNG(code *qrcode.QRCode, opts Options) ([]byte, error) {
+	src := code.Image(opts.Size)
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	if opts.Logo != nil {
+		box := logoBox(img.Bounds().Dx())
+		padding := box.Dx() / 10
+		draw.Draw(img, box.Inset(-padding), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(img, fit(box, opts.Logo.Bounds()), opts.Logo, opts.Logo.Bounds(), draw.Over, nil)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderSVG рисует темные модули одним контуром в координатах модулей.
+// Логотип встраивается как PNG в data URI
+func renderSVG(code *qrcode.QRCode, opts Options) ([]byte, error) {
+	bitmap := code.Bitmap()
+	n := len(bitmap)
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, n, n)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, n, n, colors.Hex(opts.Background))
+	fmt.Fprintf(&buf, `<path d="%s" fill="%s"/>`, path.String(), colors.Hex(opts.Foreground))
+
+	if opts.Logo != nil {
+		// Логотип растрируется в размере, соответствующем запрошенному
+		// размеру кода, и масштабируется вместе с ним
+		pixels := logoBox(opts.Size)
+		logo := image.NewRGBA(image.Rect(0, 0, pixels.Dx(), pixels.Dy()))
+		draw.CatmullRom.Scale(logo, fit(logo.Bounds(), opts.Logo.Bounds()), opts.Logo, opts.Logo.Bounds(), draw.Over, nil)
+		var logoPNG bytes.Buffer
+		if err := png.Encode(&logoPNG, logo); err != nil {
+			return nil, err
+		}
+
+		side := float64(n) * logoScale
+		offset := (float64(n) - side) / 2
+		padding := side / 10
+		fmt.Fprintf(&buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`,
+			num(offset-padding), num(offset-padding), num(side+2*padding), num(side+2*padding), colors.Hex(opts.Background))
+		fmt.Fprintf(&buf, `<image x="%s" y="%s" width="%s" height="%s" href="data:image/png;base64,%s"/>`,
+			num(offset), num(offset), num(side), num(side), base64.StdEncoding.EncodeToString(logoPNG.Bytes()))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// logoBox возвращает квадрат для логотипа в центре изображения со стороной size
+func logoBox(size int) image.Rectangle {
+	side := int(float64(size) * logoScale)
+	offset := (size - side) / 2
+	return image.Rect(offset, offset, offset+side, offset+side)
+}
+
+// fit вписывает изображение с границами src в box с сохранением пропорций
+func fit(box, src image.Rectangle) image.Rectangle {
+	w, h := box.Dx(), box.Dy()
+	if src.Dx()*h > src.Dy()*w {
+		h = src.Dy() * w / src.Dx()
+	} else {
+		w = src.Dx() * h / src.Dy()
+	}
+	x := box.Min.X + (box.Dx()-w)/2
+	y := box.Min.Y + (box.Dy()-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// BlocklistRepository определяет интерфейс для работы с записями списка
+// блокировки, добавленными администраторами
+type BlocklistRepository interface {
+	// GetBlockedDomains получает все записи списка блокировки
+	GetBlockedDomains(ctx context.Context) ([]models.BlockedDomain, error)
+
+	// CreateBlockedDomain сохраняет запись и возвращает ее с заполненными ID и временем создания
+	CreateBlockedDomain(ctx context.Context, domain models.BlockedDomain) (models.BlockedDomain, error)
+
+	// DeleteBlockedDomain удаляет запись по ID
+	DeleteBlockedDomain(ctx context.Context, id int64) error
+}
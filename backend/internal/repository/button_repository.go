@@ -1,35 +1,70 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"mvp_multylink/backend/internal/models"
 )
 
-// ButtonRepository определяет интерфейс для работы с кнопками-ссылками в базе данных
+// ButtonRepository определяет интерфейс для работы с кнопками-ссылками в базе данных.
+// Методы чтения и обновления не видят кнопки, перемещенные в корзину
 type ButtonRepository interface {
 	// CreateButton создает новую кнопку-ссылку и возвращает ее ID
-	CreateButton(button models.LinkButton) (int64, error)
+	CreateButton(ctx context.Context, button models.LinkButton) (int64, error)
 
 	// GetButtonByID получает кнопку по ID
-	GetButtonByID(id int64) (models.LinkButton, error)
+	GetButtonByID(ctx context.Context, id int64) (models.LinkButton, error)
 
 	// GetButtonsByMultiLinkID получает все кнопки для мультиссылки
-	GetButtonsByMultiLinkID(multiLinkID int64) ([]models.LinkButton, error)
+	GetButtonsByMultiLinkID(ctx context.Context, multiLinkID int64) ([]models.LinkButton, error)
+
+	// GetAllButtons получает кнопки всех мультиссылок, кроме находящихся в корзине
+	GetAllButtons(ctx context.Context) ([]models.LinkButton, error)
 
-	// GetActiveButtonsByMultiLinkID получает все активные кнопки для мультиссылки
-	GetActiveButtonsByMultiLinkID(multiLinkID int64) ([]models.LinkButton, error)
+	// GetActiveButtonsByMultiLinkID получает все активные кнопки мультиссылки,
+	// окно видимости которых включает момент now
+	GetActiveButtonsByMultiLinkID(ctx context.Context, multiLinkID int64, now time.Time) ([]models.LinkButton, error)
 
 	// UpdateButton обновляет кнопку
-	UpdateButton(button models.LinkButton) error
+	UpdateButton(ctx context.Context, button models.LinkButton) error
 
 	// UpdateButtonPosition обновляет позицию кнопки
-	UpdateButtonPosition(id int64, position int) error
+	UpdateButtonPosition(ctx context.Context, id int64, position int) error
 
-	// DeleteButton удаляет кнопку
-	DeleteButton(id int64) error
+	// DeleteButton окончательно удаляет кнопку, в том числе из корзины
+	DeleteButton(ctx context.Context, id int64) error
 
-	// DeleteButtonsByMultiLinkID удаляет все кнопки для мультиссылки
-	DeleteButtonsByMultiLinkID(multiLinkID int64) error
+	// DeleteButtonsByMultiLinkID окончательно удаляет все кнопки для мультиссылки
+	DeleteButtonsByMultiLinkID(ctx context.Context, multiLinkID int64) error
 
 	// GetButtonsCountByMultiLinkID получает количество кнопок для мультиссылки
-	GetButtonsCountByMultiLinkID(multiLinkID int64) (int, error)
+	GetButtonsCountByMultiLinkID(ctx context.Context, multiLinkID int64) (int, error)
+
+	// SoftDeleteButton перемещает кнопку в корзину
+	SoftDeleteButton(ctx context.Context, id int64, deletedAt time.Time) error
+
+	// SoftDeleteButtonsByMultiLinkID перемещает в корзину все кнопки мультиссылки
+	SoftDeleteButtonsByMultiLinkID(ctx context.Context, multiLinkID int64, deletedAt time.Time) error
+
+	// RestoreButton восстанавливает кнопку из корзины
+	RestoreButton(ctx context.Context, id int64) error
+
+	// RestoreButtonsByMultiLinkID восстанавливает кнопки мультиссылки, перемещенные
+	// в корзину в указанный момент (вместе с самой мультиссылкой)
+	RestoreButtonsByMultiLinkID(ctx context.Context, multiLinkID int64, deletedAt time.Time) error
+
+	// GetDeletedButtonByID получает кнопку из корзины по ID
+	GetDeletedButtonByID(ctx context.Context, id int64) (models.LinkButton, error)
+
+	// GetDeletedButtonsByUserID получает удаленные по отдельности кнопки
+	// неудаленных мультиссылок пользователя
+	GetDeletedButtonsByUserID(ctx context.Context, userID int64) ([]models.LinkButton, error)
+
+	// ConsumeClick атомарно списывает клик с кнопки, если она не исчерпана и не
+	// истекла к моменту now. Возвращает false, если клик не может быть засчитан
+	ConsumeClick(ctx context.Context, id int64, now time.Time) (bool, error)
+
+	// GetButtonIDsDeletedBefore получает ID кнопок, перемещенных в корзину раньше указанного времени
+	GetButtonIDsDeletedBefore(ctx context.Context, before time.Time) ([]int64, error)
 }
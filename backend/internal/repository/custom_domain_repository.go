@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// CustomDomainRepository определяет интерфейс для работы с пользовательскими доменами
+type CustomDomainRepository interface {
+	// GetDomainsByMultiLinkID получает домены мультиссылки
+	GetDomainsByMultiLinkID(ctx context.Context, multiLinkID int64) ([]models.CustomDomain, error)
+
+	// GetDomainByID получает домен по ID
+	GetDomainByID(ctx context.Context, id int64) (models.CustomDomain, error)
+
+	// CreateDomain сохраняет домен и возвращает его с заполненными ID и временем создания.
+	// Повторная привязка домена к той же мультиссылке возвращает ErrDuplicate
+	CreateDomain(ctx context.Context, domain models.CustomDomain) (models.CustomDomain, error)
+
+	// MarkDomainVerified отмечает домен подтвержденным. Если домен уже
+	// подтвержден для другой мультиссылки, возвращается ErrDuplicate
+	MarkDomainVerified(ctx context.Context, id int64, method string, verifiedAt time.Time) error
+
+	// DeleteDomain удаляет домен по ID
+	DeleteDomain(ctx context.Context, id int64) error
+
+	// GetVerifiedSlugByHostname получает slug мультиссылки, к которой
+	// привязан подтвержденный домен. Мультиссылки из корзины не учитываются
+	GetVerifiedSlugByHostname(ctx context.Context, hostname string) (string, error)
+}
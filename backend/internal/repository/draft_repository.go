@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// DraftRepository определяет интерфейс для работы с черновиками мультиссылок
+type DraftRepository interface {
+	// GetDraft получает черновик мультиссылки. Внутри транзакции строка
+	// черновика блокируется до ее завершения
+	GetDraft(ctx context.Context, multiLinkID int64) (models.MultiLinkDraft, error)
+
+	// SaveDraft создает или перезаписывает черновик мультиссылки
+	SaveDraft(ctx context.Context, draft models.MultiLinkDraft) error
+
+	// DeleteDraft удаляет черновик мультиссылки, если он есть
+	DeleteDraft(ctx context.Context, multiLinkID int64) error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// LinkHealthRepository определяет интерфейс для работы с результатами
+// проверки доступности ссылок кнопок
+type LinkHealthRepository interface {
+	// GetCheckableButtons получает активные кнопки-ссылки мультиссылок, не находящихся в корзине
+	GetCheckableButtons(ctx context.Context) ([]models.LinkButton, error)
+
+	// CreateLinkCheck сохраняет результат проверки ссылки
+	CreateLinkCheck(ctx context.Context, check models.LinkCheck) error
+
+	// UpdateButtonHealth обновляет состояние ссылки кнопки. Момент, с которого
+	// ссылка не работает, сохраняется до первой успешной проверки
+	UpdateButtonHealth(ctx context.Context, buttonID int64, health string, checkedAt time.Time) error
+
+	// GetLinkChecksByButtonID получает последние limit проверок ссылки кнопки, начиная с новой
+	GetLinkChecksByButtonID(ctx context.Context, buttonID int64, limit int) ([]models.LinkCheck, error)
+
+	// DeleteLinkChecksBefore удаляет результаты проверок, выполненных раньше указанного времени
+	DeleteLinkChecksBefore(ctx context.Context, before time.Time) error
+
+	// GetBrokenLinksToNotify получает ссылки, которые не работают с момента
+	// brokenBefore или раньше и о которых владелец еще не уведомлен
+	GetBrokenLinksToNotify(ctx context.Context, brokenBefore time.Time) ([]models.BrokenLink, error)
+
+	// MarkBrokenLinksNotified отмечает, что владелец уведомлен о неработающих ссылках
+	MarkBrokenLinksNotified(ctx context.Context, buttonIDs []int64, at time.Time) error
+}
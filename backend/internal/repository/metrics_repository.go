@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"mvp_multylink/backend/internal/models"
@@ -9,29 +10,38 @@ import (
 // MetricsRepository определяет интерфейс для работы с метриками в базе данных
 type MetricsRepository interface {
 	// CreateLinkMetrics создает метрику для кнопки и возвращает ее ID
-	CreateLinkMetrics(metrics models.LinkMetrics) (int64, error)
+	CreateLinkMetrics(ctx context.Context, metrics models.LinkMetrics) (int64, error)
 
 	// GetMetricsByButtonID получает метрику для кнопки
-	GetMetricsByButtonID(buttonID int64) (models.LinkMetrics, error)
+	GetMetricsByButtonID(ctx context.Context, buttonID int64) (models.LinkMetrics, error)
 
 	// UpdateLinkMetrics обновляет метрику кнопки
-	UpdateLinkMetrics(metrics models.LinkMetrics) error
+	UpdateLinkMetrics(ctx context.Context, metrics models.LinkMetrics) error
 
 	// DeleteMetricsByButtonID удаляет метрику для кнопки
-	DeleteMetricsByButtonID(buttonID int64) error
+	DeleteMetricsByButtonID(ctx context.Context, buttonID int64) error
+
+	// DeleteMetricsByMultiLinkID удаляет метрики всех кнопок мультиссылки
+	DeleteMetricsByMultiLinkID(ctx context.Context, multiLinkID int64) error
 
 	// CreateClickEvent создает событие клика и возвращает его ID
-	CreateClickEvent(event models.ClickEvent) (int64, error)
+	CreateClickEvent(ctx context.Context, event models.ClickEvent) (int64, error)
 
 	// GetClickEventsByButtonID получает все события кликов для кнопки
-	GetClickEventsByButtonID(buttonID int64) ([]models.ClickEvent, error)
+	GetClickEventsByButtonID(ctx context.Context, buttonID int64) ([]models.ClickEvent, error)
+
+	// DeleteClickEventsByButtonID удаляет все события кликов для кнопки
+	DeleteClickEventsByButtonID(ctx context.Context, buttonID int64) error
+
+	// DeleteClickEventsByMultiLinkID удаляет события кликов всех кнопок мультиссылки
+	DeleteClickEventsByMultiLinkID(ctx context.Context, multiLinkID int64) error
 
 	// GetClickEventsByButtonIDsAndDateRange получает события кликов для кнопок за указанный период
-	GetClickEventsByButtonIDsAndDateRange(buttonIDs []int64, startDate, endDate time.Time) ([]models.ClickEvent, error)
+	GetClickEventsByButtonIDsAndDateRange(ctx context.Context, buttonIDs []int64, startDate, endDate time.Time) ([]models.ClickEvent, error)
 
 	// GetUTMSourceStatsByButtonIDs получает статистику по источникам трафика (utm_source) для кнопок
-	GetUTMSourceStatsByButtonIDs(buttonIDs []int64) (map[string]int, error)
+	GetUTMSourceStatsByButtonIDs(ctx context.Context, buttonIDs []int64) (map[string]int, error)
 
 	// GetUTMMediumStatsByButtonIDs получает статистику по каналам трафика (utm_medium) для кнопок
-	GetUTMMediumStatsByButtonIDs(buttonIDs []int64) (map[string]int, error)
+	GetUTMMediumStatsByButtonIDs(ctx context.Context, buttonIDs []int64) (map[string]int, error)
 }
@@ -1,29 +1,62 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"mvp_multylink/backend/internal/models"
 )
 
-// MultiLinkRepository определяет интерфейс для работы с мультиссылками в базе данных
+// MultiLinkRepository определяет интерфейс для работы с мультиссылками в базе данных.
+// Методы чтения и обновления не видят мультиссылки, перемещенные в корзину
 type MultiLinkRepository interface {
 	// CreateMultiLink создает новую мультиссылку и возвращает ее ID
-	CreateMultiLink(multiLink models.MultiLink) (int64, error)
+	CreateMultiLink(ctx context.Context, multiLink models.MultiLink) (int64, error)
 
 	// GetMultiLinkByID получает мультиссылку по ID
-	GetMultiLinkByID(id int64) (models.MultiLink, error)
+	GetMultiLinkByID(ctx context.Context, id int64) (models.MultiLink, error)
+
+	// LockMultiLink блокирует строку мультиссылки до завершения транзакции.
+	// Изменения черновика и опубликованного состояния выполняются по очереди
+	LockMultiLink(ctx context.Context, id int64) error
 
 	// GetMultiLinkBySlug получает мультиссылку по slug
-	GetMultiLinkBySlug(slug string) (models.MultiLink, error)
+	GetMultiLinkBySlug(ctx context.Context, slug string) (models.MultiLink, error)
 
 	// GetMultiLinksByUserID получает все мультиссылки пользователя
-	GetMultiLinksByUserID(userID int64) ([]models.MultiLink, error)
+	GetMultiLinksByUserID(ctx context.Context, userID int64) ([]models.MultiLink, error)
 
 	// UpdateMultiLink обновляет мультиссылку
-	UpdateMultiLink(multiLink models.MultiLink) error
+	UpdateMultiLink(ctx context.Context, multiLink models.MultiLink) error
+
+	// SetMultiLinkPassword задает хэш пароля мультиссылки. Пустой хэш снимает защиту
+	SetMultiLinkPassword(ctx context.Context, id int64, passwordHash string) error
+
+	// SetForcedSensitiveReason задает отметку чувствительного содержимого,
+	// установленную администратором. Пустая причина снимает отметку
+	SetForcedSensitiveReason(ctx context.Context, id int64, reason string) error
+
+	// SetMultiLinkTheme задает пресет и переопределения оформления мультиссылки
+	SetMultiLinkTheme(ctx context.Context, id int64, preset string, overrides models.ThemeOverrides) error
 
-	// DeleteMultiLink удаляет мультиссылку
-	DeleteMultiLink(id int64) error
+	// DeleteMultiLink окончательно удаляет мультиссылку, в том числе из корзины
+	DeleteMultiLink(ctx context.Context, id int64) error
 
 	// CheckSlugExists проверяет существование мультиссылки с указанным slug
-	CheckSlugExists(slug string) (bool, error)
+	CheckSlugExists(ctx context.Context, slug string) (bool, error)
+
+	// SoftDeleteMultiLink перемещает мультиссылку в корзину
+	SoftDeleteMultiLink(ctx context.Context, id int64, deletedAt time.Time) error
+
+	// RestoreMultiLink восстанавливает мультиссылку из корзины
+	RestoreMultiLink(ctx context.Context, id int64) error
+
+	// GetDeletedMultiLinkByID получает мультиссылку из корзины по ID
+	GetDeletedMultiLinkByID(ctx context.Context, id int64) (models.MultiLink, error)
+
+	// GetDeletedMultiLinksByUserID получает мультиссылки пользователя из корзины
+	GetDeletedMultiLinksByUserID(ctx context.Context, userID int64) ([]models.MultiLink, error)
+
+	// GetMultiLinkIDsDeletedBefore получает ID мультиссылок, перемещенных в корзину раньше указанного времени
+	GetMultiLinkIDsDeletedBefore(ctx context.Context, before time.Time) ([]int64, error)
 }
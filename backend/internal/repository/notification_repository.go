@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// NotificationRepository определяет интерфейс для работы с уведомлениями пользователей
+type NotificationRepository interface {
+	// CreateNotification сохраняет уведомление и возвращает его ID
+	CreateNotification(ctx context.Context, notification models.Notification) (int64, error)
+
+	// GetNotificationsByUserID получает последние limit уведомлений пользователя, начиная с новых
+	GetNotificationsByUserID(ctx context.Context, userID int64, limit int) ([]models.Notification, error)
+
+	// MarkNotificationRead отмечает уведомление пользователя прочитанным
+	MarkNotificationRead(ctx context.Context, id, userID int64, at time.Time) error
+}
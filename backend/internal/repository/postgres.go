@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"mvp_multylink/backend/internal/logging"
+)
+
+// ErrNotFound возвращается, когда запись не найдена
+var ErrNotFound = errors.New("запись не найдена")
+
+// ErrDuplicate возвращается при нарушении ограничения уникальности
+var ErrDuplicate = errors.New("запись уже существует")
+
+// uniqueViolationCode — код ошибки PostgreSQL при нарушении уникальности
+const uniqueViolationCode = "23505"
+
+// postgresRepository содержит общие для PostgreSQL-репозиториев зависимости
+type postgresRepository struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// conn возвращает транзакцию из контекста, если репозиторий вызван внутри
+// UnitOfWork, иначе пул соединений. Запросы пишутся в логгер из контекста,
+// поэтому попадают в журнал с ID запроса
+func (r postgresRepository) conn(ctx context.Context) DBTX {
+	var db DBTX = r.db
+	if tx, ok := txFromContext(ctx); ok {
+		db = tx
+	}
+	return loggedConn{db: db, logger: logging.FromContext(ctx)}
+}
+
+// loggedConn пишет в журнал время выполнения запросов и их ошибки. Аргументы
+// запросов не записываются: среди них бывают хэши паролей и токены
+type loggedConn struct {
+	db     DBTX
+	logger *slog.Logger
+}
+
+func (c loggedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := c.db.ExecContext(ctx, query, args...)
+	c.log(ctx, query, start, err)
+	return result, err
+}
+
+func (c loggedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	c.log(ctx, query, start, err)
+	return rows, err
+}
+
+func (c loggedConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := c.db.QueryRowContext(ctx, query, args...)
+	// Отсутствие строки проявится только при Scan, Err возвращает ошибки самого запроса
+	c.log(ctx, query, start, row.Err())
+	return row
+}
+
+// log записывает запрос. Нарушение уникальности и отмена запроса клиентом —
+// ожидаемые исходы, они пишутся на уровне debug вместе с успешными запросами
+func (c loggedConn) log(ctx context.Context, query string, start time.Time, err error) {
+	level := slog.LevelDebug
+	if err != nil && !isUniqueViolation(err) && !errors.Is(err, context.Canceled) {
+		level = slog.LevelError
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("query", strings.Join(strings.Fields(query), " ")),
+		slog.Duration("duration", time.Since(start)),
+	}
+	message := "database query"
+	if err != nil {
+		message = "database query failed"
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	c.logger.LogAttrs(ctx, level, message, attrs...)
+}
+
+// withTimeout ограничивает время выполнения запроса. Если у контекста уже
+// есть более ранний дедлайн (например, от таймаута HTTP-запроса), действует он
+func (r postgresRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
+
+// notFound преобразует sql.ErrNoRows в ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// duplicate преобразует нарушение ограничения уникальности в ErrDuplicate
+func duplicate(err error) error {
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// isUniqueViolation сообщает, является ли err нарушением ограничения уникальности
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode
+}
+
+// checkAffected возвращает ErrNotFound, если запрос не затронул ни одной строки
+func checkAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// qualify добавляет псевдоним таблицы к каждому столбцу списка
+func qualify(alias, columns string) string {
+	parts := strings.Split(columns, ",")
+	for i, column := range parts {
+		parts[i] = alias + "." + strings.TrimSpace(column)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// nullTimePtr преобразует sql.NullTime в *time.Time
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	value := t.Time
+	return &value
+}
+
+// nullIntPtr преобразует sql.NullInt64 в *int
+func nullIntPtr(i sql.NullInt64) *int {
+	if !i.Valid {
+		return nil
+	}
+	value := int(i.Int64)
+	return &value
+}
+
+// queryIDs выполняет запрос, возвращающий один столбец с ID
+func (r postgresRepository) queryIDs(ctx context.Context, query string, args ...any) ([]int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// exec выполняет запрос на изменение. Если requireAffected, отсутствие
+// затронутых строк возвращается как ErrNotFound
+func (r postgresRepository) exec(ctx context.Context, requireAffected bool, query string, args ...any) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if requireAffected {
+		return checkAffected(result)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// PostgresBlocklistRepository реализует BlocklistRepository для PostgreSQL
+type PostgresBlocklistRepository struct {
+	postgresRepository
+}
+
+var _ BlocklistRepository = (*PostgresBlocklistRepository)(nil)
+
+// NewPostgresBlocklistRepository создает новый экземпляр PostgresBlocklistRepository
+func NewPostgresBlocklistRepository(db *sql.DB, queryTimeout time.Duration) *PostgresBlocklistRepository {
+	return &PostgresBlocklistRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+// GetBlockedDomains получает все записи списка блокировки
+func (r *PostgresBlocklistRepository) GetBlockedDomains(ctx context.Context) ([]models.BlockedDomain, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		`SELECT id, pattern, reason, created_by, created_at FROM blocked_domains ORDER BY pattern`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := make([]models.BlockedDomain, 0)
+	for rows.Next() {
+		var d models.BlockedDomain
+		var createdBy sql.NullInt64
+		if err := rows.Scan(&d.ID, &d.Pattern, &d.Reason, &createdBy, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		if createdBy.Valid {
+			d.CreatedBy = &createdBy.Int64
+		}
+		domains = append(domains, d)
+	}
+	return domains, rows.Err()
+}
+
+// CreateBlockedDomain сохраняет запись. Повторное добавление того же
+// шаблона обновляет причину блокировки
+func (r *PostgresBlocklistRepository) CreateBlockedDomain(ctx context.Context, domain models.BlockedDomain) (models.BlockedDomain, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`INSERT INTO blocked_domains (pattern, reason, created_by) VALUES ($1, $2, $3)
+		 ON CONFLICT (pattern) DO UPDATE SET reason = EXCLUDED.reason
+		 RETURNING id, created_at`,
+		domain.Pattern, domain.Reason, domain.CreatedBy,
+	).Scan(&domain.ID, &domain.CreatedAt)
+	return domain, err
+}
+
+// DeleteBlockedDomain удаляет запись по ID
+func (r *PostgresBlocklistRepository) DeleteBlockedDomain(ctx context.Context, id int64) error {
+	return r.exec(ctx, true, `DELETE FROM blocked_domains WHERE id = $1`, id)
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+const buttonColumns = `id, multilink_id, title, url, icon, color, position, is_active, created_at, updated_at, deleted_at, visible_from, visible_until, timezone,
+	max_clicks, remaining_clicks, expires_at, fallback_url, exhausted_action, kind,
+	health, health_checked_at, broken_since, sensitive, sensitive_reason`
+
+// PostgresButtonRepository реализует ButtonRepository для PostgreSQL
+type PostgresButtonRepository struct {
+	postgresRepository
+}
+
+var _ ButtonRepository = (*PostgresButtonRepository)(nil)
+
+// NewPostgresButtonRepository создает новый экземпляр PostgresButtonRepository
+func NewPostgresButtonRepository(db *sql.DB, queryTimeout time.Duration) *PostgresButtonRepository {
+	return &PostgresButtonRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+func scanButton(row interface{ Scan(...any) error }) (models.LinkButton, error) {
+	var b models.LinkButton
+	var deletedAt, visibleFrom, visibleUntil, expiresAt, healthCheckedAt, brokenSince sql.NullTime
+	var maxClicks, remainingClicks sql.NullInt64
+	err := row.Scan(&b.ID, &b.MultiLinkID, &b.Title, &b.URL, &b.Icon, &b.Color, &b.Position, &b.IsActive,
+		&b.CreatedAt, &b.UpdatedAt, &deletedAt, &visibleFrom, &visibleUntil, &b.Timezone,
+		&maxClicks, &remainingClicks, &expiresAt, &b.FallbackURL, &b.ExhaustedAction, &b.Kind,
+		&b.Health, &healthCheckedAt, &brokenSince, &b.Sensitive, &b.SensitiveReason)
+	b.DeletedAt = nullTimePtr(deletedAt)
+	b.VisibleFrom = nullTimePtr(visibleFrom)
+	b.VisibleUntil = nullTimePtr(visibleUntil)
+	b.ExpiresAt = nullTimePtr(expiresAt)
+	b.MaxClicks = nullIntPtr(maxClicks)
+	b.RemainingClicks = nullIntPtr(remainingClicks)
+	b.HealthCheckedAt = nullTimePtr(healthCheckedAt)
+	b.BrokenSince = nullTimePtr(brokenSince)
+	return b, err
+}
+
+func (r *PostgresButtonRepository) getButton(ctx context.Context, query string, args ...any) (models.LinkButton, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	b, err := scanButton(r.conn(ctx).QueryRowContext(ctx, query, args...))
+	return b, notFound(err)
+}
+
+func (r *PostgresButtonRepository) queryButtons(ctx context.Context, query string, args ...any) ([]models.LinkButton, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buttons := make([]models.LinkButton, 0)
+	for rows.Next() {
+		b, err := scanButton(rows)
+		if err != nil {
+			return nil, err
+		}
+		buttons = append(buttons, b)
+	}
+	return buttons, rows.Err()
+}
+
+// CreateButton создает новую кнопку-ссылку и возвращает ее ID
+func (r *PostgresButtonRepository) CreateButton(ctx context.Context, button models.LinkButton) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var id int64
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`INSERT INTO link_buttons (multilink_id, title, url, icon, color, position, is_active, created_at, updated_at,
+		 visible_from, visible_until, timezone, max_clicks, remaining_clicks, expires_at, fallback_url, exhausted_action, kind,
+		 sensitive, sensitive_reason)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13, $14, $15, $16, $17, $18, $19) RETURNING id`,
+		button.MultiLinkID, button.Title, button.URL, button.Icon, button.Color, button.Position, button.IsActive,
+		button.CreatedAt, button.UpdatedAt, button.VisibleFrom, button.VisibleUntil, button.Timezone,
+		button.MaxClicks, button.ExpiresAt, button.FallbackURL, exhaustedAction(button), buttonKind(button),
+		button.Sensitive, button.SensitiveReason,
+	).Scan(&id)
+	return id, err
+}
+
+// GetButtonByID получает кнопку по ID
+func (r *PostgresButtonRepository) GetButtonByID(ctx context.Context, id int64) (models.LinkButton, error) {
+	return r.getButton(ctx,
+		`SELECT `+buttonColumns+` FROM link_buttons WHERE id = $1 AND deleted_at IS NULL`, id)
+}
+
+// GetButtonsByMultiLinkID получает все кнопки для мультиссылки
+func (r *PostgresButtonRepository) GetButtonsByMultiLinkID(ctx context.Context, multiLinkID int64) ([]models.LinkButton, error) {
+	return r.queryButtons(ctx,
+		`SELECT `+buttonColumns+` FROM link_buttons
+		 WHERE multilink_id = $1 AND deleted_at IS NULL ORDER BY position, id`, multiLinkID)
+}
+
+// GetAllButtons получает кнопки всех мультиссылок, кроме находящихся в корзине
+func (r *PostgresButtonRepository) GetAllButtons(ctx context.Context) ([]models.LinkButton, error) {
+	return r.queryButtons(ctx,
+		`SELECT `+buttonColumns+` FROM link_buttons
+		 WHERE deleted_at IS NULL ORDER BY multilink_id, position, id`)
+}
+
+// GetActiveButtonsByMultiLinkID получает все активные кнопки мультиссылки, видимые
+// в момент now. Исчерпанные кнопки возвращаются, только если они ведут на запасной URL
+func (r *PostgresButtonRepository) GetActiveButtonsByMultiLinkID(ctx context.Context, multiLinkID int64, now time.Time) ([]models.LinkButton, error) {
+	return r.queryButtons(ctx,
+		`SELECT `+buttonColumns+` FROM link_buttons
+		 WHERE multilink_id = $1 AND is_active AND deleted_at IS NULL
+		   AND (visible_from IS NULL OR visible_from <= $2)
+		   AND (visible_until IS NULL OR visible_until > $2)
+		   AND (exhausted_action = 'fallback'
+		        OR ((remaining_clicks IS NULL OR remaining_clicks > 0) AND (expires_at IS NULL OR expires_at > $2)))
+		 ORDER BY position, id`, multiLinkID, now)
+}
+
+// UpdateButton обновляет кнопку. Остаток кликов не перезаписывается: при
+// изменении max_clicks он пересчитывается с учетом уже совершенных кликов.
+// При смене ссылки результаты проверки доступности сбрасываются
+func (r *PostgresButtonRepository) UpdateButton(ctx context.Context, button models.LinkButton) error {
+	return r.exec(ctx, true,
+		`UPDATE link_buttons SET title = $2, url = $3, icon = $4, color = $5, position = $6, is_active = $7, updated_at = $8,
+		 visible_from = $9, visible_until = $10, timezone = $11,
+		 remaining_clicks = CASE
+		     WHEN $12::INTEGER IS NULL THEN NULL
+		     WHEN max_clicks IS NULL THEN $12
+		     ELSE GREATEST($12 - (max_clicks - remaining_clicks), 0)
+		 END,
+		 max_clicks = $12, expires_at = $13, fallback_url = $14, exhausted_action = $15, kind = $16,
+		 sensitive = $17, sensitive_reason = $18,
+		 health = CASE WHEN url = $3 THEN health ELSE 'unknown' END,
+		 health_checked_at = CASE WHEN url = $3 THEN health_checked_at END,
+		 broken_since = CASE WHEN url = $3 THEN broken_since END,
+		 broken_notified_at = CASE WHEN url = $3 THEN broken_notified_at END
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		button.ID, button.Title, button.URL, button.Icon, button.Color, button.Position, button.IsActive, button.UpdatedAt,
+		button.VisibleFrom, button.VisibleUntil, button.Timezone,
+		button.MaxClicks, button.ExpiresAt, button.FallbackURL, exhaustedAction(button), buttonKind(button),
+		button.Sensitive, button.SensitiveReason,
+	)
+}
+
+// UpdateButtonPosition обновляет позицию кнопки
+func (r *PostgresButtonRepository) UpdateButtonPosition(ctx context.Context, id int64, position int) error {
+	return r.exec(ctx, true,
+		`UPDATE link_buttons SET position = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id, position)
+}
+
+// DeleteButton окончательно удаляет кнопку, в том числе из корзины
+func (r *PostgresButtonRepository) DeleteButton(ctx context.Context, id int64) error {
+	return r.exec(ctx, true, `DELETE FROM link_buttons WHERE id = $1`, id)
+}
+
+// DeleteButtonsByMultiLinkID окончательно удаляет все кнопки для мультиссылки
+func (r *PostgresButtonRepository) DeleteButtonsByMultiLinkID(ctx context.Context, multiLinkID int64) error {
+	return r.exec(ctx, false, `DELETE FROM link_buttons WHERE multilink_id = $1`, multiLinkID)
+}
+
+// GetButtonsCountByMultiLinkID получает количество кнопок для мультиссылки
+func (r *PostgresButtonRepository) GetButtonsCountByMultiLinkID(ctx context.Context, multiLinkID int64) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM link_buttons WHERE multilink_id = $1 AND deleted_at IS NULL`, multiLinkID).Scan(&count)
+	return count, err
+}
+
+// SoftDeleteButton перемещает кнопку в корзину
+func (r *PostgresButtonRepository) SoftDeleteButton(ctx context.Context, id int64, deletedAt time.Time) error {
+	return r.exec(ctx, true,
+		`UPDATE link_buttons SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`, id, deletedAt)
+}
+
+// SoftDeleteButtonsByMultiLinkID перемещает в корзину все кнопки мультиссылки
+func (r *PostgresButtonRepository) SoftDeleteButtonsByMultiLinkID(ctx context.Context, multiLinkID int64, deletedAt time.Time) error {
+	return r.exec(ctx, false,
+		`UPDATE link_buttons SET deleted_at = $2 WHERE multilink_id = $1 AND deleted_at IS NULL`, multiLinkID, deletedAt)
+}
+
+// RestoreButton восстанавливает кнопку из корзины
+func (r *PostgresButtonRepository) RestoreButton(ctx context.Context, id int64) error {
+	return r.exec(ctx, true,
+		`UPDATE link_buttons SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+}
+
+// RestoreButtonsByMultiLinkID восстанавливает кнопки мультиссылки, перемещенные в корзину в указанный момент
+func (r *PostgresButtonRepository) RestoreButtonsByMultiLinkID(ctx context.Context, multiLinkID int64, deletedAt time.Time) error {
+	return r.exec(ctx, false,
+		`UPDATE link_buttons SET deleted_at = NULL, updated_at = NOW() WHERE multilink_id = $1 AND deleted_at = $2`,
+		multiLinkID, deletedAt)
+}
+
+// GetDeletedButtonByID получает кнопку из корзины по ID
+func (r *PostgresButtonRepository) GetDeletedButtonByID(ctx context.Context, id int64) (models.LinkButton, error) {
+	return r.getButton(ctx,
+		`SELECT `+buttonColumns+` FROM link_buttons WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+}
+
+// GetDeletedButtonsByUserID получает удаленные по отдельности кнопки неудаленных мультиссылок пользователя
+func (r *PostgresButtonRepository) GetDeletedButtonsByUserID(ctx context.Context, userID int64) ([]models.LinkButton, error) {
+	return r.queryButtons(ctx,
+		`SELECT `+qualify("b", buttonColumns)+` FROM link_buttons b
+		 JOIN multilinks m ON m.id = b.multilink_id
+		 WHERE m.user_id = $1 AND m.deleted_at IS NULL AND b.deleted_at IS NOT NULL
+		 ORDER BY b.deleted_at DESC, b.id DESC`, userID)
+}
+
+// GetButtonIDsDeletedBefore получает ID кнопок, перемещенных в корзину раньше указанного времени
+func (r *PostgresButtonRepository) GetButtonIDsDeletedBefore(ctx context.Context, before time.Time) ([]int64, error) {
+	return r.queryIDs(ctx,
+		`SELECT id FROM link_buttons WHERE deleted_at IS NOT NULL AND deleted_at < $1 ORDER BY id`, before)
+}
+
+// ConsumeClick атомарно списывает один клик с кнопки. Условие проверяется
+// в том же UPDATE, поэтому из нескольких одновременных запросов за последний
+// клик успешен только один. Для кнопок без ограничения проверяется только срок
+func (r *PostgresButtonRepository) ConsumeClick(ctx context.Context, id int64, now time.Time) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var consumed bool
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`UPDATE link_buttons SET remaining_clicks = remaining_clicks - 1
+		 WHERE id = $1 AND deleted_at IS NULL
+		   AND (remaining_clicks IS NULL OR remaining_clicks > 0)
+		   AND (expires_at IS NULL OR expires_at > $2)
+		 RETURNING TRUE`, id, now).Scan(&consumed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return consumed, err
+}
+
+// exhaustedAction возвращает действие для исчерпанной кнопки, по умолчанию скрытие
+func exhaustedAction(button models.LinkButton) string {
+	if button.ExhaustedAction == "" {
+		return models.ExhaustedActionHide
+	}
+	return button.ExhaustedAction
+}
+
+// buttonKind возвращает тип кнопки, по умолчанию обычная ссылка
+func buttonKind(button models.LinkButton) string {
+	if button.Kind == "" {
+		return models.ButtonKindURL
+	}
+	return button.Kind
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+const customDomainColumns = `id, multilink_id, hostname, token, verified_at, method, created_at`
+
+// PostgresCustomDomainRepository реализует CustomDomainRepository для PostgreSQL
+type PostgresCustomDomainRepository struct {
+	postgresRepository
+}
+
+var _ CustomDomainRepository = (*PostgresCustomDomainRepository)(nil)
+
+// NewPostgresCustomDomainRepository создает новый экземпляр PostgresCustomDomainRepository
+func NewPostgresCustomDomainRepository(db *sql.DB, queryTimeout time.Duration) *PostgresCustomDomainRepository {
+	return &PostgresCustomDomainRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+func scanCustomDomain(row interface{ Scan(...any) error }) (models.CustomDomain, error) {
+	var d models.CustomDomain
+	var verifiedAt sql.NullTime
+	err := row.Scan(&d.ID, &d.MultiLinkID, &d.Hostname, &d.Token, &verifiedAt, &d.Method, &d.CreatedAt)
+	d.VerifiedAt = nullTimePtr(verifiedAt)
+	return d, err
+}
+
+// GetDomainsByMultiLinkID получает домены мультиссылки
+func (r *PostgresCustomDomainRepository) GetDomainsByMultiLinkID(ctx context.Context, multiLinkID int64) ([]models.CustomDomain, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		`SELECT `+customDomainColumns+` FROM custom_domains WHERE multilink_id = $1 ORDER BY hostname`, multiLinkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := make([]models.CustomDomain, 0)
+	for rows.Next() {
+		d, err := scanCustomDomain(rows)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, d)
+	}
+	return domains, rows.Err()
+}
+
+// GetDomainByID получает домен по ID
+func (r *PostgresCustomDomainRepository) GetDomainByID(ctx context.Context, id int64) (models.CustomDomain, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	d, err := scanCustomDomain(r.conn(ctx).QueryRowContext(ctx,
+		`SELECT `+customDomainColumns+` FROM custom_domains WHERE id = $1`, id))
+	return d, notFound(err)
+}
+
+// CreateDomain сохраняет домен и возвращает его с заполненными ID и временем создания
+func (r *PostgresCustomDomainRepository) CreateDomain(ctx context.Context, domain models.CustomDomain) (models.CustomDomain, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`INSERT INTO custom_domains (multilink_id, hostname, token) VALUES ($1, $2, $3)
+		 RETURNING id, created_at`,
+		domain.MultiLinkID, domain.Hostname, domain.Token,
+	).Scan(&domain.ID, &domain.CreatedAt)
+	return domain, duplicate(err)
+}
+
+// MarkDomainVerified отмечает домен подтвержденным
+func (r *PostgresCustomDomainRepository) MarkDomainVerified(ctx context.Context, id int64, method string, verifiedAt time.Time) error {
+	err := r.exec(ctx, true,
+		`UPDATE custom_domains SET verified_at = $2, method = $3 WHERE id = $1`, id, verifiedAt, method)
+	return duplicate(err)
+}
+
+// DeleteDomain удаляет домен по ID
+func (r *PostgresCustomDomainRepository) DeleteDomain(ctx context.Context, id int64) error {
+	return r.exec(ctx, true, `DELETE FROM custom_domains WHERE id = $1`, id)
+}
+
+// GetVerifiedSlugByHostname получает slug мультиссылки, к которой привязан подтвержденный домен
+func (r *PostgresCustomDomainRepository) GetVerifiedSlugByHostname(ctx context.Context, hostname string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var slug string
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT m.slug FROM custom_domains d
+		 JOIN multilinks m ON m.id = d.multilink_id AND m.deleted_at IS NULL
+		 WHERE d.hostname = $1 AND d.verified_at IS NOT NULL`, hostname).Scan(&slug)
+	return slug, notFound(err)
+}
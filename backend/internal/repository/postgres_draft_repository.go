@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// PostgresDraftRepository реализует DraftRepository для PostgreSQL.
+// Снимок черновика хранится в столбце JSONB
+type PostgresDraftRepository struct {
+	postgresRepository
+}
+
+var _ DraftRepository = (*PostgresDraftRepository)(nil)
+
+// NewPostgresDraftRepository создает новый экземпляр PostgresDraftRepository
+func NewPostgresDraftRepository(db *sql.DB, queryTimeout time.Duration) *PostgresDraftRepository {
+	return &PostgresDraftRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+// GetDraft получает черновик мультиссылки
+func (r *PostgresDraftRepository) GetDraft(ctx context.Context, multiLinkID int64) (models.MultiLinkDraft, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var draft models.MultiLinkDraft
+	var snapshot []byte
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT multilink_id, snapshot, created_at, updated_at FROM multilink_drafts
+		 WHERE multilink_id = $1 FOR UPDATE`, multiLinkID,
+	).Scan(&draft.MultiLinkID, &snapshot, &draft.CreatedAt, &draft.UpdatedAt)
+	if err != nil {
+		return draft, notFound(err)
+	}
+	return draft, json.Unmarshal(snapshot, &draft.Snapshot)
+}
+
+// SaveDraft создает или перезаписывает черновик мультиссылки
+func (r *PostgresDraftRepository) SaveDraft(ctx context.Context, draft models.MultiLinkDraft) error {
+	snapshot, err := json.Marshal(draft.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	return r.exec(ctx, false,
+		`INSERT INTO multilink_drafts (multilink_id, snapshot, created_at, updated_at)
+		 VALUES ($1, $2, $3, $3)
+		 ON CONFLICT (multilink_id) DO UPDATE SET snapshot = EXCLUDED.snapshot, updated_at = EXCLUDED.updated_at`,
+		draft.MultiLinkID, snapshot, draft.UpdatedAt)
+}
+
+// DeleteDraft удаляет черновик мультиссылки, если он есть
+func (r *PostgresDraftRepository) DeleteDraft(ctx context.Context, multiLinkID int64) error {
+	return r.exec(ctx, false, `DELETE FROM multilink_drafts WHERE multilink_id = $1`, multiLinkID)
+}
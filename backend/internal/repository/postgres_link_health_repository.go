@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+const linkCheckColumns = `id, link_button_id, url, status_code, final_url, redirects, error, health, duration_ms, checked_at`
+
+// PostgresLinkHealthRepository реализует LinkHealthRepository для PostgreSQL
+type PostgresLinkHealthRepository struct {
+	postgresRepository
+}
+
+var _ LinkHealthRepository = (*PostgresLinkHealthRepository)(nil)
+
+// NewPostgresLinkHealthRepository создает новый экземпляр PostgresLinkHealthRepository
+func NewPostgresLinkHealthRepository(db *sql.DB, queryTimeout time.Duration) *PostgresLinkHealthRepository {
+	return &PostgresLinkHealthRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+// GetCheckableButtons получает активные кнопки-ссылки мультиссылок, не находящихся в корзине
+func (r *PostgresLinkHealthRepository) GetCheckableButtons(ctx context.Context) ([]models.LinkButton, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		`SELECT `+qualify("b", buttonColumns)+` FROM link_buttons b
+		 JOIN multilinks m ON m.id = b.multilink_id
+		 WHERE b.deleted_at IS NULL AND m.deleted_at IS NULL AND b.is_active AND b.kind = $1
+		 ORDER BY b.id`, models.ButtonKindURL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buttons := make([]models.LinkButton, 0)
+	for rows.Next() {
+		b, err := scanButton(rows)
+		if err != nil {
+			return nil, err
+		}
+		buttons = append(buttons, b)
+	}
+	return buttons, rows.Err()
+}
+
+// CreateLinkCheck сохраняет результат проверки ссылки
+func (r *PostgresLinkHealthRepository) CreateLinkCheck(ctx context.Context, check models.LinkCheck) error {
+	redirects, err := json.Marshal(check.Redirects)
+	if err != nil {
+		return err
+	}
+
+	return r.exec(ctx, false,
+		`INSERT INTO link_checks (link_button_id, url, status_code, final_url, redirects, error, health, duration_ms, checked_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		check.LinkButtonID, check.URL, check.StatusCode, check.FinalURL, redirects, check.Error, check.Health,
+		check.DurationMs, check.CheckedAt)
+}
+
+// UpdateButtonHealth обновляет состояние ссылки кнопки. Момент, с которого
+// ссылка не работает, сохраняется до первой успешной проверки
+func (r *PostgresLinkHealthRepository) UpdateButtonHealth(ctx context.Context, buttonID int64, health string, checkedAt time.Time) error {
+	return r.exec(ctx, false,
+		`UPDATE link_buttons SET health = $2, health_checked_at = $3,
+		 broken_since = CASE WHEN $2 = 'broken' THEN COALESCE(broken_since, $3) END,
+		 broken_notified_at = CASE WHEN $2 = 'broken' THEN broken_notified_at END
+		 WHERE id = $1`,
+		buttonID, health, checkedAt)
+}
+
+// GetLinkChecksByButtonID получает последние limit проверок ссылки кнопки, начиная с новой
+func (r *PostgresLinkHealthRepository) GetLinkChecksByButtonID(ctx context.Context, buttonID int64, limit int) ([]models.LinkCheck, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		`SELECT `+linkCheckColumns+` FROM link_checks
+		 WHERE link_button_id = $1 ORDER BY checked_at DESC, id DESC LIMIT $2`, buttonID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checks := make([]models.LinkCheck, 0)
+	for rows.Next() {
+		var check models.LinkCheck
+		var redirects []byte
+		err := rows.Scan(&check.ID, &check.LinkButtonID, &check.URL, &check.StatusCode, &check.FinalURL, &redirects,
+			&check.Error, &check.Health, &check.DurationMs, &check.CheckedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(redirects, &check.Redirects); err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, rows.Err()
+}
+
+// DeleteLinkChecksBefore удаляет результаты проверок, выполненных раньше указанного времени
+func (r *PostgresLinkHealthRepository) DeleteLinkChecksBefore(ctx context.Context, before time.Time) error {
+	return r.exec(ctx, false, `DELETE FROM link_checks WHERE checked_at < $1`, before)
+}
+
+// GetBrokenLinksToNotify получает ссылки, которые не работают с момента
+// brokenBefore или раньше и о которых владелец еще не уведомлен
+func (r *PostgresLinkHealthRepository) GetBrokenLinksToNotify(ctx context.Context, brokenBefore time.Time) ([]models.BrokenLink, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		`SELECT b.id, b.multilink_id, m.user_id, m.title, b.title, b.url, b.broken_since
+		 FROM link_buttons b
+		 JOIN multilinks m ON m.id = b.multilink_id
+		 WHERE b.health = 'broken' AND b.broken_since <= $1 AND b.broken_notified_at IS NULL
+		   AND b.deleted_at IS NULL AND m.deleted_at IS NULL
+		 ORDER BY m.user_id, b.multilink_id, b.position`, brokenBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]models.BrokenLink, 0)
+	for rows.Next() {
+		var link models.BrokenLink
+		err := rows.Scan(&link.ButtonID, &link.MultiLinkID, &link.UserID, &link.MultiLinkTitle, &link.Title, &link.URL,
+			&link.BrokenSince)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// MarkBrokenLinksNotified отмечает, что владелец уведомлен о неработающих ссылках
+func (r *PostgresLinkHealthRepository) MarkBrokenLinksNotified(ctx context.Context, buttonIDs []int64, at time.Time) error {
+	return r.exec(ctx, false,
+		`UPDATE link_buttons SET broken_notified_at = $2 WHERE id = ANY($1)`, pq.Array(buttonIDs), at)
+}
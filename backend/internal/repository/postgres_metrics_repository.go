@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+const clickEventColumns = `id, link_button_id, ip, user_agent, referer, utm_source, utm_medium, utm_campaign, utm_content, utm_term, created_at`
+
+// PostgresMetricsRepository реализует MetricsRepository для PostgreSQL
+type PostgresMetricsRepository struct {
+	postgresRepository
+}
+
+var _ MetricsRepository = (*PostgresMetricsRepository)(nil)
+
+// NewPostgresMetricsRepository создает новый экземпляр PostgresMetricsRepository
+func NewPostgresMetricsRepository(db *sql.DB, queryTimeout time.Duration) *PostgresMetricsRepository {
+	return &PostgresMetricsRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+func scanClickEvent(row interface{ Scan(...any) error }) (models.ClickEvent, error) {
+	var e models.ClickEvent
+	err := row.Scan(&e.ID, &e.LinkButtonID, &e.IP, &e.UserAgent, &e.Referer,
+		&e.UTMSource, &e.UTMMedium, &e.UTMCampaign, &e.UTMContent, &e.UTMTerm, &e.CreatedAt)
+	return e, err
+}
+
+func (r *PostgresMetricsRepository) queryClickEvents(ctx context.Context, query string, args ...any) ([]models.ClickEvent, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.ClickEvent, 0)
+	for rows.Next() {
+		e, err := scanClickEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// CreateLinkMetrics создает метрику для кнопки и возвращает ее ID
+func (r *PostgresMetricsRepository) CreateLinkMetrics(ctx context.Context, metrics models.LinkMetrics) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var lastClickAt sql.NullTime
+	if !metrics.LastClickAt.IsZero() {
+		lastClickAt = sql.NullTime{Time: metrics.LastClickAt, Valid: true}
+	}
+
+	var id int64
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`INSERT INTO link_metrics (link_button_id, clicks, last_click_at) VALUES ($1, $2, $3) RETURNING id`,
+		metrics.LinkButtonID, metrics.Clicks, lastClickAt,
+	).Scan(&id)
+	return id, err
+}
+
+// GetMetricsByButtonID получает метрику для кнопки
+func (r *PostgresMetricsRepository) GetMetricsByButtonID(ctx context.Context, buttonID int64) (models.LinkMetrics, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var m models.LinkMetrics
+	var lastClickAt sql.NullTime
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT id, link_button_id, clicks, last_click_at FROM link_metrics WHERE link_button_id = $1`, buttonID,
+	).Scan(&m.ID, &m.LinkButtonID, &m.Clicks, &lastClickAt)
+	if err != nil {
+		return m, notFound(err)
+	}
+	m.LastClickAt = lastClickAt.Time
+	return m, nil
+}
+
+// UpdateLinkMetrics обновляет метрику кнопки
+func (r *PostgresMetricsRepository) UpdateLinkMetrics(ctx context.Context, metrics models.LinkMetrics) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var lastClickAt sql.NullTime
+	if !metrics.LastClickAt.IsZero() {
+		lastClickAt = sql.NullTime{Time: metrics.LastClickAt, Valid: true}
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx,
+		`UPDATE link_metrics SET clicks = $2, last_click_at = $3 WHERE link_button_id = $1`,
+		metrics.LinkButtonID, metrics.Clicks, lastClickAt,
+	)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
+// DeleteMetricsByButtonID удаляет метрику для кнопки
+func (r *PostgresMetricsRepository) DeleteMetricsByButtonID(ctx context.Context, buttonID int64) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM link_metrics WHERE link_button_id = $1`, buttonID)
+	return err
+}
+
+// DeleteMetricsByMultiLinkID удаляет метрики всех кнопок мультиссылки
+func (r *PostgresMetricsRepository) DeleteMetricsByMultiLinkID(ctx context.Context, multiLinkID int64) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.conn(ctx).ExecContext(ctx,
+		`DELETE FROM link_metrics WHERE link_button_id IN (SELECT id FROM link_buttons WHERE multilink_id = $1)`, multiLinkID)
+	return err
+}
+
+// CreateClickEvent создает событие клика и возвращает его ID
+func (r *PostgresMetricsRepository) CreateClickEvent(ctx context.Context, event models.ClickEvent) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var id int64
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`INSERT INTO click_events (link_button_id, ip, user_agent, referer, utm_source, utm_medium, utm_campaign, utm_content, utm_term, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`,
+		event.LinkButtonID, event.IP, event.UserAgent, event.Referer,
+		event.UTMSource, event.UTMMedium, event.UTMCampaign, event.UTMContent, event.UTMTerm, event.CreatedAt,
+	).Scan(&id)
+	return id, err
+}
+
+// GetClickEventsByButtonID получает все события кликов для кнопки
+func (r *PostgresMetricsRepository) GetClickEventsByButtonID(ctx context.Context, buttonID int64) ([]models.ClickEvent, error) {
+	return r.queryClickEvents(ctx,
+		`SELECT `+clickEventColumns+` FROM click_events WHERE link_button_id = $1 ORDER BY created_at`, buttonID)
+}
+
+// DeleteClickEventsByButtonID удаляет все события кликов для кнопки
+func (r *PostgresMetricsRepository) DeleteClickEventsByButtonID(ctx context.Context, buttonID int64) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.conn(ctx).ExecContext(ctx, `DELETE FROM click_events WHERE link_button_id = $1`, buttonID)
+	return err
+}
+
+// DeleteClickEventsByMultiLinkID удаляет события кликов всех кнопок мультиссылки
+func (r *PostgresMetricsRepository) DeleteClickEventsByMultiLinkID(ctx context.Context, multiLinkID int64) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.conn(ctx).ExecContext(ctx,
+		`DELETE FROM click_events WHERE link_button_id IN (SELECT id FROM link_buttons WHERE multilink_id = $1)`, multiLinkID)
+	return err
+}
+
+// GetClickEventsByButtonIDsAndDateRange получает события кликов для кнопок за указанный период
+func (r *PostgresMetricsRepository) GetClickEventsByButtonIDsAndDateRange(ctx context.Context, buttonIDs []int64, startDate, endDate time.Time) ([]models.ClickEvent, error) {
+	if len(buttonIDs) == 0 {
+		return []models.ClickEvent{}, nil
+	}
+	return r.queryClickEvents(ctx,
+		`SELECT `+clickEventColumns+` FROM click_events
+		 WHERE link_button_id = ANY($1) AND created_at >= $2 AND created_at < $3
+		 ORDER BY created_at`,
+		pq.Array(buttonIDs), startDate, endDate)
+}
+
+// GetUTMSourceStatsByButtonIDs получает статистику по источникам трафика (utm_source) для кнопок
+func (r *PostgresMetricsRepository) GetUTMSourceStatsByButtonIDs(ctx context.Context, buttonIDs []int64) (map[string]int, error) {
+	return r.utmStats(ctx, "utm_source", buttonIDs)
+}
+
+// GetUTMMediumStatsByButtonIDs получает статистику по каналам трафика (utm_medium) для кнопок
+func (r *PostgresMetricsRepository) GetUTMMediumStatsByButtonIDs(ctx context.Context, buttonIDs []int64) (map[string]int, error) {
+	return r.utmStats(ctx, "utm_medium", buttonIDs)
+}
+
+// utmStats группирует клики по UTM-метке. column подставляется только из
+// констант выше, поэтому конкатенация безопасна
+func (r *PostgresMetricsRepository) utmStats(ctx context.Context, column string, buttonIDs []int64) (map[string]int, error) {
+	stats := make(map[string]int)
+	if len(buttonIDs) == 0 {
+		return stats, nil
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		`SELECT `+column+`, COUNT(*) FROM click_events
+		 WHERE link_button_id = ANY($1) AND `+column+` <> ''
+		 GROUP BY `+column, pq.Array(buttonIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		stats[value] = count
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+const multiLinkColumns = `id, user_id, title, description, slug, is_active, created_at, updated_at, deleted_at, publish_at, unpublish_at, password_hash,
+	sensitive, sensitive_reason, forced_sensitive_reason, theme_preset, theme_overrides`
+
+// PostgresMultiLinkRepository реализует MultiLinkRepository для PostgreSQL
+type PostgresMultiLinkRepository struct {
+	postgresRepository
+}
+
+var _ MultiLinkRepository = (*PostgresMultiLinkRepository)(nil)
+
+// NewPostgresMultiLinkRepository создает новый экземпляр PostgresMultiLinkRepository
+func NewPostgresMultiLinkRepository(db *sql.DB, queryTimeout time.Duration) *PostgresMultiLinkRepository {
+	return &PostgresMultiLinkRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+func scanMultiLink(row interface{ Scan(...any) error }) (models.MultiLink, error) {
+	var m models.MultiLink
+	var deletedAt, publishAt, unpublishAt sql.NullTime
+	var themeOverrides []byte
+	err := row.Scan(&m.ID, &m.UserID, &m.Title, &m.Description, &m.Slug, &m.IsActive, &m.CreatedAt, &m.UpdatedAt, &deletedAt,
+		&publishAt, &unpublishAt, &m.PasswordHash, &m.Sensitive, &m.SensitiveReason, &m.ForcedSensitiveReason,
+		&m.ThemePreset, &themeOverrides)
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(themeOverrides, &m.ThemeOverrides); err != nil {
+		return m, err
+	}
+	m.PasswordProtected = m.PasswordHash != ""
+	m.DeletedAt = nullTimePtr(deletedAt)
+	m.PublishAt = nullTimePtr(publishAt)
+	m.UnpublishAt = nullTimePtr(unpublishAt)
+	return m, nil
+}
+
+func (r *PostgresMultiLinkRepository) getMultiLink(ctx context.Context, query string, args ...any) (models.MultiLink, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	m, err := scanMultiLink(r.conn(ctx).QueryRowContext(ctx, query, args...))
+	return m, notFound(err)
+}
+
+func (r *PostgresMultiLinkRepository) queryMultiLinks(ctx context.Context, query string, args ...any) ([]models.MultiLink, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	multiLinks := make([]models.MultiLink, 0)
+	for rows.Next() {
+		m, err := scanMultiLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		multiLinks = append(multiLinks, m)
+	}
+	return multiLinks, rows.Err()
+}
+
+// CreateMultiLink создает новую мультиссылку и возвращает ее ID
+func (r *PostgresMultiLinkRepository) CreateMultiLink(ctx context.Context, multiLink models.MultiLink) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var id int64
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`INSERT INTO multilinks (user_id, title, description, slug, is_active, created_at, updated_at, publish_at, unpublish_at,
+		 sensitive, sensitive_reason)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+		multiLink.UserID, multiLink.Title, multiLink.Description, multiLink.Slug, multiLink.IsActive,
+		multiLink.CreatedAt, multiLink.UpdatedAt, multiLink.PublishAt, multiLink.UnpublishAt,
+		multiLink.Sensitive, multiLink.SensitiveReason,
+	).Scan(&id)
+	return id, err
+}
+
+// GetMultiLinkByID получает мультиссылку по ID
+func (r *PostgresMultiLinkRepository) GetMultiLinkByID(ctx context.Context, id int64) (models.MultiLink, error) {
+	return r.getMultiLink(ctx,
+		`SELECT `+multiLinkColumns+` FROM multilinks WHERE id = $1 AND deleted_at IS NULL`, id)
+}
+
+// LockMultiLink блокирует строку мультиссылки до завершения транзакции
+func (r *PostgresMultiLinkRepository) LockMultiLink(ctx context.Context, id int64) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var locked int64
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT id FROM multilinks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id,
+	).Scan(&locked)
+	return notFound(err)
+}
+
+// GetMultiLinkBySlug получает мультиссылку по slug
+func (r *PostgresMultiLinkRepository) GetMultiLinkBySlug(ctx context.Context, slug string) (models.MultiLink, error) {
+	return r.getMultiLink(ctx,
+		`SELECT `+multiLinkColumns+` FROM multilinks WHERE slug = $1 AND deleted_at IS NULL`, slug)
+}
+
+// GetMultiLinksByUserID получает все мультиссылки пользователя
+func (r *PostgresMultiLinkRepository) GetMultiLinksByUserID(ctx context.Context, userID int64) ([]models.MultiLink, error) {
+	return r.queryMultiLinks(ctx,
+		`SELECT `+multiLinkColumns+` FROM multilinks
+		 WHERE user_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC, id DESC`, userID)
+}
+
+// UpdateMultiLink обновляет мультиссылку
+func (r *PostgresMultiLinkRepository) UpdateMultiLink(ctx context.Context, multiLink models.MultiLink) error {
+	return r.exec(ctx, true,
+		`UPDATE multilinks SET title = $2, description = $3, slug = $4, is_active = $5, updated_at = $6,
+		 publish_at = $7, unpublish_at = $8, sensitive = $9, sensitive_reason = $10
+		 WHERE id = $1 AND deleted_at IS NULL`,
+		multiLink.ID, multiLink.Title, multiLink.Description, multiLink.Slug, multiLink.IsActive, multiLink.UpdatedAt,
+		multiLink.PublishAt, multiLink.UnpublishAt, multiLink.Sensitive, multiLink.SensitiveReason,
+	)
+}
+
+// SetMultiLinkPassword задает хэш пароля мультиссылки. Пустой хэш снимает защиту
+func (r *PostgresMultiLinkRepository) SetMultiLinkPassword(ctx context.Context, id int64, passwordHash string) error {
+	return r.exec(ctx, true,
+		`UPDATE multilinks SET password_hash = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id, passwordHash)
+}
+
+// SetForcedSensitiveReason задает отметку чувствительного содержимого,
+// установленную администратором. Пустая причина снимает отметку
+func (r *PostgresMultiLinkRepository) SetForcedSensitiveReason(ctx context.Context, id int64, reason string) error {
+	return r.exec(ctx, true,
+		`UPDATE multilinks SET forced_sensitive_reason = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id, reason)
+}
+
+// SetMultiLinkTheme задает пресет и переопределения оформления мультиссылки
+func (r *PostgresMultiLinkRepository) SetMultiLinkTheme(ctx context.Context, id int64, preset string, overrides models.ThemeOverrides) error {
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	return r.exec(ctx, true,
+		`UPDATE multilinks SET theme_preset = $2, theme_overrides = $3, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`,
+		id, preset, data)
+}
+
+// DeleteMultiLink окончательно удаляет мультиссылку, в том числе из корзины
+func (r *PostgresMultiLinkRepository) DeleteMultiLink(ctx context.Context, id int64) error {
+	return r.exec(ctx, true, `DELETE FROM multilinks WHERE id = $1`, id)
+}
+
+// CheckSlugExists проверяет существование мультиссылки с указанным slug.
+// Slug мультиссылок из корзины считается свободным
+func (r *PostgresMultiLinkRepository) CheckSlugExists(ctx context.Context, slug string) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var exists bool
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM multilinks WHERE slug = $1 AND deleted_at IS NULL)`, slug).Scan(&exists)
+	return exists, err
+}
+
+// SoftDeleteMultiLink перемещает мультиссылку в корзину
+func (r *PostgresMultiLinkRepository) SoftDeleteMultiLink(ctx context.Context, id int64, deletedAt time.Time) error {
+	return r.exec(ctx, true,
+		`UPDATE multilinks SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`, id, deletedAt)
+}
+
+// RestoreMultiLink восстанавливает мультиссылку из корзины
+func (r *PostgresMultiLinkRepository) RestoreMultiLink(ctx context.Context, id int64) error {
+	return r.exec(ctx, true,
+		`UPDATE multilinks SET deleted_at = NULL, updated_at = NOW() WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+}
+
+// GetDeletedMultiLinkByID получает мультиссылку из корзины по ID
+func (r *PostgresMultiLinkRepository) GetDeletedMultiLinkByID(ctx context.Context, id int64) (models.MultiLink, error) {
+	return r.getMultiLink(ctx,
+		`SELECT `+multiLinkColumns+` FROM multilinks WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+}
+
+// GetDeletedMultiLinksByUserID получает мультиссылки пользователя из корзины
+func (r *PostgresMultiLinkRepository) GetDeletedMultiLinksByUserID(ctx context.Context, userID int64) ([]models.MultiLink, error) {
+	return r.queryMultiLinks(ctx,
+		`SELECT `+multiLinkColumns+` FROM multilinks
+		 WHERE user_id = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC`, userID)
+}
+
+// GetMultiLinkIDsDeletedBefore получает ID мультиссылок, перемещенных в корзину раньше указанного времени
+func (r *PostgresMultiLinkRepository) GetMultiLinkIDsDeletedBefore(ctx context.Context, before time.Time) ([]int64, error) {
+	return r.queryIDs(ctx,
+		`SELECT id FROM multilinks WHERE deleted_at IS NOT NULL AND deleted_at < $1 ORDER BY id`, before)
+}
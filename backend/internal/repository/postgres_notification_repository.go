@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// PostgresNotificationRepository реализует NotificationRepository для PostgreSQL
+type PostgresNotificationRepository struct {
+	postgresRepository
+}
+
+var _ NotificationRepository = (*PostgresNotificationRepository)(nil)
+
+// NewPostgresNotificationRepository создает новый экземпляр PostgresNotificationRepository
+func NewPostgresNotificationRepository(db *sql.DB, queryTimeout time.Duration) *PostgresNotificationRepository {
+	return &PostgresNotificationRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+// CreateNotification сохраняет уведомление и возвращает его ID
+func (r *PostgresNotificationRepository) CreateNotification(ctx context.Context, notification models.Notification) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var id int64
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`INSERT INTO notifications (user_id, kind, title, payload, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		notification.UserID, notification.Kind, notification.Title, []byte(notification.Payload), notification.CreatedAt,
+	).Scan(&id)
+	return id, err
+}
+
+// GetNotificationsByUserID получает последние limit уведомлений пользователя, начиная с новых
+func (r *PostgresNotificationRepository) GetNotificationsByUserID(ctx context.Context, userID int64, limit int) ([]models.Notification, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		`SELECT id, user_id, kind, title, payload, created_at, read_at FROM notifications
+		 WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]models.Notification, 0)
+	for rows.Next() {
+		var n models.Notification
+		var payload []byte
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Title, &payload, &n.CreatedAt, &readAt); err != nil {
+			return nil, err
+		}
+		n.Payload = payload
+		n.ReadAt = nullTimePtr(readAt)
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+// MarkNotificationRead отмечает уведомление пользователя прочитанным.
+// Повторная отметка не меняет время прочтения
+func (r *PostgresNotificationRepository) MarkNotificationRead(ctx context.Context, id, userID int64, at time.Time) error {
+	return r.exec(ctx, true,
+		`UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE id = $1 AND user_id = $2`, id, userID, at)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// PostgresProfileRepository реализует ProfileRepository для PostgreSQL
+type PostgresProfileRepository struct {
+	postgresRepository
+}
+
+var _ ProfileRepository = (*PostgresProfileRepository)(nil)
+
+// NewPostgresProfileRepository создает новый экземпляр PostgresProfileRepository
+func NewPostgresProfileRepository(db *sql.DB, queryTimeout time.Duration) *PostgresProfileRepository {
+	return &PostgresProfileRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+// GetProfileByUserID получает публичный профиль пользователя. Аватар хранится
+// в users, остальные поля — в user_profiles, запись в которой может отсутствовать
+func (r *PostgresProfileRepository) GetProfileByUserID(ctx context.Context, userID int64) (models.UserProfile, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var p models.UserProfile
+	err := r.conn(ctx).QueryRowContext(ctx,
+		`SELECT u.username, COALESCE(p.display_name, ''), u.avatar_url, COALESCE(p.bio, ''),
+		        COALESCE(p.theme_color, ''), COALESCE(p.background_url, '')
+		 FROM users u LEFT JOIN user_profiles p ON p.user_id = u.id
+		 WHERE u.id = $1`, userID,
+	).Scan(&p.Username, &p.DisplayName, &p.AvatarURL, &p.Bio, &p.ThemeColor, &p.BackgroundURL)
+	return p, notFound(err)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+const revisionColumns = `id, multilink_id, revision, author_id, author_name, action, snapshot, created_at`
+
+// PostgresRevisionRepository реализует RevisionRepository для PostgreSQL.
+// Снимок хранится в столбце JSONB
+type PostgresRevisionRepository struct {
+	postgresRepository
+}
+
+var _ RevisionRepository = (*PostgresRevisionRepository)(nil)
+
+// NewPostgresRevisionRepository создает новый экземпляр PostgresRevisionRepository
+func NewPostgresRevisionRepository(db *sql.DB, queryTimeout time.Duration) *PostgresRevisionRepository {
+	return &PostgresRevisionRepository{postgresRepository{db: db, queryTimeout: queryTimeout}}
+}
+
+func scanRevision(row interface{ Scan(...any) error }) (models.MultiLinkRevision, error) {
+	var rev models.MultiLinkRevision
+	var authorID sql.NullInt64
+	var snapshot []byte
+	err := row.Scan(&rev.ID, &rev.MultiLinkID, &rev.Revision, &authorID, &rev.AuthorName, &rev.Action, &snapshot, &rev.CreatedAt)
+	if err != nil {
+		return rev, err
+	}
+	if authorID.Valid {
+		rev.AuthorID = &authorID.Int64
+	}
+	return rev, json.Unmarshal(snapshot, &rev.Snapshot)
+}
+
+// CreateRevision сохраняет ревизию со следующим номером для мультиссылки.
+// Строка мультиссылки блокируется до конца транзакции, чтобы параллельные
+// изменения получали последовательные номера
+func (r *PostgresRevisionRepository) CreateRevision(ctx context.Context, revision models.MultiLinkRevision) (models.MultiLinkRevision, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	snapshot, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return revision, err
+	}
+
+	conn := r.conn(ctx)
+	if _, err := conn.ExecContext(ctx, `SELECT 1 FROM multilinks WHERE id = $1 FOR UPDATE`, revision.MultiLinkID); err != nil {
+		return revision, err
+	}
+
+	err = conn.QueryRowContext(ctx,
+		`INSERT INTO multilink_revisions (multilink_id, revision, author_id, author_name, action, snapshot)
+		 SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5
+		 FROM multilink_revisions WHERE multilink_id = $1
+		 RETURNING id, revision, created_at`,
+		revision.MultiLinkID, revision.AuthorID, revision.AuthorName, revision.Action, snapshot,
+	).Scan(&revision.ID, &revision.Revision, &revision.CreatedAt)
+	return revision, err
+}
+
+// GetRevisionsByMultiLinkID получает все ревизии мультиссылки, начиная с последней
+func (r *PostgresRevisionRepository) GetRevisionsByMultiLinkID(ctx context.Context, multiLinkID int64) ([]models.MultiLinkRevision, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		`SELECT `+revisionColumns+` FROM multilink_revisions
+		 WHERE multilink_id = $1 ORDER BY revision DESC`, multiLinkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]models.MultiLinkRevision, 0)
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// GetRevision получает ревизию мультиссылки по номеру
+func (r *PostgresRevisionRepository) GetRevision(ctx context.Context, multiLinkID int64, revision int) (models.MultiLinkRevision, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rev, err := scanRevision(r.conn(ctx).QueryRowContext(ctx,
+		`SELECT `+revisionColumns+` FROM multilink_revisions
+		 WHERE multilink_id = $1 AND revision = $2`, multiLinkID, revision))
+	return rev, notFound(err)
+}
+
+// DeleteRevisionsByMultiLinkID удаляет все ревизии мультиссылки
+func (r *PostgresRevisionRepository) DeleteRevisionsByMultiLinkID(ctx context.Context, multiLinkID int64) error {
+	return r.exec(ctx, false, `DELETE FROM multilink_revisions WHERE multilink_id = $1`, multiLinkID)
+}
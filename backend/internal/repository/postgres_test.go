@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"mvp_multylink/backend/internal/logging"
+)
+
+// failingDB возвращает заданную ошибку на любой запрос
+type failingDB struct {
+	DBTX
+	err error
+}
+
+func (f failingDB) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, f.err
+}
+
+func TestLoggedConnWritesFailedQueryToContextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewWithWriter(&buf, "info").With("request_id", "req-42")
+	ctx := logging.WithLogger(context.Background(), logger)
+
+	conn := loggedConn{db: failingDB{err: errors.New("connection reset")}, logger: logging.FromContext(ctx)}
+	if _, err := conn.ExecContext(ctx, "UPDATE users\n\tSET password_hash = $1", "secret-hash"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	line := buf.String()
+	for _, want := range []string{`"level":"ERROR"`, `"request_id":"req-42"`, `"query":"UPDATE users SET password_hash = $1"`, "connection reset"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line %s does not contain %s", line, want)
+		}
+	}
+	if strings.Contains(line, "secret-hash") {
+		t.Errorf("log line %s contains query arguments", line)
+	}
+}
+
+func TestLoggedConnSkipsExpectedErrorsAtInfoLevel(t *testing.T) {
+	for name, err := range map[string]error{
+		"unique violation": &pq.Error{Code: uniqueViolationCode},
+		"canceled":         context.Canceled,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			conn := loggedConn{db: failingDB{err: err}, logger: logging.NewWithWriter(&buf, "info")}
+			_, _ = conn.ExecContext(context.Background(), "INSERT INTO multilinks (slug) VALUES ($1)", "taken")
+			if buf.Len() != 0 {
+				t.Errorf("expected no log output, got %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// ProfileRepository определяет интерфейс для чтения публичных профилей пользователей
+type ProfileRepository interface {
+	// GetProfileByUserID получает публичный профиль пользователя
+	GetProfileByUserID(ctx context.Context, userID int64) (models.UserProfile, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// RevisionRepository определяет интерфейс для работы с историей изменений мультиссылок
+type RevisionRepository interface {
+	// CreateRevision сохраняет ревизию со следующим номером для мультиссылки
+	// и возвращает ее с заполненными ID, номером и временем создания
+	CreateRevision(ctx context.Context, revision models.MultiLinkRevision) (models.MultiLinkRevision, error)
+
+	// GetRevisionsByMultiLinkID получает все ревизии мультиссылки, начиная с последней
+	GetRevisionsByMultiLinkID(ctx context.Context, multiLinkID int64) ([]models.MultiLinkRevision, error)
+
+	// GetRevision получает ревизию мультиссылки по номеру
+	GetRevision(ctx context.Context, multiLinkID int64, revision int) (models.MultiLinkRevision, error)
+
+	// DeleteRevisionsByMultiLinkID удаляет все ревизии мультиссылки
+	DeleteRevisionsByMultiLinkID(ctx context.Context, multiLinkID int64) error
+}
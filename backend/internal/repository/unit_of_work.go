@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"mvp_multylink/backend/internal/logging"
+)
+
+// UnitOfWork выполняет несколько операций репозиториев атомарно
+type UnitOfWork interface {
+	// Do выполняет fn в транзакции. Репозитории, вызванные с переданным в fn
+	// контекстом, присоединяются к транзакции. Если fn возвращает ошибку,
+	// транзакция откатывается. Вложенный вызов Do присоединяется к внешней транзакции
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// DBTX — общий интерфейс *sql.DB и *sql.Tx
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// txFromContext возвращает транзакцию, открытую UnitOfWork, если она есть
+func txFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// PostgresUnitOfWork реализует UnitOfWork на транзакциях database/sql
+type PostgresUnitOfWork struct {
+	db *sql.DB
+}
+
+var _ UnitOfWork = (*PostgresUnitOfWork)(nil)
+
+// NewPostgresUnitOfWork создает новый экземпляр PostgresUnitOfWork
+func NewPostgresUnitOfWork(db *sql.DB) *PostgresUnitOfWork {
+	return &PostgresUnitOfWork{db: db}
+}
+
+// Do выполняет fn в транзакции
+func (u *PostgresUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				logging.FromContext(ctx).Error("transaction rollback failed", "error", rbErr)
+			}
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				logging.FromContext(ctx).Error("transaction rollback failed", "error", rbErr, "cause", err)
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
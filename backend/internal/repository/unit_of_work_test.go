@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var errStatementFailed = errors.New("statement failed")
+
+// fakeDatabase — драйвер database/sql, который запоминает выполненные
+// запросы. Запросы транзакции применяются только при фиксации, поэтому
+// committed показывает, что осталось бы в базе
+type fakeDatabase struct {
+	mu        sync.Mutex
+	committed []string
+	begins    int
+	commits   int
+	rollbacks int
+	// failOn — фрагмент запроса, на котором выполнение завершится ошибкой
+	failOn string
+}
+
+func (d *fakeDatabase) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: d}, nil
+}
+
+func (d *fakeDatabase) Driver() driver.Driver {
+	return fakeDriver{d}
+}
+
+type fakeDriver struct {
+	db *fakeDatabase
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{db: d.db}, nil
+}
+
+// fakeConn накапливает запросы открытой транзакции в pending
+type fakeConn struct {
+	db      *fakeDatabase
+	inTx    bool
+	pending []string
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.begins++
+	c.inTx = true
+	return fakeTx{c}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if c.db.failOn != "" && strings.Contains(query, c.db.failOn) {
+		return nil, errStatementFailed
+	}
+	if c.inTx {
+		c.pending = append(c.pending, query)
+	} else {
+		c.db.committed = append(c.db.committed, query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (t fakeTx) Commit() error {
+	db := t.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.commits++
+	db.committed = append(db.committed, t.conn.pending...)
+	t.conn.pending, t.conn.inTx = nil, false
+	return nil
+}
+
+func (t fakeTx) Rollback() error {
+	db := t.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.rollbacks++
+	t.conn.pending, t.conn.inTx = nil, false
+	return nil
+}
+
+// deleteMultiLink повторяет каскадное удаление мультиссылки поверх
+// PostgreSQL-репозиториев
+func deleteMultiLink(t *testing.T, fake *fakeDatabase) error {
+	t.Helper()
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { _ = db.Close() })
+
+	metricsRepo := NewPostgresMetricsRepository(db, 0)
+	buttonRepo := NewPostgresButtonRepository(db, 0)
+	multiLinkRepo := NewPostgresMultiLinkRepository(db, 0)
+
+	return NewPostgresUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		if err := metricsRepo.DeleteClickEventsByMultiLinkID(ctx, 1); err != nil {
+			return err
+		}
+		if err := metricsRepo.DeleteMetricsByMultiLinkID(ctx, 1); err != nil {
+			return err
+		}
+		if err := buttonRepo.DeleteButtonsByMultiLinkID(ctx, 1); err != nil {
+			return err
+		}
+		return multiLinkRepo.DeleteMultiLink(ctx, 1)
+	})
+}
+
+func TestUnitOfWorkRollsBackEarlierDeletesOnFailure(t *testing.T) {
+	fake := &fakeDatabase{failOn: "DELETE FROM multilinks"}
+
+	if err := deleteMultiLink(t, fake); !errors.Is(err, errStatementFailed) {
+		t.Fatalf("Do error = %v, want %v", err, errStatementFailed)
+	}
+
+	// Удаления аналитики и кнопок выполнены в транзакции и откатились вместе с ней
+	if len(fake.committed) != 0 {
+		t.Errorf("committed statements = %q, want none", fake.committed)
+	}
+	if fake.begins != 1 || fake.rollbacks != 1 || fake.commits != 0 {
+		t.Errorf("begins/commits/rollbacks = %d/%d/%d, want 1/0/1", fake.begins, fake.commits, fake.rollbacks)
+	}
+}
+
+func TestUnitOfWorkCommitsAllStatementsInOneTransaction(t *testing.T) {
+	fake := &fakeDatabase{}
+
+	if err := deleteMultiLink(t, fake); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	if len(fake.committed) != 4 {
+		t.Errorf("committed statements = %q, want 4", fake.committed)
+	}
+	if fake.begins != 1 || fake.commits != 1 {
+		t.Errorf("begins/commits = %d/%d, want 1/1", fake.begins, fake.commits)
+	}
+}
+
+func TestUnitOfWorkNestedDoJoinsOuterTransaction(t *testing.T) {
+	fake := &fakeDatabase{failOn: "DELETE FROM multilinks"}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	uow := NewPostgresUnitOfWork(db)
+	buttonRepo := NewPostgresButtonRepository(db, 0)
+	multiLinkRepo := NewPostgresMultiLinkRepository(db, 0)
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if err := uow.Do(ctx, func(ctx context.Context) error {
+			return buttonRepo.DeleteButtonsByMultiLinkID(ctx, 1)
+		}); err != nil {
+			return err
+		}
+		return multiLinkRepo.DeleteMultiLink(ctx, 1)
+	})
+	if !errors.Is(err, errStatementFailed) {
+		t.Fatalf("Do error = %v, want %v", err, errStatementFailed)
+	}
+	if fake.begins != 1 || len(fake.committed) != 0 {
+		t.Errorf("begins = %d, committed = %q, want one transaction and nothing committed", fake.begins, fake.committed)
+	}
+}
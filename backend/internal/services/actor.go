@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+)
+
+// Actor описывает пользователя, выполняющего изменение
+type Actor struct {
+	UserID   int64
+	Username string
+}
+
+type actorKey struct{}
+
+// WithActor возвращает контекст с пользователем, выполняющим запрос
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает пользователя из контекста. Для фоновых задач
+// пользователь отсутствует
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/logging"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// BlocklistService предоставляет методы для работы со списком блокировки:
+// записи администраторов, перезагрузку файла и отчет о затронутых мультиссылках
+type BlocklistService struct {
+	blocklistRepo repository.BlocklistRepository
+	multiLinkRepo repository.MultiLinkRepository
+	buttonRepo    repository.ButtonRepository
+	list          *blocklist.List
+}
+
+// NewBlocklistService создает новый экземпляр BlocklistService
+func NewBlocklistService(blocklistRepo repository.BlocklistRepository, multiLinkRepo repository.MultiLinkRepository, buttonRepo repository.ButtonRepository, list *blocklist.List) *BlocklistService {
+	return &BlocklistService{
+		blocklistRepo: blocklistRepo,
+		multiLinkRepo: multiLinkRepo,
+		buttonRepo:    buttonRepo,
+		list:          list,
+	}
+}
+
+// GetBlockedDomains получает записи списка блокировки, добавленные администраторами
+func (s *BlocklistService) GetBlockedDomains(ctx context.Context) ([]models.BlockedDomain, error) {
+	return s.blocklistRepo.GetBlockedDomains(ctx)
+}
+
+// AddBlockedDomain добавляет запись в список блокировки. Запись начинает
+// действовать сразу, без ожидания перезагрузки
+func (s *BlocklistService) AddBlockedDomain(ctx context.Context, pattern, reason string) (models.BlockedDomain, error) {
+	normalized, err := blocklist.Normalize(pattern)
+	if err != nil {
+		return models.BlockedDomain{}, err
+	}
+
+	domain := models.BlockedDomain{Pattern: normalized, Reason: reason}
+	if actor, ok := ActorFromContext(ctx); ok {
+		domain.CreatedBy = &actor.UserID
+	}
+
+	domain, err = s.blocklistRepo.CreateBlockedDomain(ctx, domain)
+	if err != nil {
+		return domain, err
+	}
+	return domain, s.Refresh(ctx)
+}
+
+// DeleteBlockedDomain удаляет запись из списка блокировки
+func (s *BlocklistService) DeleteBlockedDomain(ctx context.Context, id int64) error {
+	if err := s.blocklistRepo.DeleteBlockedDomain(ctx, id); err != nil {
+		return err
+	}
+	return s.Refresh(ctx)
+}
+
+// Refresh загружает записи администраторов из базы в список блокировки
+func (s *BlocklistService) Refresh(ctx context.Context) error {
+	domains, err := s.blocklistRepo.GetBlockedDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	patterns := make([]string, 0, len(domains))
+	for _, d := range domains {
+		patterns = append(patterns, d.Pattern)
+	}
+	s.list.SetAdminPatterns(patterns)
+	return nil
+}
+
+// Reload перечитывает файл списка блокировки, если он изменился,
+// и записи администраторов
+func (s *BlocklistService) Reload(ctx context.Context) (bool, error) {
+	reloaded, err := s.list.Reload()
+	if err != nil {
+		return false, fmt.Errorf("blocklist file: %w", err)
+	}
+	return reloaded, s.Refresh(ctx)
+}
+
+// RunReloader периодически вызывает Reload до отмены ctx. Так изменения
+// файла и записи, добавленные на других экземплярах API, применяются без перезапуска
+func (s *BlocklistService) RunReloader(ctx context.Context, interval time.Duration) {
+	logger := logging.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		reloaded, err := s.Reload(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.Error("blocklist reload failed", "error", err)
+		case reloaded:
+			logger.Info("blocklist file reloaded")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetReport получает мультиссылки, кнопки которых ведут на заблокированные ресурсы
+func (s *BlocklistService) GetReport(ctx context.Context) ([]models.BlocklistReportItem, error) {
+	buttons, err := s.buttonRepo.GetAllButtons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]models.BlocklistReportItem, 0)
+	index := make(map[int64]int)
+	for _, button := range buttons {
+		match, blocked := matchButton(s.list, button)
+		if !blocked {
+			continue
+		}
+
+		i, ok := index[button.MultiLinkID]
+		if !ok {
+			multiLink, err := s.multiLinkRepo.GetMultiLinkByID(ctx, button.MultiLinkID)
+			if errors.Is(err, repository.ErrNotFound) {
+				// Мультиссылка в корзине и не показывается посетителям
+				index[button.MultiLinkID] = -1
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			i = len(report)
+			index[button.MultiLinkID] = i
+			report = append(report, models.BlocklistReportItem{
+				MultiLinkID: multiLink.ID,
+				UserID:      multiLink.UserID,
+				Title:       multiLink.Title,
+				Slug:        multiLink.Slug,
+			})
+		}
+
+		if i < 0 {
+			continue
+		}
+		report[i].Buttons = append(report[i].Buttons, models.BlockedButton{
+			ButtonID: button.ID,
+			Title:    button.Title,
+			URL:      button.URL,
+			Pattern:  match.Pattern,
+			Source:   match.Source,
+		})
+	}
+	return report, nil
+}
+
+// matchButton проверяет по списку блокировки исходящую и запасную ссылки кнопки
+func matchButton(list *blocklist.List, button models.LinkButton) (blocklist.Match, bool) {
+	if IsClickableKind(button.Kind) {
+		if match, ok := list.Match(ButtonHref(button)); ok {
+			return match, true
+		}
+	}
+	if button.FallbackURL != "" {
+		return list.Match(button.FallbackURL)
+	}
+	return blocklist.Match{}, false
+}
+
+// checkBlocked возвращает ErrBlockedURL, если кнопка ведет на заблокированный ресурс
+func checkBlocked(list *blocklist.List, button models.LinkButton) error {
+	if match, ok := matchButton(list, button); ok {
+		return fmt.Errorf("%w: %s", ErrBlockedURL, match.Pattern)
+	}
+	return nil
+}
+
+// flagBlocked помечает кнопки, ведущие на заблокированные ресурсы
+func flagBlocked(list *blocklist.List, buttons []models.LinkButton) {
+	for i := range buttons {
+		_, buttons[i].Blocked = matchButton(list, buttons[i])
+	}
+}
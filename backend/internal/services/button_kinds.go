@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+var (
+	phonePattern    = regexp.MustCompile(`^\+?[0-9]{5,15}$`)
+	telegramPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{4,31}$`)
+
+	// phoneSeparators удаляются из номера телефона перед проверкой
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+)
+
+// IsClickableKind проверяет, что кнопка данного типа ведет по ссылке.
+// Заголовки и разделители не кликабельны
+func IsClickableKind(kind string) bool {
+	return kind != models.ButtonKindHeader && kind != models.ButtonKindDivider
+}
+
+// NormalizeButton проверяет содержимое кнопки в соответствии с ее типом
+// и приводит значение URL к каноническому виду: адрес почты, номер
+// телефона в формате +79991234567, имя пользователя Telegram без @
+func NormalizeButton(button *models.LinkButton) error {
+	if button.Kind == "" {
+		button.Kind = models.ButtonKindURL
+	}
+	value := strings.TrimSpace(button.URL)
+
+	switch button.Kind {
+	case models.ButtonKindURL:
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: url должен быть абсолютной http(s)-ссылкой", ErrInvalidButtonPayload)
+		}
+	case models.ButtonKindEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Name != "" {
+			return fmt.Errorf("%w: неверный адрес электронной почты", ErrInvalidButtonPayload)
+		}
+		value = address.Address
+	case models.ButtonKindPhone, models.ButtonKindSMS, models.ButtonKindWhatsApp:
+		value = phoneSeparators.Replace(value)
+		if !phonePattern.MatchString(value) {
+			return fmt.Errorf("%w: неверный номер телефона", ErrInvalidButtonPayload)
+		}
+		if !strings.HasPrefix(value, "+") {
+			value = "+" + value
+		}
+	case models.ButtonKindTelegram:
+		value = strings.TrimPrefix(value, "@")
+		if !telegramPattern.MatchString(value) {
+			return fmt.Errorf("%w: неверное имя пользователя Telegram", ErrInvalidButtonPayload)
+		}
+	case models.ButtonKindHeader, models.ButtonKindDivider:
+		value = ""
+	default:
+		return fmt.Errorf("%w: неизвестный тип кнопки %q", ErrInvalidButtonPayload, button.Kind)
+	}
+
+	if button.Title == "" && button.Kind != models.ButtonKindDivider {
+		return fmt.Errorf("%w: title обязателен", ErrInvalidButtonPayload)
+	}
+
+	button.URL = value
+	return nil
+}
+
+// ButtonHref формирует исходящую ссылку кнопки в зависимости от ее типа.
+// Для некликабельных кнопок возвращается пустая строка
+func ButtonHref(button models.LinkButton) string {
+	switch button.Kind {
+	case models.ButtonKindEmail:
+		return "mailto:" + button.URL
+	case models.ButtonKindPhone:
+		return "tel:" + button.URL
+	case models.ButtonKindSMS:
+		return "sms:" + button.URL
+	case models.ButtonKindTelegram:
+		return "https://t.me/" + button.URL
+	case models.ButtonKindWhatsApp:
+		return "https://wa.me/" + strings.TrimPrefix(button.URL, "+")
+	case models.ButtonKindHeader, models.ButtonKindDivider:
+		return ""
+	default:
+		return button.URL
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mvp_multylink/backend/internal/logging"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// ValidateButtonLimits проверяет настройки исчерпания кнопки: для действия
+// fallback должен быть указан запасной URL
+func ValidateButtonLimits(exhaustedAction, fallbackURL string) error {
+	if exhaustedAction == models.ExhaustedActionFallback && fallbackURL == "" {
+		return fmt.Errorf("%w: для exhausted_action=fallback требуется fallback_url", ErrInvalidButtonLimits)
+	}
+	return nil
+}
+
+// remainingClicks пересчитывает остаток кликов при изменении лимита так же,
+// как это делает репозиторий: уже совершенные клики не возвращаются
+func remainingClicks(oldMax, oldRemaining, newMax *int) *int {
+	if newMax == nil {
+		return nil
+	}
+	value := *newMax
+	if oldMax != nil && oldRemaining != nil {
+		value = max(*newMax-(*oldMax-*oldRemaining), 0)
+	}
+	return &value
+}
+
+// Этапы записи клика, по которым учитываются ошибки
+const (
+	ClickStageConsume   = "consume_click"
+	ClickStageEvent     = "record_event"
+	ClickStageIncrement = "increment_counter"
+)
+
+// ClickError сообщает, на каком этапе не удалось записать клик
+type ClickError struct {
+	Stage string
+	Err   error
+}
+
+func (e *ClickError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+}
+
+func (e *ClickError) Unwrap() error {
+	return e.Err
+}
+
+// ClickResult описывает исход перехода по кнопке
+type ClickResult struct {
+	// Recorded — клик списан с кнопки и записан в аналитику
+	Recorded bool
+	// FallbackURL — запасной URL исчерпанной кнопки. Пустой, если клик
+	// записан или исчерпанная кнопка скрывается
+	FallbackURL string
+}
+
+// RecordClick списывает клик с кнопки, записывает событие и увеличивает
+// счетчик кликов в одной транзакции: клик либо учитывается целиком, либо не
+// учитывается вовсе. Если кнопка исчерпала лимит или истек ее срок, событие
+// не записывается, а в результате возвращается запасной URL для действия fallback
+func (s *ButtonService) RecordClick(ctx context.Context, button models.LinkButton, event models.ClickEvent) (ClickResult, error) {
+	now := s.clock.Now()
+	var result ClickResult
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		consumed, err := s.buttonRepo.ConsumeClick(ctx, button.ID, now)
+		if err != nil {
+			return &ClickError{Stage: ClickStageConsume, Err: err}
+		}
+		if !consumed {
+			return nil
+		}
+
+		event.LinkButtonID = button.ID
+		event.CreatedAt = now
+		if _, err := s.metricsRepo.CreateClickEvent(ctx, event); err != nil {
+			return &ClickError{Stage: ClickStageEvent, Err: err}
+		}
+		if err := incrementButtonClicks(ctx, s.metricsRepo, button.ID, now); err != nil {
+			return &ClickError{Stage: ClickStageIncrement, Err: err}
+		}
+		result.Recorded = true
+		return nil
+	})
+	if err != nil {
+		return ClickResult{}, err
+	}
+
+	if !result.Recorded && button.ExhaustedAction == models.ExhaustedActionFallback {
+		result.FallbackURL = button.FallbackURL
+	}
+	return result, nil
+}
+
+// incrementButtonClicks увеличивает счетчик кликов кнопки, создавая
+// метрики, если их еще нет
+func incrementButtonClicks(ctx context.Context, metricsRepo repository.MetricsRepository, buttonID int64, now time.Time) error {
+	metrics, err := metricsRepo.GetMetricsByButtonID(ctx, buttonID)
+	if errors.Is(err, repository.ErrNotFound) {
+		logging.FromContext(ctx).Debug("creating missing button metrics", "button_id", buttonID)
+		metrics = models.LinkMetrics{LinkButtonID: buttonID}
+		if _, err := metricsRepo.CreateLinkMetrics(ctx, metrics); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	metrics.Clicks++
+	metrics.LastClickAt = now
+	return metricsRepo.UpdateLinkMetrics(ctx, metrics)
+}
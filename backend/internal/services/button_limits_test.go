@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/models"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// newClickService возвращает сервис кнопок, для которого текущее время — now
+func newClickService(f fakeRepos, now time.Time) *ButtonService {
+	return NewButtonService(f.uow, f.button, f.metrics, clock.Func(func() time.Time { return now }))
+}
+
+func TestRecordClickCountsClickOnce(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	f := newFakeRepos()
+	button := models.LinkButton{ID: 10, MultiLinkID: 1, URL: "https://example.com", IsActive: true, MaxClicks: intPtr(5), RemainingClicks: intPtr(5)}
+	f.store.buttons[10] = button
+
+	result, err := newClickService(f, now).RecordClick(context.Background(), button, models.ClickEvent{IP: "203.0.113.7", UTMSource: "tg"})
+	if err != nil {
+		t.Fatalf("RecordClick: %v", err)
+	}
+	if !result.Recorded || result.FallbackURL != "" {
+		t.Errorf("RecordClick = %+v, want recorded", result)
+	}
+	if got := *f.store.buttons[10].RemainingClicks; got != 4 {
+		t.Errorf("remaining clicks = %d, want 4", got)
+	}
+	if len(f.store.events) != 1 || f.store.events[0].LinkButtonID != 10 || !f.store.events[0].CreatedAt.Equal(now) {
+		t.Errorf("click events = %+v, want one event of button 10 at %s", f.store.events, now)
+	}
+	if metrics := f.store.clicks[10]; metrics.Clicks != 1 || !metrics.LastClickAt.Equal(now) {
+		t.Errorf("button metrics = %+v, want 1 click at %s", metrics, now)
+	}
+}
+
+func TestRecordClickConsumesLastClickOnce(t *testing.T) {
+	f := newFakeRepos()
+	button := models.LinkButton{ID: 10, MultiLinkID: 1, URL: "https://example.com", IsActive: true, MaxClicks: intPtr(1), RemainingClicks: intPtr(1)}
+	f.store.buttons[10] = button
+	s := newClickService(f, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	const visitors = 32
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		recorded int
+	)
+	for i := 0; i < visitors; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := s.RecordClick(context.Background(), button, models.ClickEvent{})
+			if err != nil {
+				t.Errorf("RecordClick: %v", err)
+				return
+			}
+			if result.Recorded {
+				mu.Lock()
+				recorded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if recorded != 1 {
+		t.Errorf("recorded clicks = %d, want exactly 1", recorded)
+	}
+	if got := *f.store.buttons[10].RemainingClicks; got != 0 {
+		t.Errorf("remaining clicks = %d, want 0", got)
+	}
+	if len(f.store.events) != 1 || f.store.clicks[10].Clicks != 1 {
+		t.Errorf("events = %d, clicks = %d, want 1 and 1", len(f.store.events), f.store.clicks[10].Clicks)
+	}
+}
+
+func TestRecordClickOnExhaustedButton(t *testing.T) {
+	expiresAt := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		button       models.LinkButton
+		now          time.Time
+		wantRecorded bool
+		wantFallback string
+	}{
+		{
+			name:         "before deadline",
+			button:       models.LinkButton{ExpiresAt: &expiresAt},
+			now:          expiresAt.Add(-time.Second),
+			wantRecorded: true,
+		},
+		{
+			name:   "at deadline, hidden",
+			button: models.LinkButton{ExpiresAt: &expiresAt, ExhaustedAction: models.ExhaustedActionHide},
+			now:    expiresAt,
+		},
+		{
+			name:         "after deadline, fallback",
+			button:       models.LinkButton{ExpiresAt: &expiresAt, ExhaustedAction: models.ExhaustedActionFallback, FallbackURL: "https://example.com/sold-out"},
+			now:          expiresAt.Add(time.Hour),
+			wantFallback: "https://example.com/sold-out",
+		},
+		{
+			name:   "no clicks left, hidden",
+			button: models.LinkButton{MaxClicks: intPtr(3), RemainingClicks: intPtr(0)},
+			now:    expiresAt,
+		},
+		{
+			name:         "no clicks left, fallback",
+			button:       models.LinkButton{MaxClicks: intPtr(3), RemainingClicks: intPtr(0), ExhaustedAction: models.ExhaustedActionFallback, FallbackURL: "https://example.com/waitlist"},
+			now:          expiresAt,
+			wantFallback: "https://example.com/waitlist",
+		},
+		{
+			// Запасной URL не используется, пока кнопка не исчерпана
+			name:         "clicks left, fallback configured",
+			button:       models.LinkButton{MaxClicks: intPtr(3), RemainingClicks: intPtr(1), ExhaustedAction: models.ExhaustedActionFallback, FallbackURL: "https://example.com/waitlist"},
+			now:          expiresAt,
+			wantRecorded: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeRepos()
+			button := tt.button
+			button.ID, button.MultiLinkID, button.URL, button.IsActive = 10, 1, "https://example.com", true
+			f.store.buttons[10] = button
+
+			result, err := newClickService(f, tt.now).RecordClick(context.Background(), button, models.ClickEvent{})
+			if err != nil {
+				t.Fatalf("RecordClick: %v", err)
+			}
+			if result.Recorded != tt.wantRecorded || result.FallbackURL != tt.wantFallback {
+				t.Errorf("RecordClick = %+v, want recorded %v, fallback %q", result, tt.wantRecorded, tt.wantFallback)
+			}
+			wantEvents := 0
+			if tt.wantRecorded {
+				wantEvents = 1
+			}
+			if len(f.store.events) != wantEvents {
+				t.Errorf("click events = %d, want %d", len(f.store.events), wantEvents)
+			}
+		})
+	}
+}
+
+func TestRecordClickRollsBackOnFailure(t *testing.T) {
+	errDB := errors.New("db is down")
+
+	for _, tt := range []struct {
+		method string
+		stage  string
+	}{
+		{"ConsumeClick", ClickStageConsume},
+		{"CreateClickEvent", ClickStageEvent},
+		{"UpdateLinkMetrics", ClickStageIncrement},
+	} {
+		t.Run(tt.method, func(t *testing.T) {
+			f := newFakeRepos()
+			button := models.LinkButton{ID: 10, MultiLinkID: 1, URL: "https://example.com", IsActive: true, MaxClicks: intPtr(1), RemainingClicks: intPtr(1)}
+			f.store.buttons[10] = button
+			f.store.fail[tt.method] = errDB
+
+			_, err := newClickService(f, time.Now()).RecordClick(context.Background(), button, models.ClickEvent{})
+			var clickErr *ClickError
+			if !errors.As(err, &clickErr) || clickErr.Stage != tt.stage || !errors.Is(err, errDB) {
+				t.Fatalf("RecordClick error = %v, want %s stage error", err, tt.stage)
+			}
+
+			// Последний клик не списан: посетитель сможет перейти повторно
+			if got := *f.store.buttons[10].RemainingClicks; got != 1 {
+				t.Errorf("remaining clicks = %d, want 1 after rollback", got)
+			}
+			if len(f.store.events) != 0 || len(f.store.clicks) != 0 {
+				t.Errorf("events = %d, metrics = %d, want none after rollback", len(f.store.events), len(f.store.clicks))
+			}
+		})
+	}
+}
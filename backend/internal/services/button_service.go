@@ -1,67 +1,68 @@
 package services
 
 import (
+	"context"
+
+	"mvp_multylink/backend/internal/clock"
 	"mvp_multylink/backend/internal/models"
 	"mvp_multylink/backend/internal/repository"
 )
 
 // ButtonService предоставляет методы для работы с кнопками-ссылками
 type ButtonService struct {
+	uow         repository.UnitOfWork
 	buttonRepo  repository.ButtonRepository
 	metricsRepo repository.MetricsRepository
+	clock       clock.Clock
 }
 
 // NewButtonService создает новый экземпляр ButtonService
-func NewButtonService(buttonRepo repository.ButtonRepository, metricsRepo repository.MetricsRepository) *ButtonService {
+func NewButtonService(uow repository.UnitOfWork, buttonRepo repository.ButtonRepository, metricsRepo repository.MetricsRepository, clock clock.Clock) *ButtonService {
 	return &ButtonService{
+		uow:         uow,
 		buttonRepo:  buttonRepo,
 		metricsRepo: metricsRepo,
+		clock:       clock,
 	}
 }
 
-// CreateButton создает новую кнопку-ссылку
-func (s *ButtonService) CreateButton(button models.LinkButton) (int64, error) {
-	return s.buttonRepo.CreateButton(button)
-}
-
 // GetButtonByID получает кнопку по ID
-func (s *ButtonService) GetButtonByID(id int64) (models.LinkButton, error) {
-	return s.buttonRepo.GetButtonByID(id)
+func (s *ButtonService) GetButtonByID(ctx context.Context, id int64) (models.LinkButton, error) {
+	return s.buttonRepo.GetButtonByID(ctx, id)
 }
 
-// UpdateButton обновляет кнопку
-func (s *ButtonService) UpdateButton(button models.LinkButton) error {
-	return s.buttonRepo.UpdateButton(button)
-}
-
-// DeleteButton удаляет кнопку
-func (s *ButtonService) DeleteButton(id int64) error {
-	// Сначала удаляем метрики кнопки
-	err := s.metricsRepo.DeleteMetricsByButtonID(id)
-	if err != nil {
-		return err
-	}
+// PurgeButton окончательно удаляет кнопку вместе с ее метриками и событиями кликов в одной транзакции
+func (s *ButtonService) PurgeButton(ctx context.Context, id int64) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		// Сначала удаляем аналитику кнопки
+		if err := s.metricsRepo.DeleteClickEventsByButtonID(ctx, id); err != nil {
+			return err
+		}
+		if err := s.metricsRepo.DeleteMetricsByButtonID(ctx, id); err != nil {
+			return err
+		}
 
-	// Затем удаляем саму кнопку
-	return s.buttonRepo.DeleteButton(id)
+		// Затем удаляем саму кнопку
+		return s.buttonRepo.DeleteButton(ctx, id)
+	})
 }
 
 // GetButtonsCountByMultiLinkID получает количество кнопок для мультиссылки
-func (s *ButtonService) GetButtonsCountByMultiLinkID(multiLinkID int64) (int, error) {
-	return s.buttonRepo.GetButtonsCountByMultiLinkID(multiLinkID)
+func (s *ButtonService) GetButtonsCountByMultiLinkID(ctx context.Context, multiLinkID int64) (int, error) {
+	return s.buttonRepo.GetButtonsCountByMultiLinkID(ctx, multiLinkID)
 }
 
 // UpdateButtonPosition обновляет позицию кнопки
-func (s *ButtonService) UpdateButtonPosition(id int64, position int) error {
-	return s.buttonRepo.UpdateButtonPosition(id, position)
+func (s *ButtonService) UpdateButtonPosition(ctx context.Context, id int64, position int) error {
+	return s.buttonRepo.UpdateButtonPosition(ctx, id, position)
 }
 
 // CreateButtonMetrics создает метрику для кнопки
-func (s *ButtonService) CreateButtonMetrics(metrics models.LinkMetrics) (int64, error) {
-	return s.metricsRepo.CreateLinkMetrics(metrics)
+func (s *ButtonService) CreateButtonMetrics(ctx context.Context, metrics models.LinkMetrics) (int64, error) {
+	return s.metricsRepo.CreateLinkMetrics(ctx, metrics)
 }
 
 // GetButtonMetrics получает метрику для кнопки
-func (s *ButtonService) GetButtonMetrics(buttonID int64) (models.LinkMetrics, error) {
-	return s.metricsRepo.GetMetricsByButtonID(buttonID)
+func (s *ButtonService) GetButtonMetrics(ctx context.Context, buttonID int64) (models.LinkMetrics, error) {
+	return s.metricsRepo.GetMetricsByButtonID(ctx, buttonID)
 }
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/icons"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/theme"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// buttonValidator проверяет кнопку перед тем, как она попадет на страницу:
+// при сохранении в черновик и при откате к ревизии. Политика ссылок, список
+// блокировки и набор значков могли измениться с момента сохранения ревизии
+type buttonValidator struct {
+	urlPolicy *urlpolicy.Policy
+	blocklist *blocklist.List
+	images    *ImageService
+}
+
+// normalize проверяет содержимое кнопки по ее типу, исходящую и запасную
+// ссылки — по политике ссылок и списку блокировки, собственный цвет кнопки —
+// по контрасту с текстом кнопок оформления страницы, а значок — по встроенному набору
+func (v buttonValidator) normalize(button *models.LinkButton, pageTheme models.Theme) error {
+	if err := NormalizeButton(button); err != nil {
+		return err
+	}
+	if button.Color != "" {
+		color, err := theme.CheckButtonColor(pageTheme, button.Color)
+		if err != nil {
+			return err
+		}
+		button.Color = color
+	}
+	if err := v.normalizeIcon(button); err != nil {
+		return err
+	}
+	if IsClickableKind(button.Kind) {
+		if err := v.urlPolicy.Check(ButtonHref(*button)); err != nil {
+			return err
+		}
+	}
+	if button.FallbackURL != "" {
+		if err := v.urlPolicy.Check(button.FallbackURL); err != nil {
+			return err
+		}
+	}
+	return checkBlocked(v.blocklist, *button)
+}
+
+// normalizeIcon проверяет значок кнопки: это имя из встроенного набора или
+// адрес загруженного в сервис изображения. Если значок не задан, он
+// подбирается по исходящей ссылке кнопки
+func (v buttonValidator) normalizeIcon(button *models.LinkButton) error {
+	icon := strings.TrimSpace(button.Icon)
+	switch {
+	case icon == "":
+		button.Icon = icons.ForURL(ButtonHref(*button))
+	case icons.Exists(strings.ToLower(icon)):
+		button.Icon = strings.ToLower(icon)
+	case v.images.IsImageURL(icon):
+		button.Icon = icon
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidIcon, icon)
+	}
+	return nil
+}
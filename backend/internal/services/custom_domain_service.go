@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/domains"
+	"mvp_multylink/backend/internal/lrucache"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// CustomDomainService управляет пользовательскими доменами мультиссылок:
+// привязкой, подтверждением владения и определением мультиссылки по Host
+type CustomDomainService struct {
+	domainRepo   repository.CustomDomainRepository
+	verifier     *domains.Verifier
+	primaryHosts map[string]bool
+	cacheTTL     time.Duration
+	clock        clock.Clock
+	// hosts ограничен по размеру: ключом служит заголовок Host посетителя,
+	// и запросы с произвольными доменами не должны расширять кэш без предела
+	hosts *lrucache.Cache[string, hostEntry]
+}
+
+// hostEntry кэширует результат поиска мультиссылки по домену, в том числе отрицательный
+type hostEntry struct {
+	slug      string
+	found     bool
+	expiresAt time.Time
+}
+
+// NewCustomDomainService создает новый экземпляр CustomDomainService.
+// primaryHosts содержит собственные домены сервиса, которые нельзя
+// привязать к мультиссылке и для которых не выполняется поиск по Host.
+// Кэш поиска по Host хранит не больше cacheSize доменов
+func NewCustomDomainService(domainRepo repository.CustomDomainRepository, verifier *domains.Verifier, primaryHosts []string, cacheSize int, cacheTTL time.Duration, clock clock.Clock) *CustomDomainService {
+	primary := make(map[string]bool, len(primaryHosts))
+	for _, h := range primaryHosts {
+		if host, err := domains.NormalizeHostname(h); err == nil {
+			primary[host] = true
+		}
+	}
+
+	return &CustomDomainService{
+		domainRepo:   domainRepo,
+		verifier:     verifier,
+		primaryHosts: primary,
+		cacheTTL:     cacheTTL,
+		clock:        clock,
+		hosts:        lrucache.New[string, hostEntry](cacheSize, cacheTTL),
+	}
+}
+
+// GetDomains получает домены мультиссылки. Для неподтвержденных доменов
+// добавляется инструкция по подтверждению
+func (s *CustomDomainService) GetDomains(ctx context.Context, multiLinkID int64) ([]models.CustomDomainResponse, error) {
+	list, err := s.domainRepo.GetDomainsByMultiLinkID(ctx, multiLinkID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]models.CustomDomainResponse, 0, len(list))
+	for _, d := range list {
+		response = append(response, domainResponse(d))
+	}
+	return response, nil
+}
+
+// AddDomain привязывает домен к мультиссылке и выдает токен для подтверждения владения
+func (s *CustomDomainService) AddDomain(ctx context.Context, multiLinkID int64, hostname string) (models.CustomDomainResponse, error) {
+	host, err := domains.NormalizeHostname(hostname)
+	if err != nil {
+		return models.CustomDomainResponse{}, err
+	}
+	if s.primaryHosts[host] {
+		return models.CustomDomainResponse{}, fmt.Errorf("%w: домен принадлежит сервису", domains.ErrInvalidHostname)
+	}
+
+	token, err := newVerificationToken()
+	if err != nil {
+		return models.CustomDomainResponse{}, err
+	}
+
+	domain, err := s.domainRepo.CreateDomain(ctx, models.CustomDomain{MultiLinkID: multiLinkID, Hostname: host, Token: token})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return models.CustomDomainResponse{}, ErrDomainExists
+	}
+	if err != nil {
+		return models.CustomDomainResponse{}, err
+	}
+	return domainResponse(domain), nil
+}
+
+// VerifyDomain проверяет, что владелец домена разместил токен в DNS или по HTTP
+func (s *CustomDomainService) VerifyDomain(ctx context.Context, multiLinkID, domainID int64, method string) (models.CustomDomainResponse, error) {
+	domain, err := s.getDomain(ctx, multiLinkID, domainID)
+	if err != nil {
+		return models.CustomDomainResponse{}, err
+	}
+	if domain.VerifiedAt != nil {
+		return domainResponse(domain), nil
+	}
+
+	if err := s.verifier.Verify(ctx, method, domain.Hostname, domain.Token); err != nil {
+		return models.CustomDomainResponse{}, err
+	}
+
+	now := s.clock.Now()
+	err = s.domainRepo.MarkDomainVerified(ctx, domain.ID, method, now)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return models.CustomDomainResponse{}, ErrDomainTaken
+	}
+	if err != nil {
+		return models.CustomDomainResponse{}, err
+	}
+
+	domain.VerifiedAt = &now
+	domain.Method = method
+	s.forget(domain.Hostname)
+	return domainResponse(domain), nil
+}
+
+// DeleteDomain отвязывает домен от мультиссылки
+func (s *CustomDomainService) DeleteDomain(ctx context.Context, multiLinkID, domainID int64) error {
+	domain, err := s.getDomain(ctx, multiLinkID, domainID)
+	if err != nil {
+		return err
+	}
+	if err := s.domainRepo.DeleteDomain(ctx, domain.ID); err != nil {
+		return err
+	}
+	s.forget(domain.Hostname)
+	return nil
+}
+
+// ResolveHost находит slug мультиссылки по заголовку Host. Для собственных
+// доменов сервиса, IP-адресов и неизвестных доменов возвращается false.
+// Результаты кэшируются на cacheTTL, поэтому изменения на других экземплярах
+// API применяются с задержкой. Устаревшая запись удаляется при чтении
+func (s *CustomDomainService) ResolveHost(ctx context.Context, hostHeader string) (string, bool, error) {
+	host, err := domains.NormalizeHostname(hostHeader)
+	if err != nil || s.primaryHosts[host] {
+		return "", false, nil
+	}
+
+	now := s.clock.Now()
+	if entry, ok := s.hosts.Get(host); ok {
+		if now.Before(entry.expiresAt) {
+			return entry.slug, entry.found, nil
+		}
+		s.hosts.Delete(host)
+	}
+
+	slug, err := s.domainRepo.GetVerifiedSlugByHostname(ctx, host)
+	found := err == nil
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return "", false, err
+	}
+
+	s.hosts.Put(host, hostEntry{slug: slug, found: found, expiresAt: now.Add(s.cacheTTL)})
+	return slug, found, nil
+}
+
+// getDomain получает домен и проверяет, что он привязан к мультиссылке
+func (s *CustomDomainService) getDomain(ctx context.Context, multiLinkID, domainID int64) (models.CustomDomain, error) {
+	domain, err := s.domainRepo.GetDomainByID(ctx, domainID)
+	if err != nil {
+		return domain, err
+	}
+	if domain.MultiLinkID != multiLinkID {
+		return domain, repository.ErrNotFound
+	}
+	return domain, nil
+}
+
+func (s *CustomDomainService) forget(host string) {
+	s.hosts.Delete(host)
+}
+
+func domainResponse(domain models.CustomDomain) models.CustomDomainResponse {
+	response := models.CustomDomainResponse{CustomDomain: domain}
+	if domain.VerifiedAt == nil {
+		response.Verification = &models.DomainVerification{
+			TXTName:  domains.TXTPrefix + domain.Hostname,
+			TXTValue: domains.TXTValuePrefix + domain.Token,
+			HTTPURL:  "http://" + domain.Hostname + domains.WellKnownPath,
+			HTTPBody: domain.Token,
+		}
+	}
+	return response
+}
+
+func newVerificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
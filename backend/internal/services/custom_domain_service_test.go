@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// fakeDomainRepo сопоставляет подтвержденные домены со slug и считает обращения
+type fakeDomainRepo struct {
+	repository.CustomDomainRepository
+	slugs   map[string]string
+	err     error
+	lookups map[string]int
+}
+
+func (r *fakeDomainRepo) GetVerifiedSlugByHostname(_ context.Context, hostname string) (string, error) {
+	r.lookups[hostname]++
+	if r.err != nil {
+		return "", r.err
+	}
+	slug, ok := r.slugs[hostname]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	return slug, nil
+}
+
+func newResolveFixture(cacheSize int, now *time.Time) (*CustomDomainService, *fakeDomainRepo) {
+	repo := &fakeDomainRepo{
+		slugs:   map[string]string{"links.example.com": "demo", "go.example.org": "other"},
+		lookups: map[string]int{},
+	}
+	s := NewCustomDomainService(repo, nil, []string{"localhost", "multylink.test"}, cacheSize, time.Minute,
+		clock.Func(func() time.Time { return *now }))
+	return s, repo
+}
+
+func TestResolveHost(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	s, repo := newResolveFixture(10, &now)
+
+	tests := []struct {
+		host      string
+		wantSlug  string
+		wantFound bool
+	}{
+		{"links.example.com", "demo", true},
+		{"Links.Example.COM:443", "demo", true},
+		{"unknown.example.net", "", false},
+		{"localhost:8080", "", false},
+		{"MULTYLINK.test", "", false},
+		{"203.0.113.7", "", false},
+	}
+	for _, tt := range tests {
+		slug, found, err := s.ResolveHost(context.Background(), tt.host)
+		if err != nil || slug != tt.wantSlug || found != tt.wantFound {
+			t.Errorf("ResolveHost(%q) = %q, %v, %v, want %q, %v", tt.host, slug, found, err, tt.wantSlug, tt.wantFound)
+		}
+	}
+
+	// Собственные домены и IP-адреса не ищутся в базе, найденные и
+	// неизвестные домены запрашиваются один раз
+	want := map[string]int{"links.example.com": 1, "unknown.example.net": 1}
+	if len(repo.lookups) != len(want) || repo.lookups["links.example.com"] != 1 || repo.lookups["unknown.example.net"] != 1 {
+		t.Errorf("lookups = %v, want %v", repo.lookups, want)
+	}
+}
+
+func TestResolveHostDropsExpiredEntries(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	s, repo := newResolveFixture(10, &now)
+	ctx := context.Background()
+
+	_, _, _ = s.ResolveHost(ctx, "links.example.com")
+	now = now.Add(59 * time.Second)
+	_, _, _ = s.ResolveHost(ctx, "links.example.com")
+	if repo.lookups["links.example.com"] != 1 {
+		t.Fatalf("lookups within ttl = %d, want 1", repo.lookups["links.example.com"])
+	}
+
+	// Домен отвязан: после cacheTTL запись не используется
+	delete(repo.slugs, "links.example.com")
+	now = now.Add(time.Second)
+	if _, found, err := s.ResolveHost(ctx, "links.example.com"); err != nil || found {
+		t.Errorf("ResolveHost after ttl = %v, %v, want not found", found, err)
+	}
+	if repo.lookups["links.example.com"] != 2 {
+		t.Errorf("lookups after ttl = %d, want 2", repo.lookups["links.example.com"])
+	}
+}
+
+func TestResolveHostCacheIsBounded(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	s, repo := newResolveFixture(2, &now)
+	ctx := context.Background()
+
+	// Запросы с произвольными Host вытесняют давно не использованные записи
+	for _, host := range []string{"links.example.com", "a.random.test", "b.random.test"} {
+		_, _, _ = s.ResolveHost(ctx, host)
+	}
+	if slug, found, _ := s.ResolveHost(ctx, "links.example.com"); !found || slug != "demo" {
+		t.Fatalf("ResolveHost after eviction = %q, %v, want demo", slug, found)
+	}
+	if repo.lookups["links.example.com"] != 2 {
+		t.Errorf("lookups of evicted host = %d, want 2", repo.lookups["links.example.com"])
+	}
+
+	// Недавно использованная запись остается в кэше
+	_, _, _ = s.ResolveHost(ctx, "b.random.test")
+	if repo.lookups["b.random.test"] != 1 {
+		t.Errorf("lookups of recent host = %d, want 1", repo.lookups["b.random.test"])
+	}
+}
+
+func TestResolveHostDoesNotCacheErrors(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	s, repo := newResolveFixture(10, &now)
+	ctx := context.Background()
+
+	repo.err = errors.New("db is down")
+	if _, _, err := s.ResolveHost(ctx, "links.example.com"); !errors.Is(err, repo.err) {
+		t.Fatalf("ResolveHost error = %v, want %v", err, repo.err)
+	}
+
+	repo.err = nil
+	if slug, found, err := s.ResolveHost(ctx, "links.example.com"); err != nil || !found || slug != "demo" {
+		t.Errorf("ResolveHost after recovery = %q, %v, %v, want demo", slug, found, err)
+	}
+}
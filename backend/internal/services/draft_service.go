@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/signing"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// DraftService предоставляет методы для работы с черновиками. Изменения
+// мультиссылки и кнопок из личного кабинета сохраняются в черновик и попадают
+// на публичную страницу только после публикации
+type DraftService struct {
+	uow        repository.UnitOfWork
+	draftRepo  repository.DraftRepository
+	snapshots  snapshotStore
+	revisions  *RevisionService
+	signer     *signing.Signer
+	previewTTL time.Duration
+	buttons    buttonValidator
+}
+
+// NewDraftService создает новый экземпляр DraftService
+func NewDraftService(uow repository.UnitOfWork, multiLinkRepo repository.MultiLinkRepository, buttonRepo repository.ButtonRepository, metricsRepo repository.MetricsRepository, draftRepo repository.DraftRepository, revisions *RevisionService, signer *signing.Signer, previewTTL time.Duration, urlPolicy *urlpolicy.Policy, blocklist *blocklist.List, images *ImageService) *DraftService {
+	return &DraftService{
+		uow:        uow,
+		draftRepo:  draftRepo,
+		snapshots:  snapshotStore{multiLinkRepo: multiLinkRepo, buttonRepo: buttonRepo, metricsRepo: metricsRepo},
+		revisions:  revisions,
+		signer:     signer,
+		previewTTL: previewTTL,
+		buttons:    buttonValidator{urlPolicy: urlPolicy, blocklist: blocklist, images: images},
+	}
+}
+
+// GetDraft получает черновик мультиссылки. Если черновика нет, возвращается
+// копия опубликованного состояния и false
+func (s *DraftService) GetDraft(ctx context.Context, multiLinkID int64) (models.MultiLinkDraft, bool, error) {
+	draft, err := s.draftRepo.GetDraft(ctx, multiLinkID)
+	if err == nil {
+		return draft, true, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return draft, false, err
+	}
+
+	snapshot, err := s.snapshots.load(ctx, multiLinkID)
+	if err != nil {
+		return models.MultiLinkDraft{}, false, err
+	}
+	return models.MultiLinkDraft{
+		MultiLinkID: multiLinkID,
+		Snapshot:    snapshot,
+		CreatedAt:   snapshot.MultiLink.UpdatedAt,
+		UpdatedAt:   snapshot.MultiLink.UpdatedAt,
+	}, false, nil
+}
+
+// edit применяет fn к черновику в транзакции. Если черновика нет, он
+// создается из опубликованного состояния. Мультиссылка блокируется на время
+// транзакции: строки черновика может еще не быть, и блокировка только ее
+// не защитила бы от потери параллельного изменения
+func (s *DraftService) edit(ctx context.Context, multiLinkID int64, fn func(snapshot *models.RevisionSnapshot) error) (models.RevisionSnapshot, error) {
+	var snapshot models.RevisionSnapshot
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.snapshots.lock(ctx, multiLinkID); err != nil {
+			return err
+		}
+
+		draft, _, err := s.GetDraft(ctx, multiLinkID)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(&draft.Snapshot); err != nil {
+			return err
+		}
+		sort.SliceStable(draft.Snapshot.Buttons, func(i, j int) bool {
+			return draft.Snapshot.Buttons[i].Position < draft.Snapshot.Buttons[j].Position
+		})
+
+		draft.UpdatedAt = time.Now().UTC()
+		if err := s.draftRepo.SaveDraft(ctx, draft); err != nil {
+			return err
+		}
+		snapshot = draft.Snapshot
+		return nil
+	})
+	return snapshot, err
+}
+
+// UpdateMultiLink применяет запрос на обновление мультиссылки к черновику
+func (s *DraftService) UpdateMultiLink(ctx context.Context, multiLinkID int64, req models.UpdateMultiLinkRequest) (models.MultiLink, error) {
+	snapshot, err := s.edit(ctx, multiLinkID, func(snapshot *models.RevisionSnapshot) error {
+		multiLink := &snapshot.MultiLink
+
+		if req.Title != "" {
+			multiLink.Title = req.Title
+		}
+
+		multiLink.Description = req.Description
+
+		if req.Slug != "" && req.Slug != multiLink.Slug {
+			// Проверка уникальности нового slug. При публикации она повторяется
+			exists, err := s.snapshots.multiLinkRepo.CheckSlugExists(ctx, req.Slug)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return ErrSlugTaken
+			}
+			multiLink.Slug = req.Slug
+		}
+
+		if err := ValidatePublishWindow(req.PublishAt, req.UnpublishAt); err != nil {
+			return err
+		}
+
+		multiLink.IsActive = req.IsActive
+		multiLink.PublishAt = req.PublishAt
+		multiLink.UnpublishAt = req.UnpublishAt
+		multiLink.Sensitive, multiLink.SensitiveReason = NormalizeSensitive(req.Sensitive, req.SensitiveReason)
+		multiLink.UpdatedAt = time.Now()
+		return nil
+	})
+	return snapshot.MultiLink, err
+}
+
+// CreateButton добавляет кнопку в черновик. До публикации кнопка имеет
+// отрицательный временный ID
+func (s *DraftService) CreateButton(ctx context.Context, multiLinkID int64, req models.CreateLinkButtonRequest) (models.LinkButton, error) {
+	var button models.LinkButton
+
+	visibleFrom, visibleUntil, err := ParseButtonWindow(req.VisibleFrom, req.VisibleUntil, req.Timezone)
+	if err != nil {
+		return button, err
+	}
+	if err := ValidateButtonLimits(req.ExhaustedAction, req.FallbackURL); err != nil {
+		return button, err
+	}
+	pageTheme, err := s.pageTheme(ctx, multiLinkID)
+	if err != nil {
+		return button, err
+	}
+
+	_, err = s.edit(ctx, multiLinkID, func(snapshot *models.RevisionSnapshot) error {
+		var minID int64
+		for _, b := range snapshot.Buttons {
+			if b.ID < minID {
+				minID = b.ID
+			}
+		}
+
+		// Если позиция не указана, устанавливаем последнюю
+		if req.Position == 0 {
+			req.Position = len(snapshot.Buttons) + 1
+		}
+
+		button = models.LinkButton{
+			ID:              minID - 1,
+			MultiLinkID:     multiLinkID,
+			Kind:            req.Kind,
+			Title:           req.Title,
+			URL:             req.URL,
+			Icon:            req.Icon,
+			Color:           req.Color,
+			Position:        req.Position,
+			IsActive:        req.IsActive,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+			VisibleFrom:     visibleFrom,
+			VisibleUntil:    visibleUntil,
+			Timezone:        req.Timezone,
+			MaxClicks:       req.MaxClicks,
+			RemainingClicks: req.MaxClicks,
+			ExpiresAt:       req.ExpiresAt,
+			FallbackURL:     req.FallbackURL,
+			ExhaustedAction: req.ExhaustedAction,
+		}
+		button.Sensitive, button.SensitiveReason = NormalizeSensitive(req.Sensitive, req.SensitiveReason)
+		if err := s.buttons.normalize(&button, pageTheme); err != nil {
+			return err
+		}
+		snapshot.Buttons = append(snapshot.Buttons, button)
+		return nil
+	})
+	return button, err
+}
+
+// UpdateButton применяет запрос на обновление кнопки к черновику
+func (s *DraftService) UpdateButton(ctx context.Context, multiLinkID, buttonID int64, req models.UpdateLinkButtonRequest) (models.LinkButton, error) {
+	var button models.LinkButton
+
+	visibleFrom, visibleUntil, err := ParseButtonWindow(req.VisibleFrom, req.VisibleUntil, req.Timezone)
+	if err != nil {
+		return button, err
+	}
+	if err := ValidateButtonLimits(req.ExhaustedAction, req.FallbackURL); err != nil {
+		return button, err
+	}
+	pageTheme, err := s.pageTheme(ctx, multiLinkID)
+	if err != nil {
+		return button, err
+	}
+
+	_, err = s.edit(ctx, multiLinkID, func(snapshot *models.RevisionSnapshot) error {
+		i := draftButtonIndex(snapshot, buttonID)
+		if i < 0 {
+			return repository.ErrNotFound
+		}
+		b := &snapshot.Buttons[i]
+
+		if req.Kind != "" {
+			b.Kind = req.Kind
+		}
+
+		if req.Title != "" {
+			b.Title = req.Title
+		}
+
+		if req.URL != "" {
+			b.URL = req.URL
+		}
+
+		b.Icon = req.Icon
+		b.Color = req.Color
+
+		if req.Position != 0 {
+			b.Position = req.Position
+		}
+
+		b.IsActive = req.IsActive
+		b.VisibleFrom = visibleFrom
+		b.VisibleUntil = visibleUntil
+		b.Timezone = req.Timezone
+		b.RemainingClicks = remainingClicks(b.MaxClicks, b.RemainingClicks, req.MaxClicks)
+		b.MaxClicks = req.MaxClicks
+		b.ExpiresAt = req.ExpiresAt
+		b.FallbackURL = req.FallbackURL
+		b.ExhaustedAction = req.ExhaustedAction
+		b.Sensitive, b.SensitiveReason = NormalizeSensitive(req.Sensitive, req.SensitiveReason)
+		b.UpdatedAt = time.Now()
+
+		if err := s.buttons.normalize(b, pageTheme); err != nil {
+			return err
+		}
+
+		button = *b
+		return nil
+	})
+	return button, err
+}
+
+// pageTheme возвращает оформление опубликованной страницы: оно задается
+// отдельно от черновика
+func (s *DraftService) pageTheme(ctx context.Context, multiLinkID int64) (models.Theme, error) {
+	multiLink, err := s.snapshots.multiLinkRepo.GetMultiLinkByID(ctx, multiLinkID)
+	if err != nil {
+		return models.Theme{}, err
+	}
+	return ResolveTheme(multiLink), nil
+}
+
+// DeleteButton удаляет кнопку из черновика. После публикации кнопка
+// перемещается в корзину
+func (s *DraftService) DeleteButton(ctx context.Context, multiLinkID, buttonID int64) error {
+	_, err := s.edit(ctx, multiLinkID, func(snapshot *models.RevisionSnapshot) error {
+		i := draftButtonIndex(snapshot, buttonID)
+		if i < 0 {
+			return repository.ErrNotFound
+		}
+		snapshot.Buttons = append(snapshot.Buttons[:i], snapshot.Buttons[i+1:]...)
+		return nil
+	})
+	return err
+}
+
+// ReorderButtons обновляет позиции кнопок в черновике
+func (s *DraftService) ReorderButtons(ctx context.Context, multiLinkID int64, order []models.ButtonPosition) error {
+	_, err := s.edit(ctx, multiLinkID, func(snapshot *models.RevisionSnapshot) error {
+		for _, item := range order {
+			i := draftButtonIndex(snapshot, item.ID)
+			if i < 0 {
+				return repository.ErrNotFound
+			}
+			snapshot.Buttons[i].Position = item.Position
+		}
+		return nil
+	})
+	return err
+}
+
+// Publish атомарно применяет черновик к опубликованной мультиссылке,
+// удаляет черновик и сохраняет ревизию
+func (s *DraftService) Publish(ctx context.Context, multiLinkID int64) (models.MultiLinkRevision, error) {
+	var revision models.MultiLinkRevision
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.snapshots.lock(ctx, multiLinkID); err != nil {
+			return err
+		}
+
+		draft, err := s.draftRepo.GetDraft(ctx, multiLinkID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNoDraft
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.snapshots.apply(ctx, draft.Snapshot); err != nil {
+			return err
+		}
+		if err := s.draftRepo.DeleteDraft(ctx, multiLinkID); err != nil {
+			return err
+		}
+
+		revision, err = s.revisions.Record(ctx, multiLinkID, models.RevisionActionPublish)
+		return err
+	})
+	return revision, err
+}
+
+// DiscardDraft удаляет черновик мультиссылки
+func (s *DraftService) DiscardDraft(ctx context.Context, multiLinkID int64) error {
+	return s.draftRepo.DeleteDraft(ctx, multiLinkID)
+}
+
+// CreatePreviewToken выпускает подписанный токен предпросмотра черновика,
+// которым можно поделиться без авторизации
+func (s *DraftService) CreatePreviewToken(multiLinkID int64) (string, time.Time) {
+	expiresAt := time.Now().Add(s.previewTTL)
+	return s.signer.Sign(strconv.FormatInt(multiLinkID, 10), expiresAt), expiresAt
+}
+
+// GetPreview получает черновик по токену предпросмотра в том виде, в котором
+// он будет опубликован: только активные кнопки в порядке отображения
+func (s *DraftService) GetPreview(ctx context.Context, token string) (models.MultiLinkResponse, error) {
+	subject, err := s.signer.Verify(token, time.Now())
+	if err != nil {
+		return models.MultiLinkResponse{}, err
+	}
+	multiLinkID, err := strconv.ParseInt(subject, 10, 64)
+	if err != nil {
+		return models.MultiLinkResponse{}, signing.ErrInvalidToken
+	}
+
+	// Черновик удаленной мультиссылки недоступен
+	multiLink, err := s.snapshots.multiLinkRepo.GetMultiLinkByID(ctx, multiLinkID)
+	if err != nil {
+		return models.MultiLinkResponse{}, err
+	}
+
+	draft, _, err := s.GetDraft(ctx, multiLinkID)
+	if err != nil {
+		return models.MultiLinkResponse{}, err
+	}
+
+	buttons := make([]models.LinkButton, 0, len(draft.Snapshot.Buttons))
+	for _, b := range draft.Snapshot.Buttons {
+		if b.IsActive {
+			buttons = append(buttons, b)
+		}
+	}
+
+	pageTheme := ResolveTheme(multiLink)
+	return models.MultiLinkResponse{
+		MultiLink: draft.Snapshot.MultiLink,
+		Buttons:   buttons,
+		Theme:     &pageTheme,
+	}, nil
+}
+
+// draftButtonIndex возвращает индекс кнопки в черновике или -1
+func draftButtonIndex(snapshot *models.RevisionSnapshot, buttonID int64) int {
+	for i, b := range snapshot.Buttons {
+		if b.ID == buttonID {
+			return i
+		}
+	}
+	return -1
+}
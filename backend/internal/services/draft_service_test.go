@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/unfurl"
+)
+
+func newTestDraftService(f fakeRepos) *DraftService {
+	return NewDraftService(f.uow, f.multiLink, f.button, f.metrics, f.draft, f.revisionService(), nil, time.Hour,
+		f.urlPolicy, f.blocklist, f.images)
+}
+
+func TestCreateButtonDetectsIconForKnownDomain(t *testing.T) {
+	f := newFakeRepos()
+	f.store.multiLinks[1] = models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Demo", IsActive: true}
+	s := newTestDraftService(f)
+
+	req := models.CreateLinkButtonRequest{Title: "Канал", URL: "https://www.youtube.com/@demo", IsActive: true}
+	button, err := s.CreateButton(context.Background(), 1, req)
+	if err != nil {
+		t.Fatalf("CreateButton: %v", err)
+	}
+	if button.Icon != "youtube" {
+		t.Errorf("button icon = %q, want %q", button.Icon, "youtube")
+	}
+
+	draft := f.store.drafts[1]
+	if len(draft.Snapshot.Buttons) != 1 || draft.Snapshot.Buttons[0].Icon != "youtube" {
+		t.Errorf("draft buttons = %+v, want one button with icon youtube", draft.Snapshot.Buttons)
+	}
+}
+
+func TestCreateButtonForUnknownDomainHasNoIcon(t *testing.T) {
+	f := newFakeRepos()
+	f.store.multiLinks[1] = models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Demo", IsActive: true}
+	s := newTestDraftService(f)
+
+	req := models.CreateLinkButtonRequest{Title: "Сайт", URL: "https://example.com/", IsActive: true}
+	button, err := s.CreateButton(context.Background(), 1, req)
+	if err != nil {
+		t.Fatalf("CreateButton: %v", err)
+	}
+	if button.Icon != "" {
+		t.Errorf("button icon = %q, want none", button.Icon)
+	}
+}
+
+func TestCreateButtonRejectsUnknownIcon(t *testing.T) {
+	f := newFakeRepos()
+	f.store.multiLinks[1] = models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Demo", IsActive: true}
+	s := newTestDraftService(f)
+
+	for _, icon := range []string{"unknown", "../youtube", "https://cdn.example.com/icon.png"} {
+		req := models.CreateLinkButtonRequest{Title: "Сайт", URL: "https://example.com/", Icon: icon, IsActive: true}
+		if _, err := s.CreateButton(context.Background(), 1, req); !errors.Is(err, ErrInvalidIcon) {
+			t.Errorf("CreateButton with icon %q error = %v, want %v", icon, err, ErrInvalidIcon)
+		}
+	}
+	if _, ok := f.store.drafts[1]; ok {
+		t.Error("rejected buttons must not be saved to the draft")
+	}
+}
+
+func TestCreateButtonAfterPrefillKeepsBuiltinIcon(t *testing.T) {
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<html><head><title>Канал</title>` +
+			`<link rel="icon" href="/favicon.png"><meta property="og:image" content="/cover.png"></head></html>`))
+	}))
+	defer page.Close()
+
+	// Запросы к youtube.com направляются на локальный сервер
+	var dialer net.Dialer
+	client := unfurl.New(unfurl.Options{
+		Timeout: time.Second, MaxBodyBytes: 1 << 20, MaxRedirects: 3, CacheSize: 8, CacheTTL: time.Minute,
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, page.Listener.Addr().String())
+		},
+	})
+
+	f := newFakeRepos()
+	f.store.multiLinks[1] = models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Demo", IsActive: true}
+	s := newTestDraftService(f)
+
+	req := models.CreateLinkButtonRequest{URL: "http://www.youtube.com/@demo", IsActive: true}
+	NewUnfurlService(client, f.urlPolicy, f.blocklist).Prefill(context.Background(), &req)
+	if req.Title != "Канал" {
+		t.Errorf("prefilled title = %q, want %q", req.Title, "Канал")
+	}
+	if req.Icon != "" {
+		t.Fatalf("Prefill set icon %q, want empty", req.Icon)
+	}
+
+	button, err := s.CreateButton(context.Background(), 1, req)
+	if err != nil {
+		t.Fatalf("CreateButton: %v", err)
+	}
+	if button.Icon != "youtube" {
+		t.Errorf("button icon = %q, want %q", button.Icon, "youtube")
+	}
+}
+
+func TestDraftEditLocksMultiLinkBeforeReadingDraft(t *testing.T) {
+	f := newFakeRepos()
+	f.store.multiLinks[1] = models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Demo", IsActive: true}
+	s := newTestDraftService(f)
+
+	req := models.CreateLinkButtonRequest{Title: "Сайт", URL: "https://example.com", IsActive: true}
+	if _, err := s.CreateButton(context.Background(), 1, req); err != nil {
+		t.Fatalf("CreateButton: %v", err)
+	}
+	if len(f.store.calls) < 2 || f.store.calls[0] != "LockMultiLink" || f.store.calls[1] != "GetDraft" {
+		t.Errorf("calls = %v, want LockMultiLink before GetDraft", f.store.calls)
+	}
+}
+
+func TestDraftEditOfDeletedMultiLinkFails(t *testing.T) {
+	f := newFakeRepos()
+	deletedAt := time.Now()
+	f.store.multiLinks[1] = models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Demo", DeletedAt: &deletedAt}
+	s := newTestDraftService(f)
+
+	_, err := s.edit(context.Background(), 1, func(*models.RevisionSnapshot) error { return nil })
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("edit error = %v, want ErrNotFound", err)
+	}
+	if _, ok := f.store.drafts[1]; ok {
+		t.Error("draft must not be created for a deleted multilink")
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import "errors"
+
+var (
+	// ErrSlugTaken возвращается, если slug восстанавливаемой мультиссылки уже занят
+	ErrSlugTaken = errors.New("slug уже используется")
+
+	// ErrParentDeleted возвращается при восстановлении кнопки, мультиссылка которой находится в корзине
+	ErrParentDeleted = errors.New("мультиссылка находится в корзине")
+
+	// ErrNoDraft возвращается при публикации, если у мультиссылки нет черновика
+	ErrNoDraft = errors.New("черновик не найден")
+
+	// ErrInvalidButtonLimits возвращается при некорректных настройках лимита кнопки
+	ErrInvalidButtonLimits = errors.New("некорректные ограничения кнопки")
+
+	// ErrInvalidButtonPayload возвращается, если содержимое кнопки не соответствует ее типу
+	ErrInvalidButtonPayload = errors.New("некорректное содержимое кнопки")
+
+	// ErrBlockedURL возвращается, если ссылка кнопки находится в списке блокировки
+	ErrBlockedURL = errors.New("ссылка находится в списке блокировки")
+
+	// ErrWrongPassword возвращается, если посетитель ввел неверный пароль страницы
+	ErrWrongPassword = errors.New("неверный пароль")
+
+	// ErrDomainExists возвращается при повторной привязке домена к той же мультиссылке
+	ErrDomainExists = errors.New("домен уже привязан к мультиссылке")
+
+	// ErrDomainTaken возвращается, если домен уже подтвержден для другой мультиссылки
+	ErrDomainTaken = errors.New("домен уже используется другой мультиссылкой")
+
+	// ErrTooManyAttempts возвращается, если для страницы исчерпан лимит попыток ввода пароля
+	ErrTooManyAttempts = errors.New("слишком много попыток ввода пароля")
+
+	// ErrInvalidIcon возвращается, если значок кнопки не из встроенного набора
+	// и не загруженное в сервис изображение
+	ErrInvalidIcon = errors.New("неизвестный значок")
+)
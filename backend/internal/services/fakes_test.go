@@ -0,0 +1,397 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// memStore хранит состояние фейковых репозиториев. fail задает ошибки,
+// которые вернут методы с указанными именами
+type memStore struct {
+	multiLinks map[int64]models.MultiLink
+	buttons    map[int64]models.LinkButton
+	drafts     map[int64]models.MultiLinkDraft
+	revisions  map[int64][]models.MultiLinkRevision
+	metrics    map[int64]int
+	clicks     map[int64]models.LinkMetrics // метрики кнопок по ID кнопки
+	events     []models.ClickEvent
+	nextID     int64
+	fail       map[string]error
+	// calls перечисляет вызовы блокировки и чтения черновика по порядку
+	calls []string
+	// mu сериализует транзакции fakeUnitOfWork
+	mu sync.Mutex
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		multiLinks: map[int64]models.MultiLink{},
+		buttons:    map[int64]models.LinkButton{},
+		drafts:     map[int64]models.MultiLinkDraft{},
+		revisions:  map[int64][]models.MultiLinkRevision{},
+		metrics:    map[int64]int{},
+		clicks:     map[int64]models.LinkMetrics{},
+		fail:       map[string]error{},
+	}
+}
+
+func (m *memStore) err(method string) error {
+	return m.fail[method]
+}
+
+// state и restore копируют данные хранилища для отката транзакции
+type memState struct {
+	multiLinks map[int64]models.MultiLink
+	buttons    map[int64]models.LinkButton
+	drafts     map[int64]models.MultiLinkDraft
+	revisions  map[int64][]models.MultiLinkRevision
+	metrics    map[int64]int
+	clicks     map[int64]models.LinkMetrics
+	events     []models.ClickEvent
+	nextID     int64
+}
+
+func (m *memStore) state() memState {
+	return memState{
+		multiLinks: maps.Clone(m.multiLinks),
+		buttons:    maps.Clone(m.buttons),
+		drafts:     maps.Clone(m.drafts),
+		revisions:  maps.Clone(m.revisions),
+		metrics:    maps.Clone(m.metrics),
+		clicks:     maps.Clone(m.clicks),
+		events:     slices.Clone(m.events),
+		nextID:     m.nextID,
+	}
+}
+
+func (m *memStore) restore(s memState) {
+	m.multiLinks = s.multiLinks
+	m.buttons = s.buttons
+	m.drafts = s.drafts
+	m.revisions = s.revisions
+	m.metrics = s.metrics
+	m.clicks = s.clicks
+	m.events = s.events
+	m.nextID = s.nextID
+}
+
+// fakeUnitOfWork откатывает изменения хранилища, если fn вернула ошибку.
+// Транзакции выполняются по очереди, как при блокировке строк в PostgreSQL
+type fakeUnitOfWork struct {
+	store *memStore
+}
+
+type fakeTxKey struct{}
+
+func (u fakeUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(fakeTxKey{}) != nil {
+		return fn(ctx)
+	}
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+	saved := u.store.state()
+	if err := fn(context.WithValue(ctx, fakeTxKey{}, true)); err != nil {
+		u.store.restore(saved)
+		return err
+	}
+	return nil
+}
+
+// Фейковые репозитории встраивают интерфейс: вызов нереализованного метода
+// завершится паникой и укажет на недостающую часть фейка
+
+type fakeMultiLinkRepo struct {
+	repository.MultiLinkRepository
+	store *memStore
+}
+
+func (r fakeMultiLinkRepo) GetMultiLinkByID(_ context.Context, id int64) (models.MultiLink, error) {
+	multiLink, ok := r.store.multiLinks[id]
+	if !ok || multiLink.DeletedAt != nil {
+		return models.MultiLink{}, repository.ErrNotFound
+	}
+	return multiLink, nil
+}
+
+func (r fakeMultiLinkRepo) LockMultiLink(ctx context.Context, id int64) error {
+	r.store.calls = append(r.store.calls, "LockMultiLink")
+	_, err := r.GetMultiLinkByID(ctx, id)
+	return err
+}
+
+func (r fakeMultiLinkRepo) UpdateMultiLink(_ context.Context, multiLink models.MultiLink) error {
+	if err := r.store.err("UpdateMultiLink"); err != nil {
+		return err
+	}
+	r.store.multiLinks[multiLink.ID] = multiLink
+	return nil
+}
+
+func (r fakeMultiLinkRepo) CheckSlugExists(_ context.Context, slug string) (bool, error) {
+	for _, multiLink := range r.store.multiLinks {
+		if multiLink.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r fakeMultiLinkRepo) SoftDeleteMultiLink(_ context.Context, id int64, deletedAt time.Time) error {
+	if err := r.store.err("SoftDeleteMultiLink"); err != nil {
+		return err
+	}
+	multiLink, ok := r.store.multiLinks[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	multiLink.DeletedAt = &deletedAt
+	r.store.multiLinks[id] = multiLink
+	return nil
+}
+
+func (r fakeMultiLinkRepo) DeleteMultiLink(_ context.Context, id int64) error {
+	if err := r.store.err("DeleteMultiLink"); err != nil {
+		return err
+	}
+	delete(r.store.multiLinks, id)
+	return nil
+}
+
+type fakeButtonRepo struct {
+	repository.ButtonRepository
+	store *memStore
+}
+
+func (r fakeButtonRepo) GetButtonsByMultiLinkID(_ context.Context, multiLinkID int64) ([]models.LinkButton, error) {
+	var buttons []models.LinkButton
+	for _, button := range r.store.buttons {
+		if button.MultiLinkID == multiLinkID && button.DeletedAt == nil {
+			buttons = append(buttons, button)
+		}
+	}
+	sort.Slice(buttons, func(i, j int) bool { return buttons[i].Position < buttons[j].Position })
+	return buttons, nil
+}
+
+func (r fakeButtonRepo) UpdateButton(_ context.Context, button models.LinkButton) error {
+	if err := r.store.err("UpdateButton"); err != nil {
+		return err
+	}
+	if _, ok := r.store.buttons[button.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	r.store.buttons[button.ID] = button
+	return nil
+}
+
+// ConsumeClick повторяет условие UPDATE репозитория PostgreSQL
+func (r fakeButtonRepo) ConsumeClick(_ context.Context, id int64, now time.Time) (bool, error) {
+	if err := r.store.err("ConsumeClick"); err != nil {
+		return false, err
+	}
+	button, ok := r.store.buttons[id]
+	if !ok || button.DeletedAt != nil {
+		return false, nil
+	}
+	if button.RemainingClicks != nil && *button.RemainingClicks <= 0 {
+		return false, nil
+	}
+	if button.ExpiresAt != nil && !button.ExpiresAt.After(now) {
+		return false, nil
+	}
+	if button.RemainingClicks != nil {
+		remaining := *button.RemainingClicks - 1
+		button.RemainingClicks = &remaining
+	}
+	r.store.buttons[id] = button
+	return true, nil
+}
+
+func (r fakeButtonRepo) SoftDeleteButtonsByMultiLinkID(_ context.Context, multiLinkID int64, deletedAt time.Time) error {
+	if err := r.store.err("SoftDeleteButtonsByMultiLinkID"); err != nil {
+		return err
+	}
+	for id, button := range r.store.buttons {
+		if button.MultiLinkID == multiLinkID && button.DeletedAt == nil {
+			button.DeletedAt = &deletedAt
+			r.store.buttons[id] = button
+		}
+	}
+	return nil
+}
+
+func (r fakeButtonRepo) DeleteButtonsByMultiLinkID(_ context.Context, multiLinkID int64) error {
+	if err := r.store.err("DeleteButtonsByMultiLinkID"); err != nil {
+		return err
+	}
+	for id, button := range r.store.buttons {
+		if button.MultiLinkID == multiLinkID {
+			delete(r.store.buttons, id)
+		}
+	}
+	return nil
+}
+
+type fakeMetricsRepo struct {
+	repository.MetricsRepository
+	store *memStore
+}
+
+func (r fakeMetricsRepo) DeleteClickEventsByMultiLinkID(_ context.Context, multiLinkID int64) error {
+	if err := r.store.err("DeleteClickEventsByMultiLinkID"); err != nil {
+		return err
+	}
+	delete(r.store.metrics, multiLinkID)
+	return nil
+}
+
+func (r fakeMetricsRepo) CreateClickEvent(_ context.Context, event models.ClickEvent) (int64, error) {
+	if err := r.store.err("CreateClickEvent"); err != nil {
+		return 0, err
+	}
+	r.store.nextID++
+	event.ID = r.store.nextID
+	r.store.events = append(r.store.events, event)
+	return event.ID, nil
+}
+
+func (r fakeMetricsRepo) GetMetricsByButtonID(_ context.Context, buttonID int64) (models.LinkMetrics, error) {
+	metrics, ok := r.store.clicks[buttonID]
+	if !ok {
+		return models.LinkMetrics{}, repository.ErrNotFound
+	}
+	return metrics, nil
+}
+
+func (r fakeMetricsRepo) CreateLinkMetrics(_ context.Context, metrics models.LinkMetrics) (int64, error) {
+	r.store.nextID++
+	metrics.ID = r.store.nextID
+	r.store.clicks[metrics.LinkButtonID] = metrics
+	return metrics.ID, nil
+}
+
+func (r fakeMetricsRepo) UpdateLinkMetrics(_ context.Context, metrics models.LinkMetrics) error {
+	if err := r.store.err("UpdateLinkMetrics"); err != nil {
+		return err
+	}
+	r.store.clicks[metrics.LinkButtonID] = metrics
+	return nil
+}
+
+func (r fakeMetricsRepo) DeleteMetricsByMultiLinkID(_ context.Context, multiLinkID int64) error {
+	return r.store.err("DeleteMetricsByMultiLinkID")
+}
+
+type fakeDraftRepo struct {
+	store *memStore
+}
+
+var _ repository.DraftRepository = fakeDraftRepo{}
+
+func (r fakeDraftRepo) GetDraft(_ context.Context, multiLinkID int64) (models.MultiLinkDraft, error) {
+	r.store.calls = append(r.store.calls, "GetDraft")
+	draft, ok := r.store.drafts[multiLinkID]
+	if !ok {
+		return models.MultiLinkDraft{}, repository.ErrNotFound
+	}
+	return draft, nil
+}
+
+func (r fakeDraftRepo) SaveDraft(_ context.Context, draft models.MultiLinkDraft) error {
+	if err := r.store.err("SaveDraft"); err != nil {
+		return err
+	}
+	r.store.drafts[draft.MultiLinkID] = draft
+	return nil
+}
+
+func (r fakeDraftRepo) DeleteDraft(_ context.Context, multiLinkID int64) error {
+	if err := r.store.err("DeleteDraft"); err != nil {
+		return err
+	}
+	delete(r.store.drafts, multiLinkID)
+	return nil
+}
+
+type fakeRevisionRepo struct {
+	store *memStore
+}
+
+var _ repository.RevisionRepository = fakeRevisionRepo{}
+
+func (r fakeRevisionRepo) CreateRevision(_ context.Context, revision models.MultiLinkRevision) (models.MultiLinkRevision, error) {
+	if err := r.store.err("CreateRevision"); err != nil {
+		return models.MultiLinkRevision{}, err
+	}
+	r.store.nextID++
+	revision.ID = r.store.nextID
+	revision.Revision = len(r.store.revisions[revision.MultiLinkID]) + 1
+	revision.CreatedAt = time.Now().UTC()
+	r.store.revisions[revision.MultiLinkID] = append(r.store.revisions[revision.MultiLinkID], revision)
+	return revision, nil
+}
+
+func (r fakeRevisionRepo) GetRevisionsByMultiLinkID(_ context.Context, multiLinkID int64) ([]models.MultiLinkRevision, error) {
+	return r.store.revisions[multiLinkID], nil
+}
+
+func (r fakeRevisionRepo) GetRevision(_ context.Context, multiLinkID int64, number int) (models.MultiLinkRevision, error) {
+	for _, revision := range r.store.revisions[multiLinkID] {
+		if revision.Revision == number {
+			return revision, nil
+		}
+	}
+	return models.MultiLinkRevision{}, repository.ErrNotFound
+}
+
+func (r fakeRevisionRepo) DeleteRevisionsByMultiLinkID(_ context.Context, multiLinkID int64) error {
+	if err := r.store.err("DeleteRevisionsByMultiLinkID"); err != nil {
+		return err
+	}
+	delete(r.store.revisions, multiLinkID)
+	return nil
+}
+
+// fakeRepos собирает фейковые репозитории поверх одного хранилища и общие
+// для сервисов политику ссылок, список блокировки и сервис изображений
+type fakeRepos struct {
+	store     *memStore
+	uow       fakeUnitOfWork
+	multiLink fakeMultiLinkRepo
+	button    fakeButtonRepo
+	metrics   fakeMetricsRepo
+	draft     fakeDraftRepo
+	revision  fakeRevisionRepo
+	urlPolicy *urlpolicy.Policy
+	blocklist *blocklist.List
+	images    *ImageService
+}
+
+func newFakeRepos() fakeRepos {
+	store := newMemStore()
+	return fakeRepos{
+		store:     store,
+		uow:       fakeUnitOfWork{store: store},
+		multiLink: fakeMultiLinkRepo{store: store},
+		button:    fakeButtonRepo{store: store},
+		metrics:   fakeMetricsRepo{store: store},
+		draft:     fakeDraftRepo{store: store},
+		revision:  fakeRevisionRepo{store: store},
+		urlPolicy: urlpolicy.New([]string{"http", "https", "mailto", "tel", "sms"}, 2048),
+		blocklist: blocklist.New(""),
+		images:    NewImageService(nil, "https://api.example.com"),
+	}
+}
+
+func (f fakeRepos) revisionService() *RevisionService {
+	return NewRevisionService(f.uow, f.multiLink, f.button, f.metrics, f.revision, f.urlPolicy, f.blocklist, f.images)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"mvp_multylink/backend/internal/blobstore"
+	"mvp_multylink/backend/internal/images"
+	"mvp_multylink/backend/internal/models"
+)
+
+// defaultImageVariant — размер, адрес которого возвращается как основной
+const defaultImageVariant = "md"
+
+// imagesPath — путь публичного API, по которому отдаются изображения
+const imagesPath = "/api/public/images/"
+
+// ImageService обрабатывает загруженные изображения и сохраняет их в
+// хранилище. Имена объектов строятся по хэшу исходного файла, поэтому
+// повторная загрузка того же файла не создает новых объектов, а содержимое
+// по адресу никогда не меняется
+type ImageService struct {
+	store      blobstore.BlobStore
+	apiBaseURL string
+}
+
+// NewImageService создает новый экземпляр ImageService. Адреса изображений
+// строятся от apiBaseURL
+func NewImageService(store blobstore.BlobStore, apiBaseURL string) *ImageService {
+	return &ImageService{
+		store:      store,
+		apiBaseURL: strings.TrimSuffix(apiBaseURL, "/"),
+	}
+}
+
+// Upload обрабатывает изображение для назначения kind и сохраняет все его размеры
+func (s *ImageService) Upload(ctx context.Context, kind string, data []byte) (models.UploadedImage, error) {
+	encoded, err := images.Process(data, kind)
+	if err != nil {
+		return models.UploadedImage{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:16])
+	uploaded := models.UploadedImage{Kind: kind, Variants: make(map[string]string, len(encoded))}
+	for _, e := range encoded {
+		key := kind + "/" + name + "_" + e.Variant + e.Ext
+		if err := s.store.Put(ctx, key, e.ContentType, e.Data); err != nil {
+			return models.UploadedImage{}, err
+		}
+		uploaded.Variants[e.Variant] = s.URL(key)
+	}
+	uploaded.URL = uploaded.Variants[defaultImageVariant]
+	return uploaded, nil
+}
+
+// Open открывает сохраненное изображение для отдачи посетителю
+func (s *ImageService) Open(ctx context.Context, key string) (blobstore.Object, error) {
+	return s.store.Get(ctx, key)
+}
+
+// URL возвращает публичный адрес изображения с ключом key
+func (s *ImageService) URL(key string) string {
+	return s.apiBaseURL + imagesPath + key
+}
+
+// IsImageURL проверяет, что ссылка ведет на изображение, загруженное в сервис
+func (s *ImageService) IsImageURL(rawURL string) bool {
+	key, ok := strings.CutPrefix(rawURL, s.apiBaseURL+imagesPath)
+	return ok && blobstore.ValidKey(key)
+}
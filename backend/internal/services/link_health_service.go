@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/linkcheck"
+	"mvp_multylink/backend/internal/logging"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// linkChecksLimit ограничивает число проверок в истории кнопки
+const linkChecksLimit = 50
+
+// LinkHealthOptions задает параметры фоновой проверки ссылок
+type LinkHealthOptions struct {
+	// Concurrency ограничивает число хостов, проверяемых одновременно
+	Concurrency int
+	// HostDelay задает паузу между запросами к одному хосту
+	HostDelay time.Duration
+	// BrokenAfter задает, сколько ссылка должна не работать до уведомления владельца
+	BrokenAfter time.Duration
+	// HistoryRetention задает срок хранения результатов проверок
+	HistoryRetention time.Duration
+}
+
+// LinkHealthService проверяет доступность ссылок кнопок, хранит историю
+// проверок и уведомляет владельцев о ссылках, которые долго не работают
+type LinkHealthService struct {
+	uow           repository.UnitOfWork
+	healthRepo    repository.LinkHealthRepository
+	notifications *NotificationService
+	checker       *linkcheck.Checker
+	clock         clock.Clock
+	opts          LinkHealthOptions
+}
+
+// NewLinkHealthService создает новый экземпляр LinkHealthService
+func NewLinkHealthService(uow repository.UnitOfWork, healthRepo repository.LinkHealthRepository, notifications *NotificationService, checker *linkcheck.Checker, clock clock.Clock, opts LinkHealthOptions) *LinkHealthService {
+	return &LinkHealthService{
+		uow:           uow,
+		healthRepo:    healthRepo,
+		notifications: notifications,
+		checker:       checker,
+		clock:         clock,
+		opts:          opts,
+	}
+}
+
+// GetButtonHealth получает состояние ссылки кнопки и последние проверки
+func (s *LinkHealthService) GetButtonHealth(ctx context.Context, button models.LinkButton) (models.ButtonHealthResponse, error) {
+	checks, err := s.healthRepo.GetLinkChecksByButtonID(ctx, button.ID, linkChecksLimit)
+	if err != nil {
+		return models.ButtonHealthResponse{}, err
+	}
+
+	return models.ButtonHealthResponse{
+		ButtonID:        button.ID,
+		Health:          button.Health,
+		HealthCheckedAt: button.HealthCheckedAt,
+		BrokenSince:     button.BrokenSince,
+		Checks:          checks,
+	}, nil
+}
+
+// CheckAll проверяет ссылки всех активных кнопок и возвращает число
+// проверенных. Ошибки сохранения отдельных результатов не прерывают проверку
+func (s *LinkHealthService) CheckAll(ctx context.Context) (int, error) {
+	buttons, err := s.healthRepo.GetCheckableButtons(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	targets := make([]linkcheck.Target, 0, len(buttons))
+	for _, b := range buttons {
+		targets = append(targets, linkcheck.Target{ID: b.ID, URL: b.URL})
+	}
+
+	logger := logging.FromContext(ctx)
+	var checked atomic.Int64
+	s.checker.CheckAll(ctx, targets, s.opts.Concurrency, s.opts.HostDelay, func(target linkcheck.Target, result linkcheck.Result) {
+		checked.Add(1)
+		if err := s.saveResult(ctx, target.ID, result); err != nil && ctx.Err() == nil {
+			logger.Error("failed to save link check", "button_id", target.ID, "error", err)
+		}
+	})
+	return int(checked.Load()), ctx.Err()
+}
+
+// saveResult сохраняет результат проверки и обновляет состояние кнопки.
+// Неопределенный результат попадает в историю, но не меняет состояние
+func (s *LinkHealthService) saveResult(ctx context.Context, buttonID int64, result linkcheck.Result) error {
+	now := s.clock.Now()
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		err := s.healthRepo.CreateLinkCheck(ctx, models.LinkCheck{
+			LinkButtonID: buttonID,
+			URL:          result.URL,
+			StatusCode:   result.StatusCode,
+			FinalURL:     result.FinalURL,
+			Redirects:    result.Redirects,
+			Error:        result.Error,
+			Health:       result.Health,
+			DurationMs:   int(result.Duration / time.Millisecond),
+			CheckedAt:    now,
+		})
+		if err != nil || result.Health == linkcheck.HealthUnknown {
+			return err
+		}
+		return s.healthRepo.UpdateButtonHealth(ctx, buttonID, result.Health, now)
+	})
+}
+
+// NotifyBroken уведомляет владельцев о ссылках, которые не работают дольше
+// BrokenAfter. О каждой ссылке владелец уведомляется один раз, пока она не заработает
+func (s *LinkHealthService) NotifyBroken(ctx context.Context) (int, error) {
+	links, err := s.healthRepo.GetBrokenLinksToNotify(ctx, s.clock.Now().Add(-s.opts.BrokenAfter))
+	if err != nil {
+		return 0, err
+	}
+
+	byUser := make(map[int64][]models.BrokenLink)
+	var users []int64
+	for _, link := range links {
+		if _, ok := byUser[link.UserID]; !ok {
+			users = append(users, link.UserID)
+		}
+		byUser[link.UserID] = append(byUser[link.UserID], link)
+	}
+
+	notified := 0
+	for _, userID := range users {
+		userLinks := byUser[userID]
+		buttonIDs := make([]int64, 0, len(userLinks))
+		for _, link := range userLinks {
+			buttonIDs = append(buttonIDs, link.ButtonID)
+		}
+
+		err := s.uow.Do(ctx, func(ctx context.Context) error {
+			title := fmt.Sprintf("Не работают ссылки: %d", len(userLinks))
+			if err := s.notifications.Notify(ctx, userID, models.NotificationKindBrokenLinks, title, userLinks); err != nil {
+				return err
+			}
+			return s.healthRepo.MarkBrokenLinksNotified(ctx, buttonIDs, s.clock.Now())
+		})
+		if err != nil {
+			return notified, err
+		}
+		notified += len(userLinks)
+	}
+	return notified, nil
+}
+
+// RunChecker периодически проверяет ссылки, уведомляет владельцев
+// и удаляет устаревшую историю до отмены ctx
+func (s *LinkHealthService) RunChecker(ctx context.Context, interval time.Duration) {
+	logger := logging.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, logger)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *LinkHealthService) runOnce(ctx context.Context, logger *slog.Logger) {
+	checked, err := s.CheckAll(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("link check failed", "error", err)
+		}
+		return
+	}
+	logger.Info("links checked", "checked", checked)
+
+	if notified, err := s.NotifyBroken(ctx); err != nil && ctx.Err() == nil {
+		logger.Error("broken link notification failed", "error", err, "notified", notified)
+	} else if notified > 0 {
+		logger.Info("owners notified about broken links", "links", notified)
+	}
+
+	if err := s.healthRepo.DeleteLinkChecksBefore(ctx, s.clock.Now().Add(-s.opts.HistoryRetention)); err != nil && ctx.Err() == nil {
+		logger.Error("failed to delete old link checks", "error", err)
+	}
+}
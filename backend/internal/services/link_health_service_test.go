@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/linkcheck"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// fakeLinkHealthRepo хранит состояние ссылок кнопок так же, как
+// PostgresLinkHealthRepository: момент поломки сохраняется до успешной проверки
+type fakeLinkHealthRepo struct {
+	mu       sync.Mutex
+	buttons  map[int64]*models.LinkButton
+	notified map[int64]time.Time
+	checks   []models.LinkCheck
+}
+
+var _ repository.LinkHealthRepository = (*fakeLinkHealthRepo)(nil)
+
+func (r *fakeLinkHealthRepo) GetCheckableButtons(context.Context) ([]models.LinkButton, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	buttons := make([]models.LinkButton, 0, len(r.buttons))
+	for _, b := range r.buttons {
+		buttons = append(buttons, *b)
+	}
+	return buttons, nil
+}
+
+func (r *fakeLinkHealthRepo) CreateLinkCheck(_ context.Context, check models.LinkCheck) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+	return nil
+}
+
+func (r *fakeLinkHealthRepo) UpdateButtonHealth(_ context.Context, buttonID int64, health string, checkedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.buttons[buttonID]
+	b.Health = health
+	b.HealthCheckedAt = &checkedAt
+	if health != linkcheck.HealthBroken {
+		b.BrokenSince = nil
+		delete(r.notified, buttonID)
+	} else if b.BrokenSince == nil {
+		b.BrokenSince = &checkedAt
+	}
+	return nil
+}
+
+func (r *fakeLinkHealthRepo) GetLinkChecksByButtonID(context.Context, int64, int) ([]models.LinkCheck, error) {
+	return r.checks, nil
+}
+
+func (r *fakeLinkHealthRepo) DeleteLinkChecksBefore(context.Context, time.Time) error {
+	return nil
+}
+
+func (r *fakeLinkHealthRepo) GetBrokenLinksToNotify(_ context.Context, brokenBefore time.Time) ([]models.BrokenLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var links []models.BrokenLink
+	for _, b := range r.buttons {
+		if _, done := r.notified[b.ID]; done || b.Health != linkcheck.HealthBroken || b.BrokenSince.After(brokenBefore) {
+			continue
+		}
+		links = append(links, models.BrokenLink{ButtonID: b.ID, MultiLinkID: b.MultiLinkID, UserID: 1, Title: b.Title, URL: b.URL, BrokenSince: *b.BrokenSince})
+	}
+	return links, nil
+}
+
+func (r *fakeLinkHealthRepo) MarkBrokenLinksNotified(_ context.Context, buttonIDs []int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range buttonIDs {
+		r.notified[id] = at
+	}
+	return nil
+}
+
+type fakeNotificationRepo struct {
+	repository.NotificationRepository
+	created []models.Notification
+}
+
+func (r *fakeNotificationRepo) CreateNotification(_ context.Context, notification models.Notification) (int64, error) {
+	r.created = append(r.created, notification)
+	return int64(len(r.created)), nil
+}
+
+func TestLinkHealthBrokenTransition(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	healthRepo := &fakeLinkHealthRepo{
+		buttons:  map[int64]*models.LinkButton{7: {ID: 7, MultiLinkID: 1, Title: "Сайт", URL: server.URL, Health: linkcheck.HealthOK}},
+		notified: map[int64]time.Time{},
+	}
+	notificationRepo := &fakeNotificationRepo{}
+	s := NewLinkHealthService(newFakeRepos().uow, healthRepo, NewNotificationService(notificationRepo),
+		linkcheck.New(linkcheck.Options{Timeout: time.Second, MaxRedirects: 3, AllowPrivate: true}),
+		clock.Func(func() time.Time { return now }),
+		LinkHealthOptions{Concurrency: 1, HostDelay: time.Millisecond, BrokenAfter: time.Hour})
+	ctx := context.Background()
+	button := healthRepo.buttons[7]
+
+	check := func() {
+		t.Helper()
+		if _, err := s.CheckAll(ctx); err != nil {
+			t.Fatalf("CheckAll: %v", err)
+		}
+	}
+	notify := func() int {
+		t.Helper()
+		n, err := s.NotifyBroken(ctx)
+		if err != nil {
+			t.Fatalf("NotifyBroken: %v", err)
+		}
+		return n
+	}
+
+	// Первая неудачная проверка отмечает ссылку неработающей
+	check()
+	if button.Health != linkcheck.HealthBroken || button.BrokenSince == nil || !button.BrokenSince.Equal(start) {
+		t.Fatalf("after failure: health = %q, broken since %v; want broken since %v", button.Health, button.BrokenSince, start)
+	}
+
+	// Ограничение частоты запросов не меняет состояние и момент поломки
+	now = start.Add(30 * time.Minute)
+	status.Store(http.StatusTooManyRequests)
+	check()
+	if button.Health != linkcheck.HealthBroken || !button.BrokenSince.Equal(start) {
+		t.Fatalf("after 429: health = %q, broken since %v; want unchanged", button.Health, button.BrokenSince)
+	}
+	if n := notify(); n != 0 {
+		t.Fatalf("notified %d links before BrokenAfter", n)
+	}
+
+	// Повторная поломка не сдвигает момент, после BrokenAfter владелец уведомляется один раз
+	now = start.Add(2 * time.Hour)
+	status.Store(http.StatusNotFound)
+	check()
+	if !button.BrokenSince.Equal(start) {
+		t.Errorf("broken since moved to %v", button.BrokenSince)
+	}
+	if n := notify(); n != 1 {
+		t.Fatalf("notified %d links, want 1", n)
+	}
+	if n := notify(); n != 0 {
+		t.Fatalf("notified %d links again, want 0", n)
+	}
+	if len(notificationRepo.created) != 1 || notificationRepo.created[0].Kind != models.NotificationKindBrokenLinks {
+		t.Errorf("notifications = %+v, want one broken_links notification", notificationRepo.created)
+	}
+
+	// Успешная проверка снимает отметку
+	now = start.Add(3 * time.Hour)
+	status.Store(http.StatusOK)
+	check()
+	if button.Health != linkcheck.HealthOK || button.BrokenSince != nil {
+		t.Errorf("after recovery: health = %q, broken since %v; want ok", button.Health, button.BrokenSince)
+	}
+	if len(healthRepo.checks) != 4 {
+		t.Errorf("saved %d checks, want 4", len(healthRepo.checks))
+	}
+}
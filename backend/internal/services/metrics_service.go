@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"time"
 
 	"mvp_multylink/backend/internal/models"
@@ -22,43 +23,24 @@ func NewMetricsService(metricsRepo repository.MetricsRepository, buttonRepo repo
 }
 
 // RecordClickEvent записывает событие клика по кнопке
-func (s *MetricsService) RecordClickEvent(event models.ClickEvent) (int64, error) {
-	return s.metricsRepo.CreateClickEvent(event)
+func (s *MetricsService) RecordClickEvent(ctx context.Context, event models.ClickEvent) (int64, error) {
+	return s.metricsRepo.CreateClickEvent(ctx, event)
 }
 
 // IncrementButtonClicks увеличивает счетчик кликов для кнопки
-func (s *MetricsService) IncrementButtonClicks(buttonID int64) error {
-	// Получаем текущие метрики кнопки
-	metrics, err := s.metricsRepo.GetMetricsByButtonID(buttonID)
-	if err != nil {
-		// Если метрики не существуют, создаем новые
-		metrics = models.LinkMetrics{
-			LinkButtonID: buttonID,
-			Clicks:       0,
-		}
-		_, err = s.metricsRepo.CreateLinkMetrics(metrics)
-		if err != nil {
-			return err
-		}
-	}
-
-	// Увеличиваем счетчик кликов
-	metrics.Clicks++
-	metrics.LastClickAt = time.Now()
-
-	// Обновляем метрики
-	return s.metricsRepo.UpdateLinkMetrics(metrics)
+func (s *MetricsService) IncrementButtonClicks(ctx context.Context, buttonID int64) error {
+	return incrementButtonClicks(ctx, s.metricsRepo, buttonID, time.Now())
 }
 
 // GetButtonMetrics получает метрики для кнопки
-func (s *MetricsService) GetButtonMetrics(buttonID int64) (models.LinkMetrics, error) {
-	return s.metricsRepo.GetMetricsByButtonID(buttonID)
+func (s *MetricsService) GetButtonMetrics(ctx context.Context, buttonID int64) (models.LinkMetrics, error) {
+	return s.metricsRepo.GetMetricsByButtonID(ctx, buttonID)
 }
 
 // GetUTMSourceStats получает статистику по источникам трафика (utm_source)
-func (s *MetricsService) GetUTMSourceStats(multiLinkID int64) (map[string]int, error) {
+func (s *MetricsService) GetUTMSourceStats(ctx context.Context, multiLinkID int64) (map[string]int, error) {
 	// Получаем все кнопки для мультиссылки
-	buttons, err := s.buttonRepo.GetButtonsByMultiLinkID(multiLinkID)
+	buttons, err := s.buttonRepo.GetButtonsByMultiLinkID(ctx, multiLinkID)
 	if err != nil {
 		return nil, err
 	}
@@ -70,13 +52,13 @@ func (s *MetricsService) GetUTMSourceStats(multiLinkID int64) (map[string]int, e
 	}
 
 	// Получаем статистику по UTM-меткам
-	return s.metricsRepo.GetUTMSourceStatsByButtonIDs(buttonIDs)
+	return s.metricsRepo.GetUTMSourceStatsByButtonIDs(ctx, buttonIDs)
 }
 
 // GetUTMMediumStats получает статистику по каналам трафика (utm_medium)
-func (s *MetricsService) GetUTMMediumStats(multiLinkID int64) (map[string]int, error) {
+func (s *MetricsService) GetUTMMediumStats(ctx context.Context, multiLinkID int64) (map[string]int, error) {
 	// Получаем все кнопки для мультиссылки
-	buttons, err := s.buttonRepo.GetButtonsByMultiLinkID(multiLinkID)
+	buttons, err := s.buttonRepo.GetButtonsByMultiLinkID(ctx, multiLinkID)
 	if err != nil {
 		return nil, err
 	}
@@ -88,13 +70,13 @@ func (s *MetricsService) GetUTMMediumStats(multiLinkID int64) (map[string]int, e
 	}
 
 	// Получаем статистику по UTM-меткам
-	return s.metricsRepo.GetUTMMediumStatsByButtonIDs(buttonIDs)
+	return s.metricsRepo.GetUTMMediumStatsByButtonIDs(ctx, buttonIDs)
 }
 
 // GetClickEventsByDateRange получает события кликов за указанный период
-func (s *MetricsService) GetClickEventsByDateRange(multiLinkID int64, startDate, endDate time.Time) ([]models.ClickEvent, error) {
+func (s *MetricsService) GetClickEventsByDateRange(ctx context.Context, multiLinkID int64, startDate, endDate time.Time) ([]models.ClickEvent, error) {
 	// Получаем все кнопки для мультиссылки
-	buttons, err := s.buttonRepo.GetButtonsByMultiLinkID(multiLinkID)
+	buttons, err := s.buttonRepo.GetButtonsByMultiLinkID(ctx, multiLinkID)
 	if err != nil {
 		return nil, err
 	}
@@ -106,5 +88,5 @@ func (s *MetricsService) GetClickEventsByDateRange(multiLinkID int64, startDate,
 	}
 
 	// Получаем события кликов
-	return s.metricsRepo.GetClickEventsByButtonIDsAndDateRange(buttonIDs, startDate, endDate)
+	return s.metricsRepo.GetClickEventsByButtonIDsAndDateRange(ctx, buttonIDs, startDate, endDate)
 }
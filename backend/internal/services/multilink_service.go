@@ -1,72 +1,141 @@
 package services
 
 import (
+	"context"
+	"time"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/clock"
 	"mvp_multylink/backend/internal/models"
 	"mvp_multylink/backend/internal/repository"
 )
 
 // MultiLinkService предоставляет методы для работы с мультиссылками
 type MultiLinkService struct {
+	uow           repository.UnitOfWork
 	multiLinkRepo repository.MultiLinkRepository
 	buttonRepo    repository.ButtonRepository
+	metricsRepo   repository.MetricsRepository
+	draftRepo     repository.DraftRepository
+	revisions     *RevisionService
+	blocklist     *blocklist.List
+	clock         clock.Clock
 }
 
 // NewMultiLinkService создает новый экземпляр MultiLinkService
-func NewMultiLinkService(multiLinkRepo repository.MultiLinkRepository, buttonRepo repository.ButtonRepository) *MultiLinkService {
+func NewMultiLinkService(uow repository.UnitOfWork, multiLinkRepo repository.MultiLinkRepository, buttonRepo repository.ButtonRepository, metricsRepo repository.MetricsRepository, draftRepo repository.DraftRepository, revisions *RevisionService, blocklist *blocklist.List, clock clock.Clock) *MultiLinkService {
 	return &MultiLinkService{
+		uow:           uow,
 		multiLinkRepo: multiLinkRepo,
 		buttonRepo:    buttonRepo,
+		metricsRepo:   metricsRepo,
+		draftRepo:     draftRepo,
+		revisions:     revisions,
+		blocklist:     blocklist,
+		clock:         clock,
 	}
 }
 
 // CreateMultiLink создает новую мультиссылку
-func (s *MultiLinkService) CreateMultiLink(multiLink models.MultiLink) (int64, error) {
-	return s.multiLinkRepo.CreateMultiLink(multiLink)
+func (s *MultiLinkService) CreateMultiLink(ctx context.Context, multiLink models.MultiLink) (int64, error) {
+	return s.multiLinkRepo.CreateMultiLink(ctx, multiLink)
 }
 
 // GetMultiLinkByID получает мультиссылку по ID
-func (s *MultiLinkService) GetMultiLinkByID(id int64) (models.MultiLink, error) {
-	return s.multiLinkRepo.GetMultiLinkByID(id)
+func (s *MultiLinkService) GetMultiLinkByID(ctx context.Context, id int64) (models.MultiLink, error) {
+	return s.multiLinkRepo.GetMultiLinkByID(ctx, id)
 }
 
 // GetMultiLinkBySlug получает мультиссылку по slug
-func (s *MultiLinkService) GetMultiLinkBySlug(slug string) (models.MultiLink, error) {
-	return s.multiLinkRepo.GetMultiLinkBySlug(slug)
+func (s *MultiLinkService) GetMultiLinkBySlug(ctx context.Context, slug string) (models.MultiLink, error) {
+	return s.multiLinkRepo.GetMultiLinkBySlug(ctx, slug)
 }
 
 // GetMultiLinksByUserID получает все мультиссылки пользователя
-func (s *MultiLinkService) GetMultiLinksByUserID(userID int64) ([]models.MultiLink, error) {
-	return s.multiLinkRepo.GetMultiLinksByUserID(userID)
+func (s *MultiLinkService) GetMultiLinksByUserID(ctx context.Context, userID int64) ([]models.MultiLink, error) {
+	return s.multiLinkRepo.GetMultiLinksByUserID(ctx, userID)
 }
 
-// UpdateMultiLink обновляет мультиссылку
-func (s *MultiLinkService) UpdateMultiLink(multiLink models.MultiLink) error {
-	return s.multiLinkRepo.UpdateMultiLink(multiLink)
+// DeleteMultiLink перемещает мультиссылку и ее кнопки в корзину. Кнопки
+// помечаются тем же временем удаления, чтобы восстановить их вместе с мультиссылкой
+func (s *MultiLinkService) DeleteMultiLink(ctx context.Context, id int64) error {
+	deletedAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.buttonRepo.SoftDeleteButtonsByMultiLinkID(ctx, id, deletedAt); err != nil {
+			return err
+		}
+		return s.multiLinkRepo.SoftDeleteMultiLink(ctx, id, deletedAt)
+	})
 }
 
-// DeleteMultiLink удаляет мультиссылку
-func (s *MultiLinkService) DeleteMultiLink(id int64) error {
-	// Сначала удаляем все кнопки, связанные с мультиссылкой
-	err := s.buttonRepo.DeleteButtonsByMultiLinkID(id)
-	if err != nil {
-		return err
-	}
+// PurgeMultiLink окончательно удаляет мультиссылку вместе с кнопками, их
+// метриками и событиями кликов в одной транзакции
+func (s *MultiLinkService) PurgeMultiLink(ctx context.Context, id int64) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		// Сначала удаляем аналитику кнопок
+		if err := s.metricsRepo.DeleteClickEventsByMultiLinkID(ctx, id); err != nil {
+			return err
+		}
+		if err := s.metricsRepo.DeleteMetricsByMultiLinkID(ctx, id); err != nil {
+			return err
+		}
 
-	// Затем удаляем саму мультиссылку
-	return s.multiLinkRepo.DeleteMultiLink(id)
+		// Затем черновик, историю изменений и все кнопки, связанные с мультиссылкой
+		if err := s.draftRepo.DeleteDraft(ctx, id); err != nil {
+			return err
+		}
+		if err := s.revisions.DeleteRevisionsByMultiLinkID(ctx, id); err != nil {
+			return err
+		}
+		if err := s.buttonRepo.DeleteButtonsByMultiLinkID(ctx, id); err != nil {
+			return err
+		}
+
+		// И саму мультиссылку
+		return s.multiLinkRepo.DeleteMultiLink(ctx, id)
+	})
 }
 
 // CheckSlugExists проверяет существование мультиссылки с указанным slug
-func (s *MultiLinkService) CheckSlugExists(slug string) (bool, error) {
-	return s.multiLinkRepo.CheckSlugExists(slug)
+func (s *MultiLinkService) CheckSlugExists(ctx context.Context, slug string) (bool, error) {
+	return s.multiLinkRepo.CheckSlugExists(ctx, slug)
 }
 
 // GetLinkButtonsByMultiLinkID получает все кнопки для мультиссылки
-func (s *MultiLinkService) GetLinkButtonsByMultiLinkID(multiLinkID int64) ([]models.LinkButton, error) {
-	return s.buttonRepo.GetButtonsByMultiLinkID(multiLinkID)
+func (s *MultiLinkService) GetLinkButtonsByMultiLinkID(ctx context.Context, multiLinkID int64) ([]models.LinkButton, error) {
+	buttons, err := s.buttonRepo.GetButtonsByMultiLinkID(ctx, multiLinkID)
+	if err != nil {
+		return nil, err
+	}
+	flagBlocked(s.blocklist, buttons)
+	return buttons, nil
+}
+
+// GetActiveLinkButtonsByMultiLinkID получает все активные кнопки для мультиссылки,
+// видимые в текущий момент по расписанию. У заблокированных кнопок ссылки
+// скрываются: посетитель увидит предупреждение при переходе
+func (s *MultiLinkService) GetActiveLinkButtonsByMultiLinkID(ctx context.Context, multiLinkID int64) ([]models.LinkButton, error) {
+	buttons, err := s.buttonRepo.GetActiveButtonsByMultiLinkID(ctx, multiLinkID, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	flagBlocked(s.blocklist, buttons)
+	for i := range buttons {
+		if buttons[i].Blocked {
+			buttons[i].URL = ""
+			buttons[i].FallbackURL = ""
+		}
+	}
+	return buttons, nil
+}
+
+// IsPublished проверяет, что мультиссылка активна и опубликована по расписанию
+func (s *MultiLinkService) IsPublished(multiLink models.MultiLink) bool {
+	return IsMultiLinkPublished(multiLink, s.clock.Now())
 }
 
-// GetActiveLinkButtonsByMultiLinkID получает все активные кнопки для мультиссылки
-func (s *MultiLinkService) GetActiveLinkButtonsByMultiLinkID(multiLinkID int64) ([]models.LinkButton, error) {
-	return s.buttonRepo.GetActiveButtonsByMultiLinkID(multiLinkID)
+// IsButtonVisible проверяет, что кнопка активна и видима по расписанию
+func (s *MultiLinkService) IsButtonVisible(button models.LinkButton) bool {
+	return IsButtonVisible(button, s.clock.Now())
 }
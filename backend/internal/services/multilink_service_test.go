@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/models"
+)
+
+var errWriteFailed = errors.New("write failed")
+
+// newTestMultiLinkService создает сервис поверх фейков с одной мультиссылкой,
+// ее кнопкой, черновиком и ревизией
+func newTestMultiLinkService(t *testing.T) (*MultiLinkService, fakeRepos) {
+	t.Helper()
+	f := newFakeRepos()
+	f.store.multiLinks[1] = models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Demo", IsActive: true}
+	f.store.buttons[10] = models.LinkButton{ID: 10, MultiLinkID: 1, Title: "Сайт", URL: "https://example.com", Position: 1, IsActive: true}
+	f.store.drafts[1] = models.MultiLinkDraft{MultiLinkID: 1}
+	f.store.revisions[1] = []models.MultiLinkRevision{{ID: 100, MultiLinkID: 1, Revision: 1}}
+	f.store.metrics[1] = 3
+
+	s := NewMultiLinkService(f.uow, f.multiLink, f.button, f.metrics, f.draft, f.revisionService(), blocklist.New(""), clock.Real{})
+	return s, f
+}
+
+func TestDeleteMultiLinkRollsBackOnFailure(t *testing.T) {
+	s, f := newTestMultiLinkService(t)
+	// Кнопки уже перемещены в корзину, когда перемещение мультиссылки завершается ошибкой
+	f.store.fail["SoftDeleteMultiLink"] = errWriteFailed
+
+	if err := s.DeleteMultiLink(context.Background(), 1); !errors.Is(err, errWriteFailed) {
+		t.Fatalf("DeleteMultiLink error = %v, want %v", err, errWriteFailed)
+	}
+
+	if button := f.store.buttons[10]; button.DeletedAt != nil {
+		t.Errorf("button deleted_at = %v, want rollback to nil", button.DeletedAt)
+	}
+	if multiLink := f.store.multiLinks[1]; multiLink.DeletedAt != nil {
+		t.Errorf("multilink deleted_at = %v, want nil", multiLink.DeletedAt)
+	}
+}
+
+func TestDeleteMultiLinkMovesToTrash(t *testing.T) {
+	s, f := newTestMultiLinkService(t)
+
+	if err := s.DeleteMultiLink(context.Background(), 1); err != nil {
+		t.Fatalf("DeleteMultiLink: %v", err)
+	}
+	if f.store.buttons[10].DeletedAt == nil || f.store.multiLinks[1].DeletedAt == nil {
+		t.Error("multilink and its buttons must be moved to trash")
+	}
+}
+
+func TestPurgeMultiLinkRollsBackOnFailure(t *testing.T) {
+	s, f := newTestMultiLinkService(t)
+	// Аналитика уже удалена, когда удаление метрик завершается ошибкой
+	f.store.fail["DeleteMetricsByMultiLinkID"] = errWriteFailed
+
+	if err := s.PurgeMultiLink(context.Background(), 1); !errors.Is(err, errWriteFailed) {
+		t.Fatalf("PurgeMultiLink error = %v, want %v", err, errWriteFailed)
+	}
+
+	if got := f.store.metrics[1]; got != 3 {
+		t.Errorf("click events = %d, want rollback to 3", got)
+	}
+	if _, ok := f.store.drafts[1]; !ok {
+		t.Error("draft must survive a failed purge")
+	}
+	if len(f.store.revisions[1]) != 1 {
+		t.Error("revisions must survive a failed purge")
+	}
+	if _, ok := f.store.buttons[10]; !ok {
+		t.Error("buttons must survive a failed purge")
+	}
+	if _, ok := f.store.multiLinks[1]; !ok {
+		t.Error("multilink must survive a failed purge")
+	}
+}
+
+func TestPurgeMultiLinkRollsBackOnLastWrite(t *testing.T) {
+	s, f := newTestMultiLinkService(t)
+	f.store.fail["DeleteMultiLink"] = errWriteFailed
+
+	if err := s.PurgeMultiLink(context.Background(), 1); !errors.Is(err, errWriteFailed) {
+		t.Fatalf("PurgeMultiLink error = %v, want %v", err, errWriteFailed)
+	}
+	if f.store.metrics[1] != 3 || len(f.store.revisions[1]) != 1 {
+		t.Error("analytics and revisions must be restored after a failed purge")
+	}
+	if _, ok := f.store.buttons[10]; !ok {
+		t.Error("buttons must be restored after a failed purge")
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// notificationsLimit ограничивает число уведомлений в ответе
+const notificationsLimit = 100
+
+// NotificationService предоставляет методы для работы с уведомлениями пользователей
+type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationService создает новый экземпляр NotificationService
+func NewNotificationService(notificationRepo repository.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Notify сохраняет уведомление пользователя. Payload сериализуется в JSON
+func (s *NotificationService) Notify(ctx context.Context, userID int64, kind, title string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.notificationRepo.CreateNotification(ctx, models.Notification{
+		UserID:    userID,
+		Kind:      kind,
+		Title:     title,
+		Payload:   data,
+		CreatedAt: time.Now(),
+	})
+	return err
+}
+
+// GetNotifications получает последние уведомления пользователя
+func (s *NotificationService) GetNotifications(ctx context.Context, userID int64) ([]models.Notification, error) {
+	return s.notificationRepo.GetNotificationsByUserID(ctx, userID, notificationsLimit)
+}
+
+// MarkRead отмечает уведомление пользователя прочитанным
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID int64) error {
+	return s.notificationRepo.MarkNotificationRead(ctx, id, userID, time.Now())
+}
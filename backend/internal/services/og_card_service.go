@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/color"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"mvp_multylink/backend/internal/colors"
+	"mvp_multylink/backend/internal/logging"
+	"mvp_multylink/backend/internal/lrucache"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/ogcard"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// defaultAccent — цвет карточек защищенных и чувствительных страниц,
+// оформление которых не раскрывается
+var defaultAccent = color.RGBA{R: 0x4f, G: 0x46, B: 0xe5, A: 0xff}
+
+// OGCardService рисует карточки страниц для og:image. Карточка кэшируется
+// по ID страницы вместе с отпечатком данных, из которых она нарисована:
+// после изменения страницы, профиля или числа кнопок она рисуется заново
+type OGCardService struct {
+	multiLinkService *MultiLinkService
+	profileRepo      repository.ProfileRepository
+	unfurlService    *UnfurlService
+	apiBaseURL       string
+	cache            *lrucache.Cache[int64, renderedCard]
+}
+
+type renderedCard struct {
+	fingerprint string
+	png         []byte
+}
+
+// NewOGCardService создает новый экземпляр OGCardService
+func NewOGCardService(multiLinkService *MultiLinkService, profileRepo repository.ProfileRepository, unfurlService *UnfurlService, apiBaseURL string, cacheSize int, cacheTTL time.Duration) *OGCardService {
+	return &OGCardService{
+		multiLinkService: multiLinkService,
+		profileRepo:      profileRepo,
+		unfurlService:    unfurlService,
+		apiBaseURL:       strings.TrimSuffix(apiBaseURL, "/"),
+		cache:            lrucache.New[int64, renderedCard](cacheSize, cacheTTL),
+	}
+}
+
+// ImageURL возвращает адрес карточки для og:image. Параметр v меняется при
+// изменении страницы, чтобы мессенджеры не показывали устаревшую карточку
+func (s *OGCardService) ImageURL(multiLink models.MultiLink) string {
+	return s.apiBaseURL + "/api/public/multilinks/" + url.PathEscape(multiLink.Slug) +
+		"/og.png?v=" + strconv.FormatInt(multiLink.UpdatedAt.Unix(), 10)
+}
+
+// Card возвращает PNG карточки опубликованной страницы и ее отпечаток для
+// ETag. Для защищенных паролем и чувствительных страниц рисуется карточка
+// без содержимого
+func (s *OGCardService) Card(ctx context.Context, multiLink models.MultiLink) ([]byte, string, error) {
+	card, avatarURL, err := s.describe(ctx, multiLink)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%q|%q|%q|%s|%s|%q",
+		card.Title, card.Subtitle, card.Footer, colors.Hex(card.Accent), colors.Hex(card.Text), avatarURL)))
+	fingerprint := hex.EncodeToString(sum[:8])
+	if cached, ok := s.cache.Get(multiLink.ID); ok && cached.fingerprint == fingerprint {
+		return cached.png, fingerprint, nil
+	}
+
+	// Карточка рисуется и без аватара, если его не удалось загрузить
+	if avatarURL != "" {
+		card.Avatar, err = s.unfurlService.FetchImage(ctx, avatarURL)
+		if err != nil {
+			logging.FromContext(ctx).Debug("og card avatar skipped", "multilink_id", multiLink.ID, "url", avatarURL, "error", err)
+		}
+	}
+
+	data, err := ogcard.Render(card)
+	if err != nil {
+		return nil, "", err
+	}
+	s.cache.Put(multiLink.ID, renderedCard{fingerprint: fingerprint, png: data})
+	return data, fingerprint, nil
+}
+
+// describe собирает содержимое карточки и ссылку на аватар владельца
+func (s *OGCardService) describe(ctx context.Context, multiLink models.MultiLink) (ogcard.Card, string, error) {
+	card := ogcard.Card{Accent: defaultAccent}
+
+	if multiLink.PasswordProtected {
+		card.Title = "Страница защищена паролем"
+		return card, "", nil
+	}
+	if _, sensitive := MultiLinkSensitiveReason(multiLink); sensitive {
+		card.Title = NeutralMultiLink(multiLink).Title
+		return card, "", nil
+	}
+	card.Title = multiLink.Title
+
+	// Карточка рисуется в цветах оформления страницы
+	pageTheme := ResolveTheme(multiLink)
+	if background, err := colors.Parse(pageTheme.BackgroundColor); err == nil {
+		card.Accent = background
+	}
+	if text, err := colors.Parse(pageTheme.TextColor); err == nil {
+		card.Text = text
+	}
+
+	buttons, err := s.multiLinkService.GetActiveLinkButtonsByMultiLinkID(ctx, multiLink.ID)
+	if err != nil {
+		return card, "", err
+	}
+	links := 0
+	for _, b := range buttons {
+		if IsClickableKind(b.Kind) {
+			links++
+		}
+	}
+	if links > 0 {
+		card.Footer = strconv.Itoa(links) + " " + pluralRu(links, "ссылка", "ссылки", "ссылок")
+	}
+
+	profile, err := s.profileRepo.GetProfileByUserID(ctx, multiLink.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return card, "", nil
+	}
+	if err != nil {
+		return card, "", err
+	}
+	if profile.Username != "" {
+		card.Subtitle = "@" + profile.Username
+	}
+	return card, profile.AvatarURL, nil
+}
+
+// pluralRu выбирает форму существительного для числа n
+func pluralRu(n int, one, few, many string) string {
+	n %= 100
+	switch {
+	case n >= 11 && n <= 14:
+		return many
+	case n%10 == 1:
+		return one
+	case n%10 >= 2 && n%10 <= 4:
+		return few
+	default:
+		return many
+	}
+}
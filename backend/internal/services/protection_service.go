@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/signing"
+)
+
+// ProtectionOptions задает параметры защиты страниц паролем
+type ProtectionOptions struct {
+	// UnlockTTL задает срок действия токена доступа после ввода пароля
+	UnlockTTL time.Duration
+	// MaxAttempts ограничивает число неверных паролей для одной страницы
+	// с одного адреса за AttemptWindow
+	MaxAttempts int
+	// MaxSlugAttempts ограничивает число неверных паролей для одной страницы
+	// со всех адресов вместе за AttemptWindow: подбор с множества адресов
+	// упирается в общий для страницы лимит
+	MaxSlugAttempts int
+	AttemptWindow   time.Duration
+}
+
+// ProtectionService управляет паролями страниц и выдает посетителям
+// короткоживущие токены доступа к защищенным страницам
+type ProtectionService struct {
+	multiLinkRepo repository.MultiLinkRepository
+	signer        *signing.Signer
+	attempts      *attemptLimiter
+	slugAttempts  *attemptLimiter
+	clock         clock.Clock
+	opts          ProtectionOptions
+}
+
+// NewProtectionService создает новый экземпляр ProtectionService
+func NewProtectionService(multiLinkRepo repository.MultiLinkRepository, signer *signing.Signer, clock clock.Clock, opts ProtectionOptions) *ProtectionService {
+	return &ProtectionService{
+		multiLinkRepo: multiLinkRepo,
+		signer:        signer,
+		attempts:      newAttemptLimiter(opts.MaxAttempts, opts.AttemptWindow),
+		slugAttempts:  newAttemptLimiter(opts.MaxSlugAttempts, opts.AttemptWindow),
+		clock:         clock,
+		opts:          opts,
+	}
+}
+
+// SetPassword задает пароль мультиссылки. Ранее выданные токены доступа
+// перестают действовать
+func (s *ProtectionService) SetPassword(ctx context.Context, multiLinkID int64, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return s.multiLinkRepo.SetMultiLinkPassword(ctx, multiLinkID, string(hash))
+}
+
+// RemovePassword снимает защиту паролем с мультиссылки
+func (s *ProtectionService) RemovePassword(ctx context.Context, multiLinkID int64) error {
+	return s.multiLinkRepo.SetMultiLinkPassword(ctx, multiLinkID, "")
+}
+
+// Unlock проверяет пароль посетителя и выдает токен доступа к странице.
+// Неверные пароли учитываются по slug и адресу посетителя: после MaxAttempts
+// ошибок попытки с этого адреса отклоняются до конца окна, а посетители
+// с других адресов по-прежнему могут открыть страницу. Кроме того, после
+// MaxSlugAttempts ошибок со всех адресов вместе до конца окна отклоняются
+// все попытки для страницы
+func (s *ProtectionService) Unlock(multiLink models.MultiLink, clientIP, password string) (models.UnlockResponse, error) {
+	now := s.clock.Now()
+	if !multiLink.PasswordProtected {
+		return s.issueToken(multiLink, now), nil
+	}
+	key := attemptKey(multiLink.Slug, clientIP)
+	if s.retryAfter(multiLink.Slug, key, now) > 0 {
+		return models.UnlockResponse{}, ErrTooManyAttempts
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(multiLink.PasswordHash), []byte(password)) != nil {
+		s.attempts.fail(key, now)
+		s.slugAttempts.fail(multiLink.Slug, now)
+		return models.UnlockResponse{}, ErrWrongPassword
+	}
+	return s.issueToken(multiLink, now), nil
+}
+
+// RetryAfter возвращает, через сколько посетитель с адреса clientIP снова
+// может вводить пароль страницы: ожидание по большему из двух лимитов
+func (s *ProtectionService) RetryAfter(slug, clientIP string) time.Duration {
+	return s.retryAfter(slug, attemptKey(slug, clientIP), s.clock.Now())
+}
+
+func (s *ProtectionService) retryAfter(slug, key string, now time.Time) time.Duration {
+	return max(s.attempts.retryAfter(key, now), s.slugAttempts.retryAfter(slug, now))
+}
+
+// attemptKey — ключ счетчика неверных паролей для страницы и адреса посетителя
+func attemptKey(slug, clientIP string) string {
+	return slug + "|" + clientIP
+}
+
+// IsUnlocked проверяет, что страница не защищена паролем или токен
+// посетителя действует для текущего пароля страницы
+func (s *ProtectionService) IsUnlocked(multiLink models.MultiLink, token string) bool {
+	if !multiLink.PasswordProtected {
+		return true
+	}
+	if token == "" {
+		return false
+	}
+	subject, err := s.signer.Verify(token, s.clock.Now())
+	return err == nil && subject == unlockSubject(multiLink)
+}
+
+func (s *ProtectionService) issueToken(multiLink models.MultiLink, now time.Time) models.UnlockResponse {
+	expiresAt := now.Add(s.opts.UnlockTTL)
+	return models.UnlockResponse{
+		Token:     s.signer.Sign(unlockSubject(multiLink), expiresAt),
+		ExpiresAt: expiresAt,
+	}
+}
+
+// unlockSubject связывает токен с мультиссылкой и текущим хэшем пароля:
+// после смены пароля старые токены не подходят
+func unlockSubject(multiLink models.MultiLink) string {
+	sum := sha256.Sum256([]byte(multiLink.PasswordHash))
+	return strconv.FormatInt(multiLink.ID, 10) + ":" + hex.EncodeToString(sum[:8])
+}
+
+// attemptLimiter считает неверные попытки по ключу в фиксированном окне.
+// Счетчики хранятся в памяти процесса
+type attemptLimiter struct {
+	max    int
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]attemptWindow
+}
+
+type attemptWindow struct {
+	start    time.Time
+	failures int
+}
+
+// attemptLimiterPruneSize задает размер, после которого из limiter удаляются истекшие окна
+const attemptLimiterPruneSize = 10000
+
+func newAttemptLimiter(max int, window time.Duration) *attemptLimiter {
+	return &attemptLimiter{max: max, window: window, entries: make(map[string]attemptWindow)}
+}
+
+func (l *attemptLimiter) retryAfter(key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok || entry.failures < l.max {
+		return 0
+	}
+	if wait := entry.start.Add(l.window).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+func (l *attemptLimiter) fail(key string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok || !now.Before(entry.start.Add(l.window)) {
+		entry = attemptWindow{start: now}
+	}
+	entry.failures++
+	l.entries[key] = entry
+
+	if len(l.entries) > attemptLimiterPruneSize {
+		for k, e := range l.entries {
+			if !now.Before(e.start.Add(l.window)) {
+				delete(l.entries, k)
+			}
+		}
+	}
+}
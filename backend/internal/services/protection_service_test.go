@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/signing"
+)
+
+// protectedMultiLink возвращает мультиссылку, защищенную паролем «secret»
+func protectedMultiLink(t *testing.T, id int64, slug string) models.MultiLink {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.MultiLink{ID: id, Slug: slug, PasswordHash: string(hash), PasswordProtected: true}
+}
+
+// newProtectionService возвращает сервис, для которого текущее время — *now
+func newProtectionService(now *time.Time, maxAttempts, maxSlugAttempts int) *ProtectionService {
+	return NewProtectionService(nil, signing.NewSigner("test-secret", "unlock"), clock.Func(func() time.Time { return *now }),
+		ProtectionOptions{UnlockTTL: time.Hour, MaxAttempts: maxAttempts, MaxSlugAttempts: maxSlugAttempts, AttemptWindow: 10 * time.Minute})
+}
+
+func TestUnlockLockoutIsPerClient(t *testing.T) {
+	multiLink := protectedMultiLink(t, 1, "demo")
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newProtectionService(&now, 3, 100)
+
+	const attacker, visitor = "203.0.113.7", "198.51.100.20"
+	for i := 0; i < 3; i++ {
+		if _, err := s.Unlock(multiLink, attacker, "guess"); !errors.Is(err, ErrWrongPassword) {
+			t.Fatalf("attempt %d: error = %v, want ErrWrongPassword", i+1, err)
+		}
+	}
+
+	// Подбор с одного адреса блокирует только этот адрес, даже с верным паролем
+	if _, err := s.Unlock(multiLink, attacker, "secret"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("attacker after lockout: error = %v, want ErrTooManyAttempts", err)
+	}
+	if wait := s.RetryAfter(multiLink.Slug, attacker); wait != 10*time.Minute {
+		t.Errorf("attacker retry after = %v, want 10m", wait)
+	}
+
+	access, err := s.Unlock(multiLink, visitor, "secret")
+	if err != nil {
+		t.Fatalf("visitor from another address: %v", err)
+	}
+	if !s.IsUnlocked(multiLink, access.Token) {
+		t.Error("visitor token does not unlock the page")
+	}
+	if wait := s.RetryAfter(multiLink.Slug, visitor); wait != 0 {
+		t.Errorf("visitor retry after = %v, want 0", wait)
+	}
+
+	// После окна адрес снова может вводить пароль
+	now = now.Add(10 * time.Minute)
+	if _, err := s.Unlock(multiLink, attacker, "secret"); err != nil {
+		t.Errorf("attacker after window: %v", err)
+	}
+}
+
+func TestUnlockLockoutIsPerSlugAcrossClients(t *testing.T) {
+	multiLink := protectedMultiLink(t, 1, "demo")
+	other := protectedMultiLink(t, 2, "other")
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	s := newProtectionService(&now, 3, 5)
+
+	// Подбор с множества адресов: каждый адрес ошибается по одному разу
+	// и не достигает лимита адреса, но исчерпывает общий лимит страницы
+	for i := 0; i < 5; i++ {
+		now = start.Add(time.Duration(i) * time.Minute)
+		if _, err := s.Unlock(multiLink, fmt.Sprintf("203.0.113.%d", i+1), "guess"); !errors.Is(err, ErrWrongPassword) {
+			t.Fatalf("attempt %d: error = %v, want ErrWrongPassword", i+1, err)
+		}
+	}
+
+	now = start.Add(6 * time.Minute)
+	const visitor = "198.51.100.20"
+	if _, err := s.Unlock(multiLink, visitor, "secret"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("visitor after page lockout: error = %v, want ErrTooManyAttempts", err)
+	}
+	// Окно страницы открылось первой ошибкой, ожидание считается от него
+	if wait := s.RetryAfter(multiLink.Slug, visitor); wait != 4*time.Minute {
+		t.Errorf("visitor retry after = %v, want 4m", wait)
+	}
+
+	// Лимит одной страницы не затрагивает другие
+	if _, err := s.Unlock(other, visitor, "secret"); err != nil {
+		t.Errorf("other page: %v", err)
+	}
+
+	now = start.Add(10 * time.Minute)
+	if wait := s.RetryAfter(multiLink.Slug, visitor); wait != 0 {
+		t.Errorf("retry after once the window is over = %v, want 0", wait)
+	}
+	if _, err := s.Unlock(multiLink, visitor, "secret"); err != nil {
+		t.Errorf("visitor after window: %v", err)
+	}
+}
+
+func TestRetryAfterReportsLongerLockout(t *testing.T) {
+	multiLink := protectedMultiLink(t, 1, "demo")
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	s := newProtectionService(&now, 2, 4)
+
+	const attacker = "203.0.113.7"
+	tests := []struct {
+		at       time.Duration
+		clientIP string
+		want     time.Duration
+	}{
+		// Первые ошибки открывают окна страницы и адреса attacker
+		{0, "198.51.100.1", 0},
+		{2 * time.Minute, attacker, 0},
+		// Вторая ошибка attacker исчерпывает лимит адреса, окно которого
+		// открылось на второй минуте
+		{3 * time.Minute, attacker, 9 * time.Minute},
+		// Четвертая ошибка исчерпывает лимит страницы, окно которого
+		// открылось раньше: для других адресов ожидание короче
+		{5 * time.Minute, "198.51.100.2", 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		now = start.Add(tt.at)
+		if _, err := s.Unlock(multiLink, tt.clientIP, "guess"); !errors.Is(err, ErrWrongPassword) {
+			t.Fatalf("at %v from %s: error = %v, want ErrWrongPassword", tt.at, tt.clientIP, err)
+		}
+		if wait := s.RetryAfter(multiLink.Slug, tt.clientIP); wait != tt.want {
+			t.Errorf("at %v from %s: retry after = %v, want %v", tt.at, tt.clientIP, wait, tt.want)
+		}
+	}
+
+	// Для attacker действует более долгая блокировка по адресу
+	if wait := s.RetryAfter(multiLink.Slug, attacker); wait != 7*time.Minute {
+		t.Errorf("attacker retry after = %v, want the address lockout of 7m", wait)
+	}
+	if wait := s.RetryAfter(multiLink.Slug, "198.51.100.3"); wait != 5*time.Minute {
+		t.Errorf("new address retry after = %v, want the page lockout of 5m", wait)
+	}
+}
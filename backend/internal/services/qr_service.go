@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"mvp_multylink/backend/internal/colors"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/qr"
+)
+
+// qrMedium отмечает переходы по QR-кодам в статистике UTM
+const qrMedium = "qr"
+
+// QRService строит QR-коды для страниц и кнопок
+type QRService struct {
+	unfurlService *UnfurlService
+	pageBaseURL   string
+	apiBaseURL    string
+}
+
+// NewQRService создает новый экземпляр QRService. Ссылки в кодах строятся
+// от pageBaseURL для страниц и от apiBaseURL для переходов по кнопкам
+func NewQRService(unfurlService *UnfurlService, pageBaseURL, apiBaseURL string) *QRService {
+	return &QRService{
+		unfurlService: unfurlService,
+		pageBaseURL:   strings.TrimSuffix(pageBaseURL, "/"),
+		apiBaseURL:    strings.TrimSuffix(apiBaseURL, "/"),
+	}
+}
+
+// MultiLinkURL возвращает ссылку на публичную страницу с меткой utm_medium=qr
+func (s *QRService) MultiLinkURL(multiLink models.MultiLink) string {
+	return s.pageBaseURL + "/" + url.PathEscape(multiLink.Slug) + "?utm_medium=" + qrMedium
+}
+
+// ButtonURL возвращает ссылку перехода по кнопке с меткой utm_medium=qr.
+// Переход проходит через публичное API, поэтому сканирования учитываются
+// в статистике кликов отдельным каналом
+func (s *QRService) ButtonURL(button models.LinkButton) string {
+	return s.apiBaseURL + "/api/public/click/" + strconv.FormatInt(button.ID, 10) + "?utm_medium=" + qrMedium
+}
+
+// Render строит QR-код по параметрам запроса и возвращает изображение и его
+// Content-Type. Логотип загружается с проверками политики ссылок
+func (s *QRService) Render(ctx context.Context, content string, req models.QRCodeRequest) ([]byte, string, error) {
+	opts := qr.DefaultOptions()
+	if req.Size != 0 {
+		opts.Size = req.Size
+	}
+	if req.Level != "" {
+		opts.Level = req.Level
+	}
+
+	var err error
+	if req.Foreground != "" {
+		if opts.Foreground, err = colors.Parse(req.Foreground); err != nil {
+			return nil, "", fmt.Errorf("%w: %v", qr.ErrInvalidOptions, err)
+		}
+	}
+	if req.Background != "" {
+		if opts.Background, err = colors.Parse(req.Background); err != nil {
+			return nil, "", fmt.Errorf("%w: %v", qr.ErrInvalidOptions, err)
+		}
+	}
+	if req.LogoURL != "" {
+		if opts.Logo, err = s.unfurlService.FetchImage(ctx, req.LogoURL); err != nil {
+			return nil, "", err
+		}
+	}
+
+	format, contentType := qr.FormatPNG, "image/png"
+	if req.Format == qr.FormatSVG {
+		format, contentType = qr.FormatSVG, "image/svg+xml"
+	}
+
+	data, err := qr.Render(content, format, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, contentType, nil
+}
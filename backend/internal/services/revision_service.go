@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// RevisionService сохраняет историю изменений мультиссылок и откатывает их к прежним ревизиям
+type RevisionService struct {
+	uow          repository.UnitOfWork
+	snapshots    snapshotStore
+	revisionRepo repository.RevisionRepository
+	buttons      buttonValidator
+}
+
+// NewRevisionService создает новый экземпляр RevisionService. Политика ссылок,
+// список блокировки и сервис изображений нужны для проверки кнопок при откате
+func NewRevisionService(uow repository.UnitOfWork, multiLinkRepo repository.MultiLinkRepository, buttonRepo repository.ButtonRepository, metricsRepo repository.MetricsRepository, revisionRepo repository.RevisionRepository, urlPolicy *urlpolicy.Policy, blocklist *blocklist.List, images *ImageService) *RevisionService {
+	return &RevisionService{
+		uow:          uow,
+		snapshots:    snapshotStore{multiLinkRepo: multiLinkRepo, buttonRepo: buttonRepo, metricsRepo: metricsRepo},
+		revisionRepo: revisionRepo,
+		buttons:      buttonValidator{urlPolicy: urlPolicy, blocklist: blocklist, images: images},
+	}
+}
+
+// Record сохраняет снимок текущего состояния мультиссылки и ее кнопок.
+// Вызывается внутри транзакции изменения, поэтому ревизия фиксируется
+// только вместе с ним
+func (s *RevisionService) Record(ctx context.Context, multiLinkID int64, action string) (models.MultiLinkRevision, error) {
+	snapshot, err := s.snapshots.load(ctx, multiLinkID)
+	if err != nil {
+		return models.MultiLinkRevision{}, err
+	}
+
+	revision := models.MultiLinkRevision{
+		MultiLinkID: multiLinkID,
+		Action:      action,
+		Snapshot:    snapshot,
+	}
+	if actor, ok := ActorFromContext(ctx); ok {
+		revision.AuthorID = &actor.UserID
+		revision.AuthorName = actor.Username
+	}
+
+	return s.revisionRepo.CreateRevision(ctx, revision)
+}
+
+// GetRevisions получает историю мультиссылки, начиная с последней ревизии,
+// с отличиями каждой ревизии от предыдущей
+func (s *RevisionService) GetRevisions(ctx context.Context, multiLinkID int64) ([]models.RevisionListItem, error) {
+	revisions, err := s.revisionRepo.GetRevisionsByMultiLinkID(ctx, multiLinkID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.RevisionListItem, len(revisions))
+	for i, rev := range revisions {
+		items[i] = models.RevisionListItem{
+			ID:         rev.ID,
+			Revision:   rev.Revision,
+			AuthorID:   rev.AuthorID,
+			AuthorName: rev.AuthorName,
+			Action:     rev.Action,
+			CreatedAt:  rev.CreatedAt,
+		}
+		// Ревизии отсортированы по убыванию, предыдущая идет следующей в списке.
+		// Для первой ревизии сравнивать не с чем
+		if i+1 < len(revisions) {
+			items[i].Diff = DiffSnapshots(revisions[i+1].Snapshot, rev.Snapshot)
+		}
+	}
+	return items, nil
+}
+
+// GetRevision получает ревизию мультиссылки по номеру
+func (s *RevisionService) GetRevision(ctx context.Context, multiLinkID int64, revision int) (models.MultiLinkRevision, error) {
+	return s.revisionRepo.GetRevision(ctx, multiLinkID, revision)
+}
+
+// DeleteRevisionsByMultiLinkID удаляет историю мультиссылки
+func (s *RevisionService) DeleteRevisionsByMultiLinkID(ctx context.Context, multiLinkID int64) error {
+	return s.revisionRepo.DeleteRevisionsByMultiLinkID(ctx, multiLinkID)
+}
+
+// RestoreRevision возвращает мультиссылку и ее кнопки к состоянию ревизии в одной
+// транзакции. Кнопки ревизии проверяются так же, как при сохранении: ссылка,
+// заблокированная после сохранения ревизии, или значок, удаленный из набора,
+// не возвращаются на страницу. Откат сам сохраняется как новая ревизия,
+// которая и возвращается
+func (s *RevisionService) RestoreRevision(ctx context.Context, multiLinkID int64, revision int) (models.MultiLinkRevision, error) {
+	var restored models.MultiLinkRevision
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.snapshots.lock(ctx, multiLinkID); err != nil {
+			return err
+		}
+
+		rev, err := s.revisionRepo.GetRevision(ctx, multiLinkID, revision)
+		if err != nil {
+			return err
+		}
+		if err := s.validateButtons(ctx, multiLinkID, rev.Snapshot.Buttons); err != nil {
+			return err
+		}
+
+		if err := s.snapshots.apply(ctx, rev.Snapshot); err != nil {
+			return err
+		}
+
+		restored, err = s.Record(ctx, multiLinkID, models.RevisionActionRestore)
+		return err
+	})
+	return restored, err
+}
+
+// validateButtons проверяет кнопки ревизии перед откатом. Цвет кнопок
+// сверяется с текущим оформлением страницы: оно не входит в ревизию
+func (s *RevisionService) validateButtons(ctx context.Context, multiLinkID int64, buttons []models.LinkButton) error {
+	multiLink, err := s.snapshots.multiLinkRepo.GetMultiLinkByID(ctx, multiLinkID)
+	if err != nil {
+		return err
+	}
+	pageTheme := ResolveTheme(multiLink)
+
+	for i := range buttons {
+		if err := s.buttons.normalize(&buttons[i], pageTheme); err != nil {
+			return fmt.Errorf("кнопка «%s»: %w", buttons[i].Title, err)
+		}
+	}
+	return nil
+}
+
+// DiffSnapshots вычисляет отличия снимка next от prev
+func DiffSnapshots(prev, next models.RevisionSnapshot) models.RevisionDiff {
+	var diff models.RevisionDiff
+
+	diff.MultiLink = appendChange(diff.MultiLink, "title", prev.MultiLink.Title, next.MultiLink.Title)
+	diff.MultiLink = appendChange(diff.MultiLink, "description", prev.MultiLink.Description, next.MultiLink.Description)
+	diff.MultiLink = appendChange(diff.MultiLink, "slug", prev.MultiLink.Slug, next.MultiLink.Slug)
+	diff.MultiLink = appendChange(diff.MultiLink, "is_active", prev.MultiLink.IsActive, next.MultiLink.IsActive)
+	diff.MultiLink = appendChange(diff.MultiLink, "publish_at", timeValue(prev.MultiLink.PublishAt), timeValue(next.MultiLink.PublishAt))
+	diff.MultiLink = appendChange(diff.MultiLink, "unpublish_at", timeValue(prev.MultiLink.UnpublishAt), timeValue(next.MultiLink.UnpublishAt))
+	diff.MultiLink = appendChange(diff.MultiLink, "sensitive", prev.MultiLink.Sensitive, next.MultiLink.Sensitive)
+	diff.MultiLink = appendChange(diff.MultiLink, "sensitive_reason", prev.MultiLink.SensitiveReason, next.MultiLink.SensitiveReason)
+
+	prevByID := make(map[int64]models.LinkButton, len(prev.Buttons))
+	for _, b := range prev.Buttons {
+		prevByID[b.ID] = b
+	}
+	nextByID := make(map[int64]models.LinkButton, len(next.Buttons))
+	for _, b := range next.Buttons {
+		nextByID[b.ID] = b
+	}
+
+	for _, b := range prev.Buttons {
+		if _, ok := nextByID[b.ID]; !ok {
+			diff.ButtonsRemoved = append(diff.ButtonsRemoved, b)
+		}
+	}
+
+	var prevOrder, nextOrder []int64
+	for _, b := range prev.Buttons {
+		if _, ok := nextByID[b.ID]; ok {
+			prevOrder = append(prevOrder, b.ID)
+		}
+	}
+	for _, b := range next.Buttons {
+		old, ok := prevByID[b.ID]
+		if !ok {
+			diff.ButtonsAdded = append(diff.ButtonsAdded, b)
+			continue
+		}
+		nextOrder = append(nextOrder, b.ID)
+
+		var changes []models.FieldChange
+		changes = appendChange(changes, "kind", old.Kind, b.Kind)
+		changes = appendChange(changes, "title", old.Title, b.Title)
+		changes = appendChange(changes, "url", old.URL, b.URL)
+		changes = appendChange(changes, "icon", old.Icon, b.Icon)
+		changes = appendChange(changes, "color", old.Color, b.Color)
+		changes = appendChange(changes, "is_active", old.IsActive, b.IsActive)
+		changes = appendChange(changes, "visible_from", timeValue(old.VisibleFrom), timeValue(b.VisibleFrom))
+		changes = appendChange(changes, "visible_until", timeValue(old.VisibleUntil), timeValue(b.VisibleUntil))
+		changes = appendChange(changes, "max_clicks", intValue(old.MaxClicks), intValue(b.MaxClicks))
+		changes = appendChange(changes, "expires_at", timeValue(old.ExpiresAt), timeValue(b.ExpiresAt))
+		changes = appendChange(changes, "fallback_url", old.FallbackURL, b.FallbackURL)
+		changes = appendChange(changes, "exhausted_action", old.ExhaustedAction, b.ExhaustedAction)
+		changes = appendChange(changes, "timezone", old.Timezone, b.Timezone)
+		changes = appendChange(changes, "sensitive", old.Sensitive, b.Sensitive)
+		changes = appendChange(changes, "sensitive_reason", old.SensitiveReason, b.SensitiveReason)
+		if len(changes) > 0 {
+			diff.ButtonsChanged = append(diff.ButtonsChanged, models.ButtonChange{
+				ButtonID: b.ID,
+				Title:    b.Title,
+				Changes:  changes,
+			})
+		}
+	}
+
+	// Порядок сравнивается только для кнопок, присутствующих в обоих снимках
+	for i := range nextOrder {
+		if prevOrder[i] != nextOrder[i] {
+			diff.OrderChanged = true
+			break
+		}
+	}
+
+	return diff
+}
+
+// appendChange добавляет изменение поля, если значения отличаются
+func appendChange[T comparable](changes []models.FieldChange, field string, from, to T) []models.FieldChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, models.FieldChange{Field: field, From: from, To: to})
+}
+
+// timeValue представляет необязательное время строкой RFC 3339 для сравнения и вывода
+func timeValue(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// intValue представляет необязательное число строкой для сравнения и вывода
+func intValue(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/theme"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// newRestoreFixture создает мультиссылку с кнопкой и первую ревизию, в которой
+// у мультиссылки другой заголовок, а кнопка ведет на button
+func newRestoreFixture(button models.LinkButton) fakeRepos {
+	f := newFakeRepos()
+	f.store.multiLinks[1] = models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Demo", IsActive: true}
+	f.store.buttons[10] = models.LinkButton{ID: 10, MultiLinkID: 1, Title: "Сайт", URL: "https://example.com", Position: 1, IsActive: true}
+
+	button.ID, button.MultiLinkID, button.Position, button.IsActive = 10, 1, 1, true
+	f.store.revisions[1] = []models.MultiLinkRevision{{
+		ID: 100, MultiLinkID: 1, Revision: 1,
+		Snapshot: models.RevisionSnapshot{
+			MultiLink: models.MultiLink{ID: 1, UserID: 1, Slug: "demo", Title: "Старый заголовок", IsActive: true},
+			Buttons:   []models.LinkButton{button},
+		},
+	}}
+	return f
+}
+
+func TestRestoreRevisionAppliesValidatedSnapshot(t *testing.T) {
+	f := newRestoreFixture(models.LinkButton{Title: "Канал", URL: "https://www.youtube.com/@demo", Color: "#1D4ED8"})
+
+	restored, err := f.revisionService().RestoreRevision(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("RestoreRevision: %v", err)
+	}
+	if restored.Revision != 2 || restored.Action != models.RevisionActionRestore {
+		t.Errorf("restored revision = %d %q, want 2 %q", restored.Revision, restored.Action, models.RevisionActionRestore)
+	}
+	if got := f.store.multiLinks[1].Title; got != "Старый заголовок" {
+		t.Errorf("multilink title = %q, want restored title", got)
+	}
+	button := f.store.buttons[10]
+	if button.URL != "https://www.youtube.com/@demo" {
+		t.Errorf("button url = %q, want restored url", button.URL)
+	}
+	// Кнопка проходит ту же нормализацию, что и при сохранении
+	if button.Icon != "youtube" || button.Color != "#1d4ed8" {
+		t.Errorf("button icon, color = %q, %q, want youtube, #1d4ed8", button.Icon, button.Color)
+	}
+}
+
+func TestRestoreRevisionRejectsButtonsInvalidSinceSave(t *testing.T) {
+	tests := []struct {
+		name    string
+		button  models.LinkButton
+		setup   func(f fakeRepos)
+		wantErr func(err error) bool
+	}{
+		{
+			name:   "domain blocked after the revision was saved",
+			button: models.LinkButton{Title: "Раздача", URL: "https://files.evil.example/get"},
+			setup: func(f fakeRepos) {
+				f.blocklist.SetAdminPatterns([]string{"evil.example"})
+			},
+			wantErr: func(err error) bool { return errors.Is(err, ErrBlockedURL) },
+		},
+		{
+			name:    "icon missing from the built-in set",
+			button:  models.LinkButton{Title: "Сайт", URL: "https://example.com", Icon: "myspace"},
+			wantErr: func(err error) bool { return errors.Is(err, ErrInvalidIcon) },
+		},
+		{
+			name:    "color without contrast to the button text",
+			button:  models.LinkButton{Title: "Сайт", URL: "https://example.com", Color: "#fefefe"},
+			wantErr: func(err error) bool { return errors.Is(err, theme.ErrLowContrast) },
+		},
+		{
+			name:   "scheme no longer allowed by the url policy",
+			button: models.LinkButton{Title: "Скрипт", URL: "javascript:alert(1)"},
+			wantErr: func(err error) bool {
+				var violation *urlpolicy.Violation
+				return errors.As(err, &violation) || errors.Is(err, ErrInvalidButtonPayload)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRestoreFixture(tt.button)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			_, err := f.revisionService().RestoreRevision(context.Background(), 1, 1)
+			if err == nil || !tt.wantErr(err) {
+				t.Fatalf("RestoreRevision error = %v, want validation error", err)
+			}
+
+			if got := f.store.multiLinks[1].Title; got != "Demo" {
+				t.Errorf("multilink title = %q, want unchanged", got)
+			}
+			if got := f.store.buttons[10].URL; got != "https://example.com" {
+				t.Errorf("button url = %q, want unchanged", got)
+			}
+			if len(f.store.revisions[1]) != 1 {
+				t.Errorf("revisions = %d, want no restore revision", len(f.store.revisions[1]))
+			}
+		})
+	}
+}
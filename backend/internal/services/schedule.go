@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// ErrInvalidSchedule возвращается при некорректном расписании мультиссылки или кнопки
+var ErrInvalidSchedule = errors.New("некорректное расписание")
+
+// localTimeLayouts перечисляет форматы времени без смещения, которые
+// интерпретируются в часовом поясе кнопки
+var localTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// IsMultiLinkPublished проверяет, что мультиссылка активна и момент now
+// попадает в интервал [publish_at, unpublish_at)
+func IsMultiLinkPublished(multiLink models.MultiLink, now time.Time) bool {
+	return multiLink.IsActive && inWindow(multiLink.PublishAt, multiLink.UnpublishAt, now)
+}
+
+// IsButtonVisible проверяет, что кнопка активна и момент now попадает
+// в интервал [visible_from, visible_until)
+func IsButtonVisible(button models.LinkButton, now time.Time) bool {
+	return button.IsActive && inWindow(button.VisibleFrom, button.VisibleUntil, now)
+}
+
+func inWindow(from, until *time.Time, now time.Time) bool {
+	if from != nil && now.Before(*from) {
+		return false
+	}
+	if until != nil && !now.Before(*until) {
+		return false
+	}
+	return true
+}
+
+// ValidatePublishWindow проверяет, что снятие с публикации назначено позже публикации
+func ValidatePublishWindow(publishAt, unpublishAt *time.Time) error {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return fmt.Errorf("%w: unpublish_at должен быть позже publish_at", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// ParseButtonWindow разбирает окно видимости кнопки. Время со смещением
+// (RFC 3339) принимается как есть, время без смещения интерпретируется
+// в часовом поясе timezone (IANA, по умолчанию UTC)
+func ParseButtonWindow(visibleFrom, visibleUntil *string, timezone string) (from, until *time.Time, err error) {
+	location := time.UTC
+	if timezone != "" {
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: неизвестный часовой пояс %q", ErrInvalidSchedule, timezone)
+		}
+	}
+
+	if from, err = parseScheduleTime(visibleFrom, location); err != nil {
+		return nil, nil, fmt.Errorf("%w: visible_from: %v", ErrInvalidSchedule, err)
+	}
+	if until, err = parseScheduleTime(visibleUntil, location); err != nil {
+		return nil, nil, fmt.Errorf("%w: visible_until: %v", ErrInvalidSchedule, err)
+	}
+	if from != nil && until != nil && !until.After(*from) {
+		return nil, nil, fmt.Errorf("%w: visible_until должен быть позже visible_from", ErrInvalidSchedule)
+	}
+	return from, until, nil
+}
+
+func parseScheduleTime(value *string, location *time.Location) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, *value); err == nil {
+		return &t, nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, *value, location); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("неверный формат времени %q", *value)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/clock"
+	"mvp_multylink/backend/internal/models"
+)
+
+// newScheduleService возвращает сервис, для которого текущее время — now
+func newScheduleService(now time.Time) *MultiLinkService {
+	f := newFakeRepos()
+	return NewMultiLinkService(f.uow, f.multiLink, f.button, f.metrics, f.draft, f.revisionService(), blocklist.New(""),
+		clock.Func(func() time.Time { return now }))
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestIsPublishedFollowsPublishWindow(t *testing.T) {
+	publishAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	unpublishAt := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		multiLink models.MultiLink
+		now       time.Time
+		want      bool
+	}{
+		{"no schedule", models.MultiLink{IsActive: true}, publishAt, true},
+		{"inactive ignores schedule", models.MultiLink{IsActive: false}, publishAt, false},
+		{"before publish_at", models.MultiLink{IsActive: true, PublishAt: &publishAt}, publishAt.Add(-time.Second), false},
+		{"exactly at publish_at", models.MultiLink{IsActive: true, PublishAt: &publishAt}, publishAt, true},
+		{"inside window", models.MultiLink{IsActive: true, PublishAt: &publishAt, UnpublishAt: &unpublishAt}, publishAt.Add(48 * time.Hour), true},
+		{"just before unpublish_at", models.MultiLink{IsActive: true, PublishAt: &publishAt, UnpublishAt: &unpublishAt}, unpublishAt.Add(-time.Nanosecond), true},
+		{"exactly at unpublish_at", models.MultiLink{IsActive: true, PublishAt: &publishAt, UnpublishAt: &unpublishAt}, unpublishAt, false},
+		{"only unpublish_at, after it", models.MultiLink{IsActive: true, UnpublishAt: &unpublishAt}, unpublishAt.Add(time.Hour), false},
+		// Сравниваются моменты времени, а не показания часов в поясе
+		{"publish_at in another zone", models.MultiLink{IsActive: true, PublishAt: timePtr(publishAt.In(time.FixedZone("UTC+5", 5*3600)))}, publishAt.Add(-time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newScheduleService(tt.now).IsPublished(tt.multiLink); got != tt.want {
+				t.Errorf("IsPublished at %s = %v, want %v", tt.now.Format(time.RFC3339Nano), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsButtonVisibleAcrossTimezoneBoundary(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Кнопка показывается с 18:00 до полуночи по Москве, то есть с 15:00 до 21:00 UTC.
+	// Полночь по Москве наступает, когда в UTC еще тот же день
+	from, until, err := ParseButtonWindow(strPtr("2026-06-15T18:00"), strPtr("2026-06-16T00:00"), "Europe/Moscow")
+	if err != nil {
+		t.Fatalf("ParseButtonWindow: %v", err)
+	}
+	button := models.LinkButton{IsActive: true, VisibleFrom: from, VisibleUntil: until, Timezone: "Europe/Moscow"}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"17:59 Moscow", time.Date(2026, 6, 15, 17, 59, 0, 0, moscow), false},
+		{"18:00 Moscow", time.Date(2026, 6, 15, 18, 0, 0, 0, moscow), true},
+		{"18:00 UTC is 21:00 Moscow", time.Date(2026, 6, 15, 18, 0, 0, 0, time.UTC), true},
+		{"23:59 Moscow", time.Date(2026, 6, 15, 23, 59, 59, 0, moscow), true},
+		{"midnight Moscow", time.Date(2026, 6, 16, 0, 0, 0, 0, moscow), false},
+		{"21:00 UTC is midnight Moscow", time.Date(2026, 6, 15, 21, 0, 0, 0, time.UTC), false},
+		{"23:00 UTC same calendar day", time.Date(2026, 6, 15, 23, 0, 0, 0, time.UTC), false},
+		{"15:00 UTC is 18:00 Moscow", time.Date(2026, 6, 15, 15, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newScheduleService(tt.now).IsButtonVisible(button); got != tt.want {
+				t.Errorf("IsButtonVisible at %s = %v, want %v", tt.now.UTC().Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+
+	button.IsActive = false
+	if newScheduleService(time.Date(2026, 6, 15, 20, 0, 0, 0, moscow)).IsButtonVisible(button) {
+		t.Error("inactive button must stay hidden inside its window")
+	}
+}
+
+func TestParseButtonWindow(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		from      *string
+		until     *string
+		timezone  string
+		wantFrom  time.Time
+		wantUntil time.Time
+		wantErr   bool
+	}{
+		{
+			name: "local time in zone", from: strPtr("2026-06-15T18:00"), until: strPtr("2026-06-16 00:00"), timezone: "Europe/Moscow",
+			wantFrom: time.Date(2026, 6, 15, 15, 0, 0, 0, time.UTC), wantUntil: time.Date(2026, 6, 15, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			// В марте Нью-Йорк переходит на летнее время, смещение меняется с -5 на -4
+			name: "window across DST change", from: strPtr("2026-03-07T18:00"), until: strPtr("2026-03-08T18:00"), timezone: "America/New_York",
+			wantFrom: time.Date(2026, 3, 7, 18, 0, 0, 0, newYork), wantUntil: time.Date(2026, 3, 8, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "offset wins over timezone", from: strPtr("2026-06-15T18:00:00+05:00"), timezone: "Europe/Moscow",
+			wantFrom: time.Date(2026, 6, 15, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "utc by default", from: strPtr("2026-06-15T18:00"),
+			wantFrom: time.Date(2026, 6, 15, 18, 0, 0, 0, time.UTC),
+		},
+		{name: "unknown timezone", from: strPtr("2026-06-15T18:00"), timezone: "Mars/Olympus", wantErr: true},
+		{name: "until not after from", from: strPtr("2026-06-15T18:00"), until: strPtr("2026-06-15T18:00"), wantErr: true},
+		{name: "bad format", from: strPtr("15.06.2026 18:00"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, until, err := ParseButtonWindow(tt.from, tt.until, tt.timezone)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseButtonWindow = %v, %v, want error", from, until)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseButtonWindow: %v", err)
+			}
+			if !sameInstant(from, tt.wantFrom) || !sameInstant(until, tt.wantUntil) {
+				t.Errorf("ParseButtonWindow = %v, %v, want %v, %v", from, until, tt.wantFrom, tt.wantUntil)
+			}
+		})
+	}
+}
+
+// sameInstant сравнивает необязательное время с ожидаемым; нулевое ожидание означает nil
+func sameInstant(got *time.Time, want time.Time) bool {
+	if want.IsZero() {
+		return got == nil
+	}
+	return got != nil && got.Equal(want)
+}
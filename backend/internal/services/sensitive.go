@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+
+	"mvp_multylink/backend/internal/models"
+)
+
+// NormalizeSensitive приводит отметку чувствительного содержимого к
+// согласованному виду: без отметки причина не хранится, а отметка без
+// причины получает категорию "other"
+func NormalizeSensitive(sensitive bool, reason string) (bool, string) {
+	if !sensitive {
+		return false, ""
+	}
+	if reason == "" {
+		reason = models.SensitiveReasonOther
+	}
+	return true, reason
+}
+
+// MultiLinkSensitiveReason возвращает категорию чувствительного содержимого
+// страницы. Отметка администратора имеет приоритет над отметкой владельца
+func MultiLinkSensitiveReason(multiLink models.MultiLink) (string, bool) {
+	if multiLink.ForcedSensitiveReason != "" {
+		return multiLink.ForcedSensitiveReason, true
+	}
+	if multiLink.Sensitive {
+		return multiLink.SensitiveReason, true
+	}
+	return "", false
+}
+
+// ButtonSensitiveReason возвращает категорию, подтверждение которой нужно
+// для перехода по кнопке: категорию страницы или самой кнопки
+func ButtonSensitiveReason(multiLink models.MultiLink, button models.LinkButton) (string, bool) {
+	if reason, ok := MultiLinkSensitiveReason(multiLink); ok {
+		return reason, true
+	}
+	if button.Sensitive {
+		return button.SensitiveReason, true
+	}
+	return "", false
+}
+
+// NeutralMultiLink возвращает страницу без содержимого для предпросмотра
+// чувствительной страницы поисковыми роботами и мессенджерами
+func NeutralMultiLink(multiLink models.MultiLink) models.MultiLink {
+	return models.MultiLink{
+		ID:        multiLink.ID,
+		Slug:      multiLink.Slug,
+		Title:     "Содержимое с ограничениями",
+		IsActive:  true,
+		Sensitive: true,
+	}
+}
+
+// SetForcedSensitive принудительно отмечает страницу как чувствительную.
+// Пустая причина снимает отметку администратора
+func (s *MultiLinkService) SetForcedSensitive(ctx context.Context, id int64, reason string) error {
+	return s.multiLinkRepo.SetForcedSensitiveReason(ctx, id, reason)
+}
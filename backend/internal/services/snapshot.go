@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// snapshotStore читает и записывает состояние мультиссылки вместе с кнопками.
+// Используется историей изменений и черновиками
+type snapshotStore struct {
+	multiLinkRepo repository.MultiLinkRepository
+	buttonRepo    repository.ButtonRepository
+	metricsRepo   repository.MetricsRepository
+}
+
+// load получает текущее состояние мультиссылки и ее кнопок в порядке отображения
+func (s snapshotStore) load(ctx context.Context, multiLinkID int64) (models.RevisionSnapshot, error) {
+	multiLink, err := s.multiLinkRepo.GetMultiLinkByID(ctx, multiLinkID)
+	if err != nil {
+		return models.RevisionSnapshot{}, err
+	}
+
+	buttons, err := s.buttonRepo.GetButtonsByMultiLinkID(ctx, multiLinkID)
+	if err != nil {
+		return models.RevisionSnapshot{}, err
+	}
+
+	return models.RevisionSnapshot{MultiLink: multiLink, Buttons: buttons}, nil
+}
+
+// lock блокирует мультиссылку до завершения транзакции, чтобы параллельные
+// изменения черновика, публикация и откат не перезаписывали друг друга.
+// Должен вызываться внутри транзакции до чтения черновика или снимка
+func (s snapshotStore) lock(ctx context.Context, multiLinkID int64) error {
+	return s.multiLinkRepo.LockMultiLink(ctx, multiLinkID)
+}
+
+// apply переписывает мультиссылку и ее кнопки по снимку. Должен вызываться
+// внутри транзакции
+func (s snapshotStore) apply(ctx context.Context, snapshot models.RevisionSnapshot) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	if err := s.applyMultiLink(ctx, snapshot.MultiLink, now); err != nil {
+		return err
+	}
+	return s.applyButtons(ctx, snapshot.MultiLink.ID, snapshot.Buttons, now)
+}
+
+// applyMultiLink возвращает поля мультиссылки к снимку
+func (s snapshotStore) applyMultiLink(ctx context.Context, snapshot models.MultiLink, now time.Time) error {
+	current, err := s.multiLinkRepo.GetMultiLinkByID(ctx, snapshot.ID)
+	if err != nil {
+		return err
+	}
+
+	// Прежний slug мог занять кто-то другой
+	if snapshot.Slug != current.Slug {
+		taken, err := s.multiLinkRepo.CheckSlugExists(ctx, snapshot.Slug)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrSlugTaken
+		}
+	}
+
+	current.Title = snapshot.Title
+	current.Description = snapshot.Description
+	current.Slug = snapshot.Slug
+	current.IsActive = snapshot.IsActive
+	current.PublishAt = snapshot.PublishAt
+	current.UnpublishAt = snapshot.UnpublishAt
+	current.Sensitive = snapshot.Sensitive
+	current.SensitiveReason = snapshot.SensitiveReason
+	current.UpdatedAt = now
+	return s.multiLinkRepo.UpdateMultiLink(ctx, current)
+}
+
+// applyButtons переписывает кнопки мультиссылки по снимку. Кнопки, которых нет
+// в снимке, перемещаются в корзину; кнопки из корзины восстанавливаются; кнопки,
+// удаленные окончательно или еще не созданные (ID <= 0), создаются заново
+func (s snapshotStore) applyButtons(ctx context.Context, multiLinkID int64, snapshot []models.LinkButton, now time.Time) error {
+	live, err := s.buttonRepo.GetButtonsByMultiLinkID(ctx, multiLinkID)
+	if err != nil {
+		return err
+	}
+
+	liveIDs := make(map[int64]bool, len(live))
+	for _, b := range live {
+		liveIDs[b.ID] = true
+	}
+	keep := make(map[int64]bool, len(snapshot))
+	for _, b := range snapshot {
+		keep[b.ID] = true
+	}
+
+	// Кнопки, которых нет в снимке, перемещаются в корзину
+	for _, b := range live {
+		if !keep[b.ID] {
+			if err := s.buttonRepo.SoftDeleteButton(ctx, b.ID, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, b := range snapshot {
+		b.MultiLinkID = multiLinkID
+		b.UpdatedAt = now
+		b.DeletedAt = nil
+
+		if b.ID <= 0 {
+			if err := s.createButton(ctx, b, now); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !liveIDs[b.ID] {
+			deleted, err := s.buttonRepo.GetDeletedButtonByID(ctx, b.ID)
+			switch {
+			case err == nil && deleted.MultiLinkID == multiLinkID:
+				if err := s.buttonRepo.RestoreButton(ctx, b.ID); err != nil {
+					return err
+				}
+			case err == nil || errors.Is(err, repository.ErrNotFound):
+				if err := s.createButton(ctx, b, now); err != nil {
+					return err
+				}
+				continue
+			default:
+				return err
+			}
+		}
+
+		if err := s.buttonRepo.UpdateButton(ctx, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createButton создает кнопку из снимка вместе с пустой метрикой
+func (s snapshotStore) createButton(ctx context.Context, button models.LinkButton, now time.Time) error {
+	button.CreatedAt = now
+	id, err := s.buttonRepo.CreateButton(ctx, button)
+	if err != nil {
+		return err
+	}
+	_, err = s.metricsRepo.CreateLinkMetrics(ctx, models.LinkMetrics{LinkButtonID: id})
+	return err
+}
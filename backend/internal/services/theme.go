@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/theme"
+)
+
+// ResolveTheme возвращает оформление страницы. Если сохраненное оформление
+// перестало проходить проверку (например, пресет удален), используется
+// пресет по умолчанию
+func ResolveTheme(multiLink models.MultiLink) models.Theme {
+	resolved, err := theme.Resolve(multiLink.ThemePreset, multiLink.ThemeOverrides)
+	if err != nil {
+		resolved, _ = theme.Resolve(theme.DefaultPreset, models.ThemeOverrides{})
+	}
+	return resolved
+}
+
+// SetTheme проверяет и сохраняет оформление страницы. Изменение применяется
+// сразу, без публикации черновика
+func (s *MultiLinkService) SetTheme(ctx context.Context, id int64, req models.UpdateThemeRequest) (models.Theme, error) {
+	resolved, err := theme.Resolve(req.Preset, req.Overrides)
+	if err != nil {
+		return models.Theme{}, err
+	}
+
+	// Сохраняются нормализованные переопределения: цвета в виде #rrggbb
+	overrides := req.Overrides
+	for _, c := range []struct {
+		target *string
+		value  string
+	}{
+		{&overrides.BackgroundColor, resolved.BackgroundColor},
+		{&overrides.TextColor, resolved.TextColor},
+		{&overrides.ButtonColor, resolved.ButtonColor},
+		{&overrides.ButtonTextColor, resolved.ButtonTextColor},
+	} {
+		if *c.target != "" {
+			*c.target = c.value
+		}
+	}
+
+	if err := s.multiLinkRepo.SetMultiLinkTheme(ctx, id, resolved.Preset, overrides); err != nil {
+		return models.Theme{}, err
+	}
+	return resolved, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mvp_multylink/backend/internal/logging"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/repository"
+)
+
+// TrashService предоставляет методы для работы с корзиной: просмотр,
+// восстановление и окончательное удаление по истечении срока хранения
+type TrashService struct {
+	uow              repository.UnitOfWork
+	multiLinkRepo    repository.MultiLinkRepository
+	buttonRepo       repository.ButtonRepository
+	multiLinkService *MultiLinkService
+	buttonService    *ButtonService
+}
+
+// NewTrashService создает новый экземпляр TrashService
+func NewTrashService(uow repository.UnitOfWork, multiLinkRepo repository.MultiLinkRepository, buttonRepo repository.ButtonRepository, multiLinkService *MultiLinkService, buttonService *ButtonService) *TrashService {
+	return &TrashService{
+		uow:              uow,
+		multiLinkRepo:    multiLinkRepo,
+		buttonRepo:       buttonRepo,
+		multiLinkService: multiLinkService,
+		buttonService:    buttonService,
+	}
+}
+
+// GetTrash получает содержимое корзины пользователя. Кнопки удаленных
+// мультиссылок не выводятся отдельно: они восстанавливаются вместе с мультиссылкой
+func (s *TrashService) GetTrash(ctx context.Context, userID int64) (models.TrashResponse, error) {
+	multiLinks, err := s.multiLinkRepo.GetDeletedMultiLinksByUserID(ctx, userID)
+	if err != nil {
+		return models.TrashResponse{}, err
+	}
+
+	buttons, err := s.buttonRepo.GetDeletedButtonsByUserID(ctx, userID)
+	if err != nil {
+		return models.TrashResponse{}, err
+	}
+
+	return models.TrashResponse{
+		MultiLinks: multiLinks,
+		Buttons:    buttons,
+	}, nil
+}
+
+// GetDeletedMultiLinkByID получает мультиссылку из корзины по ID
+func (s *TrashService) GetDeletedMultiLinkByID(ctx context.Context, id int64) (models.MultiLink, error) {
+	return s.multiLinkRepo.GetDeletedMultiLinkByID(ctx, id)
+}
+
+// GetDeletedButtonByID получает кнопку из корзины по ID
+func (s *TrashService) GetDeletedButtonByID(ctx context.Context, id int64) (models.LinkButton, error) {
+	return s.buttonRepo.GetDeletedButtonByID(ctx, id)
+}
+
+// GetButtonOwnerMultiLink получает мультиссылку удаленной кнопки, в том числе
+// если мультиссылка сама находится в корзине
+func (s *TrashService) GetButtonOwnerMultiLink(ctx context.Context, button models.LinkButton) (models.MultiLink, error) {
+	multiLink, err := s.multiLinkRepo.GetMultiLinkByID(ctx, button.MultiLinkID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return s.multiLinkRepo.GetDeletedMultiLinkByID(ctx, button.MultiLinkID)
+	}
+	return multiLink, err
+}
+
+// RestoreMultiLink восстанавливает мультиссылку вместе с кнопками, удаленными
+// одновременно с ней. Кнопки, удаленные раньше по отдельности, остаются в корзине
+func (s *TrashService) RestoreMultiLink(ctx context.Context, id int64) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		multiLink, err := s.multiLinkRepo.GetDeletedMultiLinkByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Пока мультиссылка была в корзине, ее slug мог занять кто-то другой
+		taken, err := s.multiLinkRepo.CheckSlugExists(ctx, multiLink.Slug)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrSlugTaken
+		}
+
+		if err := s.multiLinkRepo.RestoreMultiLink(ctx, id); err != nil {
+			return err
+		}
+		return s.buttonRepo.RestoreButtonsByMultiLinkID(ctx, id, *multiLink.DeletedAt)
+	})
+}
+
+// RestoreButton восстанавливает кнопку из корзины. Кнопку удаленной
+// мультиссылки можно восстановить только вместе с мультиссылкой
+func (s *TrashService) RestoreButton(ctx context.Context, id int64) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		button, err := s.buttonRepo.GetDeletedButtonByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.multiLinkRepo.GetMultiLinkByID(ctx, button.MultiLinkID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrParentDeleted
+			}
+			return err
+		}
+
+		return s.buttonRepo.RestoreButton(ctx, id)
+	})
+}
+
+// PurgeExpired окончательно удаляет мультиссылки и кнопки, находящиеся
+// в корзине дольше retention. Возвращает количество удаленных записей
+func (s *TrashService) PurgeExpired(ctx context.Context, retention time.Duration) (int, error) {
+	before := time.Now().UTC().Add(-retention)
+	purged := 0
+
+	multiLinkIDs, err := s.multiLinkRepo.GetMultiLinkIDsDeletedBefore(ctx, before)
+	if err != nil {
+		return purged, err
+	}
+	for _, id := range multiLinkIDs {
+		if err := s.multiLinkService.PurgeMultiLink(ctx, id); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return purged, err
+		}
+		purged++
+	}
+
+	// Кнопки удаленных мультиссылок уже удалены вместе с ними выше
+	buttonIDs, err := s.buttonRepo.GetButtonIDsDeletedBefore(ctx, before)
+	if err != nil {
+		return purged, err
+	}
+	for _, id := range buttonIDs {
+		if err := s.buttonService.PurgeButton(ctx, id); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// RunPurger периодически запускает PurgeExpired до отмены ctx
+func (s *TrashService) RunPurger(ctx context.Context, interval, retention time.Duration) {
+	logger := logging.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpired(ctx, retention)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.Error("trash purge failed", "error", err, "purged", purged)
+		case purged > 0:
+			logger.Info("trash purged", "purged", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"image"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/logging"
+	"mvp_multylink/backend/internal/models"
+	"mvp_multylink/backend/internal/unfurl"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// maxPrefillLength соответствует длине колонок title и icon кнопок
+const maxPrefillLength = 255
+
+// UnfurlService получает предпросмотр ссылок и заполняет по нему
+// заголовок и иконку новых кнопок
+type UnfurlService struct {
+	client    *unfurl.Client
+	urlPolicy *urlpolicy.Policy
+	blocklist *blocklist.List
+}
+
+// NewUnfurlService создает новый экземпляр UnfurlService
+func NewUnfurlService(client *unfurl.Client, urlPolicy *urlpolicy.Policy, blocklist *blocklist.List) *UnfurlService {
+	return &UnfurlService{
+		client:    client,
+		urlPolicy: urlPolicy,
+		blocklist: blocklist,
+	}
+}
+
+// Unfurl получает предпросмотр страницы. Ссылка проверяется политикой
+// исходящих ссылок и списком блокировки до загрузки
+func (s *UnfurlService) Unfurl(ctx context.Context, rawURL string) (unfurl.Preview, error) {
+	target, err := s.checkURL(rawURL)
+	if err != nil {
+		return unfurl.Preview{}, err
+	}
+	return s.client.Unfurl(ctx, target)
+}
+
+// FetchImage загружает изображение по ссылке с теми же проверками, что и Unfurl
+func (s *UnfurlService) FetchImage(ctx context.Context, rawURL string) (image.Image, error) {
+	target, err := s.checkURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.FetchImage(ctx, target)
+}
+
+// checkURL нормализует ссылку и проверяет ее политикой исходящих ссылок
+// и списком блокировки
+func (s *UnfurlService) checkURL(rawURL string) (string, error) {
+	button := models.LinkButton{Kind: models.ButtonKindURL, URL: rawURL, Title: rawURL}
+	if err := NormalizeButton(&button); err != nil {
+		return "", err
+	}
+	if err := s.urlPolicy.Check(button.URL); err != nil {
+		return "", err
+	}
+	if err := checkBlocked(s.blocklist, button); err != nil {
+		return "", err
+	}
+	return button.URL, nil
+}
+
+// Prefill заполняет пустой заголовок кнопки-ссылки по предпросмотру страницы.
+// Иконку не трогает: её подбирает по адресу встроенный набор иконок.
+// Ошибки загрузки не прерывают создание кнопки: недостающие поля проверит
+// валидация черновика
+func (s *UnfurlService) Prefill(ctx context.Context, req *models.CreateLinkButtonRequest) {
+	if (req.Kind != "" && req.Kind != models.ButtonKindURL) || req.Title != "" {
+		return
+	}
+
+	preview, err := s.Unfurl(ctx, req.URL)
+	if err != nil {
+		logging.FromContext(ctx).Debug("button prefill skipped", "url", req.URL, "error", err)
+		return
+	}
+
+	req.Title = truncateRunes(preview.Title, maxPrefillLength)
+}
+
+// truncateRunes обрезает строку до n символов
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mvp_multylink/backend/internal/blocklist"
+	"mvp_multylink/backend/internal/unfurl"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+func TestUnfurlRefusesInternalURLs(t *testing.T) {
+	var requested bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requested = true
+		_, _ = w.Write([]byte("<title>internal</title>"))
+	}))
+	defer server.Close()
+
+	// Клиент сам разрешает внутренние адреса: отказ должен дать политика ссылок
+	client := unfurl.New(unfurl.Options{Timeout: time.Second, MaxBodyBytes: 1 << 20, MaxRedirects: 3, CacheSize: 8, CacheTTL: time.Minute, AllowPrivate: true})
+	s := NewUnfurlService(client, urlpolicy.New([]string{"http", "https"}, 2048), blocklist.New(""))
+
+	for _, rawURL := range []string{server.URL, "http://localhost/", "http://10.0.0.1/", "http://169.254.169.254/latest/meta-data"} {
+		_, err := s.Unfurl(context.Background(), rawURL)
+		var violation *urlpolicy.Violation
+		if !errors.As(err, &violation) {
+			t.Errorf("Unfurl(%q) error = %v, want policy violation", rawURL, err)
+		}
+	}
+	if _, err := s.Unfurl(context.Background(), "file:///etc/passwd"); err == nil {
+		t.Error("Unfurl accepted a file URL")
+	}
+	if requested {
+		t.Error("request reached an internal server")
+	}
+}
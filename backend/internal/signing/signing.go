@@ -0,0 +1,78 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken возвращается, если токен поврежден или подписан другим ключом
+	ErrInvalidToken = errors.New("недействительный токен")
+
+	// ErrExpiredToken возвращается, если срок действия токена истек
+	ErrExpiredToken = errors.New("срок действия токена истек")
+)
+
+// Signer выпускает и проверяет короткоживущие токены вида
+// base64url(subject|expires).base64url(HMAC-SHA256)
+type Signer struct {
+	key []byte
+}
+
+// NewSigner создает новый экземпляр Signer. Ключ выводится из секрета и
+// назначения, поэтому токен одного назначения не подходит для другого
+func NewSigner(secret, purpose string) *Signer {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return &Signer{key: mac.Sum(nil)}
+}
+
+// Sign выпускает токен для subject, действующий до expiresAt
+func (s *Signer) Sign(subject string, expiresAt time.Time) string {
+	payload := subject + "|" + strconv.FormatInt(expiresAt.Unix(), 10)
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded))
+}
+
+// Verify проверяет подпись и срок действия токена и возвращает subject
+func (s *Signer) Verify(token string, now time.Time) (string, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return "", ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	sep := strings.LastIndexByte(string(payload), '|')
+	if sep < 0 {
+		return "", ErrInvalidToken
+	}
+	subject := string(payload[:sep])
+	unix, err := strconv.ParseInt(string(payload[sep+1:]), 10, 64)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	if !now.Before(time.Unix(unix, 0)) {
+		return "", ErrExpiredToken
+	}
+
+	return subject, nil
+}
+
+func (s *Signer) mac(data string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,179 @@
+// Package theme содержит пресеты оформления страниц и проверку
+// пользовательских цветов: формат и контраст по WCAG 2
+package theme
+
+import (
+	"errors"
+	"fmt"
+
+	"mvp_multylink/backend/internal/colors"
+	"mvp_multylink/backend/internal/models"
+)
+
+// DefaultPreset используется для страниц, у которых пресет не выбран
+const DefaultPreset = "light"
+
+// MinContrast — минимальный контраст текста и фона по WCAG 2, уровень AA
+const MinContrast = 4.5
+
+var (
+	// ErrInvalidTheme возвращается для неизвестного пресета, шрифта, формы
+	// кнопок или цвета в неверном формате
+	ErrInvalidTheme = errors.New("некорректное оформление")
+
+	// ErrLowContrast возвращается, если текст недостаточно контрастен фону
+	ErrLowContrast = errors.New("недостаточный контраст цветов")
+)
+
+// fonts сопоставляет шрифты оформления значениям CSS font-family
+var fonts = map[string]string{
+	"system":  `system-ui, -apple-system, "Segoe UI", Roboto, sans-serif`,
+	"serif":   `Georgia, "Times New Roman", serif`,
+	"mono":    `ui-monospace, "SF Mono", Menlo, Consolas, monospace`,
+	"rounded": `ui-rounded, "Nunito", "Varela Round", system-ui, sans-serif`,
+}
+
+var shapes = map[string]bool{
+	models.ButtonShapeSquare:  true,
+	models.ButtonShapeRounded: true,
+	models.ButtonShapePill:    true,
+}
+
+// presets содержит подобранные сочетания цветов, формы кнопок и шрифта.
+// Все пресеты проходят проверку контраста
+var presets = []models.Theme{
+	{Preset: "light", Mode: models.ThemeModeLight, BackgroundColor: "#ffffff", TextColor: "#111827",
+		ButtonColor: "#111827", ButtonTextColor: "#ffffff", ButtonShape: models.ButtonShapeRounded, Font: "system"},
+	{Preset: "dark", Mode: models.ThemeModeDark, BackgroundColor: "#111827", TextColor: "#f9fafb",
+		ButtonColor: "#f9fafb", ButtonTextColor: "#111827", ButtonShape: models.ButtonShapeRounded, Font: "system"},
+	{Preset: "ocean", Mode: models.ThemeModeLight, BackgroundColor: "#e0f2fe", TextColor: "#0c4a6e",
+		ButtonColor: "#0369a1", ButtonTextColor: "#ffffff", ButtonShape: models.ButtonShapePill, Font: "rounded"},
+	{Preset: "sunset", Mode: models.ThemeModeLight, BackgroundColor: "#fff7ed", TextColor: "#7c2d12",
+		ButtonColor: "#c2410c", ButtonTextColor: "#ffffff", ButtonShape: models.ButtonShapePill, Font: "serif"},
+	{Preset: "forest", Mode: models.ThemeModeDark, BackgroundColor: "#052e16", TextColor: "#dcfce7",
+		ButtonColor: "#bbf7d0", ButtonTextColor: "#052e16", ButtonShape: models.ButtonShapeRounded, Font: "system"},
+	{Preset: "mono", Mode: models.ThemeModeLight, BackgroundColor: "#fafafa", TextColor: "#171717",
+		ButtonColor: "#171717", ButtonTextColor: "#fafafa", ButtonShape: models.ButtonShapeSquare, Font: "mono"},
+}
+
+func init() {
+	for i := range presets {
+		presets[i].FontFamily = fonts[presets[i].Font]
+		if err := check(presets[i]); err != nil {
+			panic(fmt.Sprintf("theme: пресет %q: %v", presets[i].Preset, err))
+		}
+	}
+}
+
+// Presets возвращает доступные пресеты
+func Presets() []models.Theme {
+	return append([]models.Theme(nil), presets...)
+}
+
+// Resolve применяет переопределения к пресету и проверяет результат.
+// Пустое имя пресета означает DefaultPreset
+func Resolve(preset string, overrides models.ThemeOverrides) (models.Theme, error) {
+	if preset == "" {
+		preset = DefaultPreset
+	}
+	var t models.Theme
+	found := false
+	for _, p := range presets {
+		if p.Preset == preset {
+			t, found = p, true
+			break
+		}
+	}
+	if !found {
+		return models.Theme{}, fmt.Errorf("%w: неизвестный пресет %q", ErrInvalidTheme, preset)
+	}
+
+	if overrides.Mode != "" {
+		if overrides.Mode != models.ThemeModeLight && overrides.Mode != models.ThemeModeDark {
+			return models.Theme{}, fmt.Errorf("%w: неизвестный режим %q", ErrInvalidTheme, overrides.Mode)
+		}
+		t.Mode = overrides.Mode
+	}
+	if overrides.ButtonShape != "" {
+		if !shapes[overrides.ButtonShape] {
+			return models.Theme{}, fmt.Errorf("%w: неизвестная форма кнопок %q", ErrInvalidTheme, overrides.ButtonShape)
+		}
+		t.ButtonShape = overrides.ButtonShape
+	}
+	if overrides.Font != "" {
+		if _, ok := fonts[overrides.Font]; !ok {
+			return models.Theme{}, fmt.Errorf("%w: неизвестный шрифт %q", ErrInvalidTheme, overrides.Font)
+		}
+		t.Font = overrides.Font
+		t.FontFamily = fonts[overrides.Font]
+	}
+
+	for _, c := range []struct {
+		target *string
+		value  string
+	}{
+		{&t.BackgroundColor, overrides.BackgroundColor},
+		{&t.TextColor, overrides.TextColor},
+		{&t.ButtonColor, overrides.ButtonColor},
+		{&t.ButtonTextColor, overrides.ButtonTextColor},
+	} {
+		if c.value == "" {
+			continue
+		}
+		normalized, err := NormalizeColor(c.value)
+		if err != nil {
+			return models.Theme{}, err
+		}
+		*c.target = normalized
+	}
+
+	if err := check(t); err != nil {
+		return models.Theme{}, err
+	}
+	return t, nil
+}
+
+// NormalizeColor проверяет цвет и приводит его к виду #rrggbb
+func NormalizeColor(value string) (string, error) {
+	c, err := colors.Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+	return colors.Hex(c), nil
+}
+
+// CheckButtonColor проверяет собственный цвет кнопки: формат и контраст
+// с цветом текста кнопок оформления. Возвращает цвет в виде #rrggbb
+func CheckButtonColor(t models.Theme, buttonColor string) (string, error) {
+	normalized, err := NormalizeColor(buttonColor)
+	if err != nil {
+		return "", err
+	}
+	if err := checkPair("цвет кнопки и текст кнопки", normalized, t.ButtonTextColor); err != nil {
+		return "", err
+	}
+	return normalized, nil
+}
+
+// check проверяет контраст текста страницы и текста кнопок
+func check(t models.Theme) error {
+	if err := checkPair("текст и фон страницы", t.TextColor, t.BackgroundColor); err != nil {
+		return err
+	}
+	return checkPair("текст и фон кнопок", t.ButtonTextColor, t.ButtonColor)
+}
+
+func checkPair(what, a, b string) error {
+	ca, err := colors.Parse(a)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+	cb, err := colors.Parse(b)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTheme, err)
+	}
+	if ratio := colors.Contrast(ca, cb); ratio < MinContrast {
+		return fmt.Errorf("%w: %s — %.2f:1, нужно не меньше %.1f:1", ErrLowContrast, what, ratio, MinContrast)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package theme
+
+import (
+	"errors"
+	"testing"
+
+	"mvp_multylink/backend/internal/colors"
+	"mvp_multylink/backend/internal/models"
+)
+
+func TestPresetsPassContrastCheck(t *testing.T) {
+	list := Presets()
+	if len(list) == 0 {
+		t.Fatal("no presets")
+	}
+	for _, p := range list {
+		t.Run(p.Preset, func(t *testing.T) {
+			resolved, err := Resolve(p.Preset, models.ThemeOverrides{})
+			if err != nil {
+				t.Fatalf("Resolve(%q): %v", p.Preset, err)
+			}
+			if resolved != p {
+				t.Errorf("Resolve(%q) = %+v, want the preset unchanged %+v", p.Preset, resolved, p)
+			}
+			if resolved.FontFamily == "" || !shapes[resolved.ButtonShape] {
+				t.Errorf("preset %q has font family %q and shape %q", p.Preset, resolved.FontFamily, resolved.ButtonShape)
+			}
+			for _, pair := range [][2]string{{p.TextColor, p.BackgroundColor}, {p.ButtonTextColor, p.ButtonColor}} {
+				if ratio := contrast(t, pair[0], pair[1]); ratio < MinContrast {
+					t.Errorf("preset %q: contrast of %s on %s = %.2f, want at least %.1f", p.Preset, pair[0], pair[1], ratio, MinContrast)
+				}
+			}
+		})
+	}
+
+	if resolved, err := Resolve("", models.ThemeOverrides{}); err != nil || resolved.Preset != DefaultPreset {
+		t.Errorf("Resolve(\"\") = %q, %v, want %q", resolved.Preset, err, DefaultPreset)
+	}
+}
+
+func TestResolveOverrides(t *testing.T) {
+	resolved, err := Resolve("light", models.ThemeOverrides{
+		BackgroundColor: "#FFF",
+		TextColor:       "1f2937",
+		ButtonShape:     models.ButtonShapePill,
+		Font:            "serif",
+	})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if resolved.BackgroundColor != "#ffffff" || resolved.TextColor != "#1f2937" {
+		t.Errorf("colors = %s, %s, want normalized #ffffff, #1f2937", resolved.BackgroundColor, resolved.TextColor)
+	}
+	if resolved.ButtonShape != models.ButtonShapePill || resolved.Font != "serif" || resolved.FontFamily != fonts["serif"] {
+		t.Errorf("shape, font = %q, %q (%q), want pill, serif", resolved.ButtonShape, resolved.Font, resolved.FontFamily)
+	}
+}
+
+func TestResolveRejectsInvalidTheme(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		overrides models.ThemeOverrides
+		wantErr   error
+	}{
+		{"unknown preset", "neon", models.ThemeOverrides{}, ErrInvalidTheme},
+		{"unknown mode", "light", models.ThemeOverrides{Mode: "sepia"}, ErrInvalidTheme},
+		{"unknown font", "light", models.ThemeOverrides{Font: "comic-sans"}, ErrInvalidTheme},
+		{"font family instead of name", "light", models.ThemeOverrides{Font: "Georgia, serif"}, ErrInvalidTheme},
+		{"unknown shape", "light", models.ThemeOverrides{ButtonShape: "circle"}, ErrInvalidTheme},
+		{"shape in other case", "light", models.ThemeOverrides{ButtonShape: "Pill"}, ErrInvalidTheme},
+
+		// Цвета принимаются только в шестнадцатеричной записи
+		{"named color", "light", models.ThemeOverrides{TextColor: "red"}, ErrInvalidTheme},
+		{"rgb function", "light", models.ThemeOverrides{TextColor: "rgb(0, 0, 0)"}, ErrInvalidTheme},
+		{"non-hex digits", "light", models.ThemeOverrides{TextColor: "#gggggg"}, ErrInvalidTheme},
+		{"alpha channel", "light", models.ThemeOverrides{TextColor: "#000000ff"}, ErrInvalidTheme},
+		{"four digits", "light", models.ThemeOverrides{TextColor: "#0000"}, ErrInvalidTheme},
+		{"css injection", "light", models.ThemeOverrides{BackgroundColor: "#fff;}"}, ErrInvalidTheme},
+		{"hex prefix", "light", models.ThemeOverrides{TextColor: "0x0000"}, ErrInvalidTheme},
+
+		// Пары цветов с контрастом ниже 4.5:1
+		{"gray text on white", "light", models.ThemeOverrides{TextColor: "#999999"}, ErrLowContrast},
+		{"text equals background", "light", models.ThemeOverrides{TextColor: "#ffffff"}, ErrLowContrast},
+		{"background close to text", "dark", models.ThemeOverrides{BackgroundColor: "#9ca3af"}, ErrLowContrast},
+		{"button text on button", "light", models.ThemeOverrides{ButtonColor: "#3b82f6"}, ErrLowContrast},
+		{"button text override", "ocean", models.ThemeOverrides{ButtonTextColor: "#38bdf8"}, ErrLowContrast},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := Resolve(tt.preset, tt.overrides)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Resolve(%q, %+v) = %+v, %v, want %v", tt.preset, tt.overrides, resolved, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContrastBoundary(t *testing.T) {
+	// #767676 на белом — самый светлый серый с контрастом не ниже 4.5:1
+	if _, err := Resolve("light", models.ThemeOverrides{TextColor: "#767676"}); err != nil {
+		t.Errorf("#767676 on white: %v, want accepted", err)
+	}
+	if _, err := Resolve("light", models.ThemeOverrides{TextColor: "#777777"}); !errors.Is(err, ErrLowContrast) {
+		t.Errorf("#777777 on white: %v, want %v", err, ErrLowContrast)
+	}
+}
+
+func TestCheckButtonColor(t *testing.T) {
+	light, err := Resolve("light", models.ThemeOverrides{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		color   string
+		want    string
+		wantErr error
+	}{
+		{"#1D4ED8", "#1d4ed8", nil},
+		{"000", "#000000", nil},
+		{"#fefefe", "", ErrLowContrast},
+		{"#60a5fa", "", ErrLowContrast},
+		{"blue", "", ErrInvalidTheme},
+		{"", "", ErrInvalidTheme},
+	}
+	for _, tt := range tests {
+		got, err := CheckButtonColor(light, tt.color)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("CheckButtonColor(%q) = %q, %v, want %q, %v", tt.color, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func contrast(t *testing.T, a, b string) float64 {
+	t.Helper()
+	ca, err := colors.Parse(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := colors.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return colors.Contrast(ca, cb)
+}
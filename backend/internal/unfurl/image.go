@@ -0,0 +1,76 @@
+package unfurl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Декодеры форматов изображений
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// MaxImagePixels ограничивает размер загружаемого изображения, чтобы
+// маленький сжатый файл не занял при декодировании много памяти
+const MaxImagePixels = 4096 * 4096
+
+// ErrImage возвращается, если по ссылке не изображение или оно слишком большое
+var ErrImage = errors.New("неподдерживаемое изображение")
+
+// FetchImage загружает и декодирует изображение PNG, JPEG или GIF.
+// Загрузка ограничена теми же размером и временем, что и для страниц
+func (c *Client) FetchImage(ctx context.Context, rawURL string) (image.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetch, err)
+	}
+	req.Header.Set("Accept", "image/png,image/jpeg,image/gif;q=0.9")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFetch, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%w: сервер ответил %d", ErrFetch, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFetch, err)
+	}
+	if int64(len(data)) > c.maxBodyBytes {
+		return nil, fmt.Errorf("%w: файл больше %d байт", ErrImage, c.maxBodyBytes)
+	}
+
+	return DecodeImage(data)
+}
+
+// DecodeImage декодирует изображение, предварительно проверяя его размеры
+func DecodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, fmt.Errorf("%w: размер %dx%d", ErrImage, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrImage
+	}
+	return img, nil
+}
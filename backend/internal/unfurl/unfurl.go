@@ -0,0 +1,281 @@
+// Package unfurl получает предпросмотр страницы по ссылке: заголовок,
+// описание, изображение og:image и иконку сайта. Загрузка ограничена по
+// размеру и времени, подключения к внутренним адресам запрещены
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"mvp_multylink/backend/internal/lrucache"
+	"mvp_multylink/backend/internal/urlpolicy"
+)
+
+// ErrFetch возвращается, если страницу не удалось загрузить
+var ErrFetch = errors.New("не удалось загрузить страницу")
+
+// Options задает параметры загрузки
+type Options struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+	MaxRedirects int
+	UserAgent    string
+	CacheTTL     time.Duration
+	CacheSize    int
+	// AllowPrivate разрешает подключение к внутренним адресам. Нужен для
+	// проверки на локальных серверах, например httptest
+	AllowPrivate bool
+	// DialContext подменяет установку соединений, например чтобы направить
+	// запросы к известному домену на локальный сервер в тестах
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Preview содержит данные предпросмотра страницы
+type Preview struct {
+	URL         string `json:"url"`
+	FinalURL    string `json:"final_url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// Client загружает страницы и кэширует предпросмотры по URL
+type Client struct {
+	client       *http.Client
+	maxBodyBytes int64
+	userAgent    string
+	cache        *lrucache.Cache[string, Preview]
+}
+
+// New создает новый экземпляр Client
+func New(opts Options) *Client {
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivate {
+		dialer.Control = urlpolicy.DialControl
+	}
+	dial := dialer.DialContext
+	if opts.DialContext != nil {
+		dial = opts.DialContext
+	}
+
+	maxRedirects := opts.MaxRedirects
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext:           dial,
+			TLSHandshakeTimeout:   opts.Timeout,
+			ResponseHeaderTimeout: opts.Timeout,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		Timeout: opts.Timeout,
+		CheckRedirect: func(_ *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return fmt.Errorf("более %d перенаправлений", maxRedirects)
+			}
+			return nil
+		},
+	}
+
+	return &Client{
+		client:       client,
+		maxBodyBytes: opts.MaxBodyBytes,
+		userAgent:    opts.UserAgent,
+		cache:        lrucache.New[string, Preview](opts.CacheSize, opts.CacheTTL),
+	}
+}
+
+// Unfurl возвращает предпросмотр страницы, используя кэш
+func (c *Client) Unfurl(ctx context.Context, rawURL string) (Preview, error) {
+	if preview, ok := c.cache.Get(rawURL); ok {
+		return preview, nil
+	}
+
+	preview, err := c.fetch(ctx, rawURL)
+	if err != nil {
+		return preview, err
+	}
+	c.cache.Put(rawURL, preview)
+	return preview, nil
+}
+
+func (c *Client) fetch(ctx context.Context, rawURL string) (Preview, error) {
+	preview := Preview{URL: rawURL}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return preview, fmt.Errorf("%w: %v", ErrFetch, err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return preview, fmt.Errorf("%w: %v", ErrFetch, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return preview, fmt.Errorf("%w: сервер ответил %d", ErrFetch, resp.StatusCode)
+	}
+
+	base := resp.Request.URL
+	preview.FinalURL = base.String()
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "" &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		// Не HTML: предпросмотр содержит только иконку сайта
+		preview.Icon = resolve(base, "/favicon.ico")
+		return preview, nil
+	}
+
+	// Кодировка определяется по BOM, заголовку Content-Type и тегам <meta>
+	body, err := charset.NewReader(io.LimitReader(resp.Body, c.maxBodyBytes), contentType)
+	if err != nil {
+		return preview, fmt.Errorf("%w: %v", ErrFetch, err)
+	}
+
+	meta := parseHead(body)
+	preview.Title = firstNonEmpty(meta.ogTitle, meta.title)
+	preview.Description = firstNonEmpty(meta.ogDescription, meta.description)
+	preview.SiteName = meta.ogSiteName
+	if meta.ogImage != "" {
+		preview.Image = resolve(base, meta.ogImage)
+	}
+	preview.Icon = resolve(base, firstNonEmpty(meta.icon, "/favicon.ico"))
+	return preview, nil
+}
+
+// headMeta содержит данные, извлеченные из <head> страницы
+type headMeta struct {
+	title         string
+	description   string
+	ogTitle       string
+	ogDescription string
+	ogImage       string
+	ogSiteName    string
+	icon          string
+}
+
+// parseHead разбирает документ до начала <body>. Ошибки разметки
+// не прерывают разбор: используется все, что удалось прочитать
+func parseHead(r io.Reader) headMeta {
+	var meta headMeta
+	var inTitle bool
+	var title strings.Builder
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			meta.title = cleanText(title.String())
+			return meta
+		case html.TextToken:
+			if inTitle {
+				title.Write(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "title" {
+				inTitle = false
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = title.Len() == 0
+			case "meta":
+				meta.applyMeta(attrs(token))
+			case "link":
+				meta.applyLink(attrs(token))
+			case "body":
+				meta.title = cleanText(title.String())
+				return meta
+			}
+		}
+	}
+}
+
+func (m *headMeta) applyMeta(a map[string]string) {
+	key := strings.ToLower(firstNonEmpty(a["property"], a["name"]))
+	content := cleanText(a["content"])
+	if content == "" {
+		return
+	}
+	switch key {
+	case "og:title":
+		m.ogTitle = firstNonEmpty(m.ogTitle, content)
+	case "og:description":
+		m.ogDescription = firstNonEmpty(m.ogDescription, content)
+	case "og:image", "og:image:url", "og:image:secure_url":
+		m.ogImage = firstNonEmpty(m.ogImage, content)
+	case "og:site_name":
+		m.ogSiteName = firstNonEmpty(m.ogSiteName, content)
+	case "description":
+		m.description = firstNonEmpty(m.description, content)
+	}
+}
+
+func (m *headMeta) applyLink(a map[string]string) {
+	if a["href"] == "" {
+		return
+	}
+	for _, rel := range strings.Fields(strings.ToLower(a["rel"])) {
+		switch rel {
+		case "icon", "apple-touch-icon":
+			// Предпочитаем обычную иконку, apple-touch-icon — если другой нет
+			if m.icon == "" || rel == "icon" {
+				m.icon = a["href"]
+			}
+			return
+		}
+	}
+}
+
+func attrs(token html.Token) map[string]string {
+	a := make(map[string]string, len(token.Attr))
+	for _, attr := range token.Attr {
+		a[strings.ToLower(attr.Key)] = attr.Val
+	}
+	return a
+}
+
+// resolve приводит ссылку к абсолютной относительно адреса страницы.
+// Ссылки со схемами, отличными от http(s), отбрасываются
+func resolve(base *url.URL, ref string) string {
+	u, err := base.Parse(strings.TrimSpace(ref))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
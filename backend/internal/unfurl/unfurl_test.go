@@ -0,0 +1,158 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func newTestClient(allowPrivate bool) *Client {
+	return New(Options{
+		Timeout:      time.Second,
+		MaxBodyBytes: 1 << 20,
+		MaxRedirects: 3,
+		UserAgent:    "unfurl-test",
+		CacheTTL:     time.Minute,
+		CacheSize:    16,
+		AllowPrivate: allowPrivate,
+	})
+}
+
+// serveHTML отвечает телом body с заголовком Content-Type contentType
+func serveHTML(t *testing.T, contentType string, body []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func encode1251(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := charmap.Windows1251.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatalf("encode windows-1251: %v", err)
+	}
+	return data
+}
+
+func TestUnfurlOpenGraph(t *testing.T) {
+	server := serveHTML(t, "text/html; charset=utf-8", []byte(`<!DOCTYPE html><html><head>
+<title>Запасной заголовок</title>
+<meta property="og:title" content="  Заголовок   OG ">
+<meta property="og:description" content="Описание OG">
+<meta name="description" content="Обычное описание">
+<meta property="og:image" content="/cover.png">
+<meta property="og:site_name" content="Пример">
+<link rel="apple-touch-icon" href="/touch.png">
+<link rel="icon" href="/favicon.svg">
+</head><body><title>Не заголовок</title></body></html>`))
+
+	preview, err := newTestClient(true).Unfurl(context.Background(), server.URL+"/page")
+	if err != nil {
+		t.Fatalf("Unfurl: %v", err)
+	}
+
+	want := Preview{
+		URL:         server.URL + "/page",
+		FinalURL:    server.URL + "/page",
+		Title:       "Заголовок OG",
+		Description: "Описание OG",
+		Image:       server.URL + "/cover.png",
+		Icon:        server.URL + "/favicon.svg",
+		SiteName:    "Пример",
+	}
+	if preview != want {
+		t.Errorf("preview = %+v\nwant %+v", preview, want)
+	}
+}
+
+func TestUnfurlTitleFallback(t *testing.T) {
+	server := serveHTML(t, "text/html", []byte(`<html><head><title>
+  Только   title
+</title><meta name="description" content="Описание"></head><body></body></html>`))
+
+	preview, err := newTestClient(true).Unfurl(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Unfurl: %v", err)
+	}
+	if preview.Title != "Только title" {
+		t.Errorf("title = %q, want %q", preview.Title, "Только title")
+	}
+	if preview.Description != "Описание" {
+		t.Errorf("description = %q, want %q", preview.Description, "Описание")
+	}
+	if preview.Icon != server.URL+"/favicon.ico" {
+		t.Errorf("icon = %q, want default favicon", preview.Icon)
+	}
+}
+
+func TestUnfurlCharset(t *testing.T) {
+	page := `<html><head><meta charset="windows-1251"><title>Привет, мир</title></head><body></body></html>`
+
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{"charset in header", "text/html; charset=windows-1251"},
+		{"charset in meta tag", "text/html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := serveHTML(t, tt.contentType, encode1251(t, page))
+			preview, err := newTestClient(true).Unfurl(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("Unfurl: %v", err)
+			}
+			if preview.Title != "Привет, мир" {
+				t.Errorf("title = %q, want %q", preview.Title, "Привет, мир")
+			}
+		})
+	}
+}
+
+func TestUnfurlNonHTML(t *testing.T) {
+	server := serveHTML(t, "application/pdf", []byte("%PDF-1.4"))
+
+	preview, err := newTestClient(true).Unfurl(context.Background(), server.URL+"/doc.pdf")
+	if err != nil {
+		t.Fatalf("Unfurl: %v", err)
+	}
+	if preview.Title != "" || preview.Icon != server.URL+"/favicon.ico" {
+		t.Errorf("preview = %+v, want only the site icon", preview)
+	}
+}
+
+func TestUnfurlErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := newTestClient(true).Unfurl(context.Background(), server.URL)
+	if !errors.Is(err, ErrFetch) {
+		t.Errorf("error = %v, want ErrFetch", err)
+	}
+}
+
+func TestUnfurlRefusesPrivateAddresses(t *testing.T) {
+	var requested bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requested = true
+		_, _ = w.Write([]byte("<title>internal</title>"))
+	}))
+	defer server.Close()
+
+	_, err := newTestClient(false).Unfurl(context.Background(), server.URL)
+	if !errors.Is(err, ErrFetch) {
+		t.Errorf("error = %v, want ErrFetch", err)
+	}
+	if requested {
+		t.Error("request reached a loopback server")
+	}
+}
@@ -0,0 +1,73 @@
+package urlpolicy
+
+import (
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+// scripts перечисляет письменности, смешение которых в одной метке домена
+// считается признаком омографа (например, латинская «a» и кириллическая «а»)
+var scripts = []*unicode.RangeTable{
+	unicode.Latin,
+	unicode.Cyrillic,
+	unicode.Greek,
+	unicode.Armenian,
+	unicode.Georgian,
+	unicode.Hebrew,
+	unicode.Arabic,
+	unicode.Han,
+	unicode.Hiragana,
+	unicode.Katakana,
+	unicode.Hangul,
+	unicode.Thai,
+}
+
+// idnProfile приводит домен к Unicode с проверками, принятыми для поиска имен
+var idnProfile = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.Transitional(false))
+
+// checkIDN проверяет интернационализированное имя хоста: punycode должен
+// корректно декодироваться, а каждая метка — использовать одну письменность.
+// Японские слоговые азбуки допускаются вместе с иероглифами
+func checkIDN(host string) error {
+	unicodeHost, err := idnProfile.ToUnicode(host)
+	if err != nil {
+		return &Violation{CodeInvalidIDN, "некорректное интернационализированное доменное имя"}
+	}
+
+	label := make(map[*unicode.RangeTable]bool)
+	for _, r := range unicodeHost + "." {
+		if r == '.' {
+			if mixedScripts(label) {
+				return &Violation{CodeHomograph, "доменное имя смешивает символы разных алфавитов"}
+			}
+			clear(label)
+			continue
+		}
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		for _, script := range scripts {
+			if unicode.Is(script, r) {
+				label[script] = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func mixedScripts(label map[*unicode.RangeTable]bool) bool {
+	count, japanese := 0, false
+	for script := range label {
+		if script == unicode.Han || script == unicode.Hiragana || script == unicode.Katakana {
+			japanese = true
+			continue
+		}
+		count++
+	}
+	if japanese {
+		count++
+	}
+	return count > 1
+}
@@ -0,0 +1,162 @@
+// Package urlpolicy проверяет исходящие ссылки кнопок: схему, длину, хост
+// и интернационализированные доменные имена
+package urlpolicy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Коды нарушений политики, возвращаемые клиенту в поле code
+const (
+	CodeInvalidURL       = "invalid_url"
+	CodeTooLong          = "url_too_long"
+	CodeSchemeNotAllowed = "scheme_not_allowed"
+	CodeMissingHost      = "missing_host"
+	CodePrivateAddress   = "private_address"
+	CodeHomograph        = "idn_homograph"
+	CodeInvalidIDN       = "invalid_idn"
+	CodeCredentials      = "credentials_in_url"
+)
+
+// Violation описывает нарушение политики ссылок
+type Violation struct {
+	Code    string
+	Message string
+}
+
+// Error реализует интерфейс error
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Code, v.Message)
+}
+
+// hostSchemes перечисляет схемы, для которых ссылка должна содержать хост
+var hostSchemes = map[string]bool{"http": true, "https": true}
+
+// Policy задает правила для исходящих ссылок
+type Policy struct {
+	schemes   map[string]bool
+	maxLength int
+}
+
+// New создает новый экземпляр Policy
+func New(allowedSchemes []string, maxLength int) *Policy {
+	schemes := make(map[string]bool, len(allowedSchemes))
+	for _, scheme := range allowedSchemes {
+		schemes[strings.ToLower(scheme)] = true
+	}
+	return &Policy{schemes: schemes, maxLength: maxLength}
+}
+
+// Check проверяет ссылку и возвращает *Violation, если она нарушает политику
+func (p *Policy) Check(raw string) error {
+	if p.maxLength > 0 && len(raw) > p.maxLength {
+		return &Violation{CodeTooLong, fmt.Sprintf("длина ссылки превышает %d символов", p.maxLength)}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return &Violation{CodeInvalidURL, "неверный формат ссылки"}
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !p.schemes[scheme] {
+		return &Violation{CodeSchemeNotAllowed, fmt.Sprintf("схема %q не разрешена", u.Scheme)}
+	}
+	if !hostSchemes[scheme] {
+		return nil
+	}
+
+	if u.User != nil {
+		return &Violation{CodeCredentials, "ссылка не должна содержать имя пользователя или пароль"}
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return &Violation{CodeMissingHost, "в ссылке не указан хост"}
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return &Violation{CodePrivateAddress, "ссылки на локальные адреса запрещены"}
+	}
+	if addr, ok := parseIP(host); ok {
+		if IsPrivateAddr(addr) {
+			return &Violation{CodePrivateAddress, "ссылки на внутренние и локальные IP-адреса запрещены"}
+		}
+		return nil
+	}
+
+	return checkIDN(host)
+}
+
+// parseIP разбирает IP-литерал, включая формы IPv4, которые браузеры
+// принимают помимо десятичной с точками: 2130706433, 0x7f.1, 0177.0.0.1
+func parseIP(host string) (netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.Unmap(), true
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return netip.Addr{}, false
+	}
+	values := make([]uint64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseUint(part, 0, 32)
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		values[i] = value
+	}
+
+	// Последняя часть заполняет все оставшиеся байты адреса
+	var ip uint64
+	for i, value := range values[:len(values)-1] {
+		if value > 0xff {
+			return netip.Addr{}, false
+		}
+		ip |= value << (24 - 8*i)
+	}
+	last := values[len(values)-1]
+	if last >= 1<<(8*(5-len(values))) {
+		return netip.Addr{}, false
+	}
+	ip |= last
+
+	return netip.AddrFrom4([4]byte{byte(ip >> 24), byte(ip >> 16), byte(ip >> 8), byte(ip)}), true
+}
+
+// IsPrivateAddr проверяет, что адрес относится к внутренней сети, loopback,
+// link-local или multicast и недоступен как внешний ресурс
+func IsPrivateAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// ErrPrivateAddress возвращается DialControl при попытке подключиться к внутреннему адресу
+var ErrPrivateAddress = errors.New("подключение к внутреннему адресу запрещено")
+
+// DialControl используется как net.Dialer.Control для исходящих запросов
+// к ссылкам пользователей. Адрес проверяется после разрешения имени, поэтому
+// DNS-записи, указывающие во внутреннюю сеть, тоже блокируются
+func DialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && IsPrivateAddr(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// sharedAddressSpace — диапазон CGNAT (RFC 6598), не покрываемый IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")